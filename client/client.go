@@ -14,6 +14,7 @@ type Client interface {
 	CreateChannel(channel *model.Channel) (*model.Channel, *model.Response)
 	RemoveUserFromChannel(channelId, userId string) (bool, *model.Response)
 	GetChannelMembers(channelId string, page, perPage int, etag string) (*model.ChannelMembers, *model.Response)
+	GetChannelMember(channelId, userId, etag string) (*model.ChannelMember, *model.Response)
 	AddChannelMember(channelId, userId string) (*model.ChannelMember, *model.Response)
 	DeleteChannel(channelId string) (bool, *model.Response)
 	PermanentDeleteChannel(channelId string) (bool, *model.Response)
@@ -32,6 +33,7 @@ type Client interface {
 	GetAllTeams(etag string, page int, perPage int) ([]*model.Team, *model.Response)
 	CreateTeam(team *model.Team) (*model.Team, *model.Response)
 	PatchTeam(teamId string, patch *model.TeamPatch) (*model.Team, *model.Response)
+	GetTeamMember(teamId, userId, etag string) (*model.TeamMember, *model.Response)
 	AddTeamMember(teamId, userId string) (*model.TeamMember, *model.Response)
 	RemoveTeamMember(teamId, userId string) (bool, *model.Response)
 	SoftDeleteTeam(teamId string) (bool, *model.Response)
@@ -51,6 +53,7 @@ type Client interface {
 	RemoveLicenseFile() (bool, *model.Response)
 	GetLogs(page, perPage int) ([]string, *model.Response)
 	GetRoleByName(name string) (*model.Role, *model.Response)
+	GetRolesByNames(roleNames []string) ([]*model.Role, *model.Response)
 	PatchRole(roleId string, patch *model.RolePatch) (*model.Role, *model.Response)
 	UploadPlugin(file io.Reader) (*model.Manifest, *model.Response)
 	RemovePlugin(id string) (bool, *model.Response)
@@ -144,4 +147,13 @@ type Client interface {
 	DeleteExport(name string) (bool, *model.Response)
 	DownloadExport(name string, wr io.Writer, offset int64) (int64, *model.Response)
 	ResetSamlAuthDataToEmail(includeDeleted bool, dryRun bool, userIDs []string) (int64, *model.Response)
+	CreateScheme(scheme *model.Scheme) (*model.Scheme, *model.Response)
+	GetScheme(id string) (*model.Scheme, *model.Response)
+	GetSchemes(scope string, page int, perPage int) ([]*model.Scheme, *model.Response)
+	PatchScheme(id string, patch *model.SchemePatch) (*model.Scheme, *model.Response)
+	DeleteScheme(id string) (bool, *model.Response)
+	GetTeamsForScheme(schemeId string, page int, perPage int) ([]*model.Team, *model.Response)
+	GetChannelsForScheme(schemeId string, page int, perPage int) (model.ChannelList, *model.Response)
+	UpdateTeamScheme(teamId, schemeId string) (bool, *model.Response)
+	UpdateChannelScheme(channelId, schemeId string) (bool, *model.Response)
 }
@@ -0,0 +1,534 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/mattermost/mmctl/client"
+	"github.com/mattermost/mmctl/printer"
+)
+
+var ApplyCmd = &cobra.Command{
+	Use:   "apply -f [manifest]",
+	Short: "Apply a desired state manifest",
+	Long: `Reads a manifest describing the desired state of teams, channels and their members and reconciles the server with it.
+A plan with the changes to be performed is printed first, and only the differences between the manifest and the server are applied.
+Members present in the server but missing from the manifest are not removed.`,
+	Example: `  # apply the manifest asking for confirmation after showing the plan
+  $ mmctl apply -f state.yaml
+
+  # apply the manifest read from the standard input without confirmation
+  $ cat state.yaml | mmctl apply -f - --confirm
+
+  # a manifest looks like this
+  teams:
+    - name: myteam
+      display_name: My Team
+      private: false
+      members: [john.doe, jane.doe]
+      channels:
+        - name: mychannel
+          display_name: My Channel
+          private: true
+          header: Channel header
+          purpose: Channel purpose
+          members: [john.doe]
+        - name: oldchannel
+          archived: true`,
+	Args: cobra.NoArgs,
+	RunE: withClient(applyCmdF),
+}
+
+func init() {
+	ApplyCmd.Flags().StringP("file", "f", "", "Path to the manifest file, or - to read it from the standard input")
+	_ = ApplyCmd.MarkFlagRequired("file")
+	ApplyCmd.Flags().Bool("confirm", false, "Confirm you really want to apply the plan without being prompted")
+
+	RootCmd.AddCommand(ApplyCmd)
+}
+
+// ApplyManifest is the desired state of the server read by the apply
+// command
+type ApplyManifest struct {
+	Teams []*ApplyTeam `yaml:"teams"`
+}
+
+// ApplyTeam is the desired state of a team. Optional properties are
+// only reconciled when they are present in the manifest
+type ApplyTeam struct {
+	Name        string          `yaml:"name"`
+	DisplayName string          `yaml:"display_name"`
+	Description *string         `yaml:"description"`
+	Private     *bool           `yaml:"private"`
+	Archived    *bool           `yaml:"archived"`
+	Members     []string        `yaml:"members"`
+	Channels    []*ApplyChannel `yaml:"channels"`
+}
+
+// ApplyChannel is the desired state of a channel inside a team
+type ApplyChannel struct {
+	Name        string   `yaml:"name"`
+	DisplayName string   `yaml:"display_name"`
+	Header      *string  `yaml:"header"`
+	Purpose     *string  `yaml:"purpose"`
+	Private     *bool    `yaml:"private"`
+	Archived    *bool    `yaml:"archived"`
+	Members     []string `yaml:"members"`
+}
+
+// applyAction is a single change of the plan that reconciles the
+// server with the manifest
+type applyAction struct {
+	Action string `json:"action"`
+	Target string `json:"target"`
+	Detail string `json:"detail,omitempty"`
+
+	run func(c client.Client) error
+}
+
+func readApplyManifest(path string) (*ApplyManifest, error) {
+	var b []byte
+	var err error
+	if path == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read manifest")
+	}
+
+	var manifest ApplyManifest
+	if err := yaml.UnmarshalStrict(b, &manifest); err != nil {
+		return nil, errors.Wrap(err, "could not parse manifest")
+	}
+
+	if err := manifest.validate(); err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+func (m *ApplyManifest) validate() error {
+	teamNames := map[string]bool{}
+	for i, team := range m.Teams {
+		if team.Name == "" {
+			return errors.Errorf("team at position %d has no name", i)
+		}
+		if teamNames[team.Name] {
+			return errors.Errorf("team %q is defined more than once", team.Name)
+		}
+		teamNames[team.Name] = true
+
+		channelNames := map[string]bool{}
+		for j, channel := range team.Channels {
+			if channel.Name == "" {
+				return errors.Errorf("channel at position %d of team %q has no name", j, team.Name)
+			}
+			if channelNames[channel.Name] {
+				return errors.Errorf("channel %q is defined more than once in team %q", channel.Name, team.Name)
+			}
+			channelNames[channel.Name] = true
+		}
+	}
+	return nil
+}
+
+func applyCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("file")
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+
+	manifest, err := readApplyManifest(path)
+	if err != nil {
+		return err
+	}
+
+	actions, err := planManifest(c, manifest)
+	if err != nil {
+		return err
+	}
+
+	if len(actions) == 0 {
+		printer.Print("The server is already in the desired state")
+		return nil
+	}
+
+	for _, action := range actions {
+		printer.PrintT("{{.Action}} {{.Target}}{{if .Detail}} ({{.Detail}}){{end}}", action)
+	}
+
+	if !confirmFlag {
+		var confirm string
+		fmt.Printf("Do you want to apply %d changes? (YES/NO): ", len(actions))
+		fmt.Scanln(&confirm)
+		if confirm != "YES" {
+			return errors.New("aborted: You did not answer YES exactly, in all capitals")
+		}
+	}
+
+	failed := 0
+	for _, action := range actions {
+		if err := action.run(c); err != nil {
+			printItemError(fmt.Sprintf("Unable to %s %s: %s", action.Action, action.Target, err), err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d actions failed", failed, len(actions))
+	}
+	return nil
+}
+
+// planManifest compares the manifest with the state of the server and
+// returns the ordered list of actions needed to reconcile them
+func planManifest(c client.Client, manifest *ApplyManifest) ([]*applyAction, error) {
+	users := map[string]*model.User{}
+	getUser := func(userArg string) (*model.User, error) {
+		if user, ok := users[userArg]; ok {
+			return user, nil
+		}
+		user := getUserFromUserArg(c, userArg)
+		if user == nil {
+			return nil, errors.Errorf("unable to find user %q", userArg)
+		}
+		users[userArg] = user
+		return user, nil
+	}
+
+	actions := []*applyAction{}
+	for _, desiredTeam := range manifest.Teams {
+		teamActions, err := planTeam(c, desiredTeam, getUser)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, teamActions...)
+	}
+	return actions, nil
+}
+
+func planTeam(c client.Client, desired *ApplyTeam, getUser func(string) (*model.User, error)) ([]*applyAction, error) {
+	actions := []*applyAction{}
+	target := "team " + desired.Name
+
+	team := getTeamFromTeamArg(c, desired.Name)
+	exists := team != nil
+	if !exists {
+		if desired.Archived != nil && *desired.Archived {
+			return actions, nil
+		}
+		if desired.DisplayName == "" {
+			return nil, errors.Errorf("team %q does not exist and has no display_name to create it", desired.Name)
+		}
+		team = &model.Team{Name: desired.Name}
+		newTeam := &model.Team{
+			Name:        desired.Name,
+			DisplayName: desired.DisplayName,
+			Type:        model.TEAM_OPEN,
+		}
+		if desired.Description != nil {
+			newTeam.Description = *desired.Description
+		}
+		if desired.Private != nil && *desired.Private {
+			newTeam.Type = model.TEAM_INVITE
+		}
+		actions = append(actions, &applyAction{
+			Action: "create",
+			Target: target,
+			run: func(c client.Client) error {
+				createdTeam, response := c.CreateTeam(newTeam)
+				if response.Error != nil {
+					return response.Error
+				}
+				*team = *createdTeam
+				return nil
+			},
+		})
+	} else {
+		patch := &model.TeamPatch{}
+		var changes []string
+		if desired.DisplayName != "" && desired.DisplayName != team.DisplayName {
+			patch.DisplayName = model.NewString(desired.DisplayName)
+			changes = append(changes, "display_name")
+		}
+		if desired.Description != nil && *desired.Description != team.Description {
+			patch.Description = desired.Description
+			changes = append(changes, "description")
+		}
+		if len(changes) > 0 {
+			actions = append(actions, &applyAction{
+				Action: "patch",
+				Target: target,
+				Detail: strings.Join(changes, ", "),
+				run: func(c client.Client) error {
+					_, response := c.PatchTeam(team.Id, patch)
+					if response.Error != nil {
+						return response.Error
+					}
+					return nil
+				},
+			})
+		}
+
+		if desired.Private != nil && *desired.Private != (team.Type == model.TEAM_INVITE) {
+			privacy := model.TEAM_OPEN
+			if *desired.Private {
+				privacy = model.TEAM_INVITE
+			}
+			actions = append(actions, &applyAction{
+				Action: "update privacy of",
+				Target: target,
+				Detail: privacyDescription(*desired.Private),
+				run: func(c client.Client) error {
+					_, response := c.UpdateTeamPrivacy(team.Id, privacy)
+					if response.Error != nil {
+						return response.Error
+					}
+					return nil
+				},
+			})
+		}
+
+		if desired.Archived != nil && !*desired.Archived && team.DeleteAt > 0 {
+			actions = append(actions, &applyAction{
+				Action: "restore",
+				Target: target,
+				run: func(c client.Client) error {
+					_, response := c.RestoreTeam(team.Id)
+					if response.Error != nil {
+						return response.Error
+					}
+					return nil
+				},
+			})
+		}
+	}
+
+	for _, member := range desired.Members {
+		user, err := getUser(member)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			if teamMember, _ := c.GetTeamMember(team.Id, user.Id, ""); teamMember != nil && teamMember.DeleteAt == 0 {
+				continue
+			}
+		}
+		actions = append(actions, &applyAction{
+			Action: "add member to",
+			Target: target,
+			Detail: member,
+			run: func(c client.Client) error {
+				if team.Id == "" {
+					return errors.New("the team was not created")
+				}
+				_, response := c.AddTeamMember(team.Id, user.Id)
+				if response.Error != nil {
+					return response.Error
+				}
+				return nil
+			},
+		})
+	}
+
+	for _, desiredChannel := range desired.Channels {
+		channelActions, err := planChannel(c, team, exists, desiredChannel, getUser)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, channelActions...)
+	}
+
+	if desired.Archived != nil && *desired.Archived && exists && team.DeleteAt == 0 {
+		actions = append(actions, &applyAction{
+			Action: "archive",
+			Target: target,
+			run: func(c client.Client) error {
+				_, response := c.SoftDeleteTeam(team.Id)
+				if response.Error != nil {
+					return response.Error
+				}
+				return nil
+			},
+		})
+	}
+
+	return actions, nil
+}
+
+// planChannel plans the changes of a channel. The team can be pending
+// of creation, in which case it will be populated by the time the
+// actions run
+func planChannel(c client.Client, team *model.Team, teamExists bool, desired *ApplyChannel, getUser func(string) (*model.User, error)) ([]*applyAction, error) {
+	actions := []*applyAction{}
+	target := "channel " + team.Name + channelArgSeparator + desired.Name
+
+	var channel *model.Channel
+	if teamExists {
+		channel = getChannelFromChannelArg(c, team.Name+channelArgSeparator+desired.Name)
+	}
+	exists := channel != nil
+
+	if !exists {
+		if desired.Archived != nil && *desired.Archived {
+			return actions, nil
+		}
+		if desired.DisplayName == "" {
+			return nil, errors.Errorf("channel %q does not exist and has no display_name to create it", desired.Name)
+		}
+		channel = &model.Channel{}
+		newChannel := &model.Channel{
+			Name:        desired.Name,
+			DisplayName: desired.DisplayName,
+			Type:        model.CHANNEL_OPEN,
+		}
+		if desired.Header != nil {
+			newChannel.Header = *desired.Header
+		}
+		if desired.Purpose != nil {
+			newChannel.Purpose = *desired.Purpose
+		}
+		if desired.Private != nil && *desired.Private {
+			newChannel.Type = model.CHANNEL_PRIVATE
+		}
+		actions = append(actions, &applyAction{
+			Action: "create",
+			Target: target,
+			run: func(c client.Client) error {
+				if team.Id == "" {
+					return errors.New("the team was not created")
+				}
+				newChannel.TeamId = team.Id
+				createdChannel, response := c.CreateChannel(newChannel)
+				if response.Error != nil {
+					return response.Error
+				}
+				*channel = *createdChannel
+				return nil
+			},
+		})
+	} else {
+		if desired.Archived != nil && !*desired.Archived && channel.DeleteAt > 0 {
+			actions = append(actions, &applyAction{
+				Action: "unarchive",
+				Target: target,
+				run: func(c client.Client) error {
+					_, response := c.RestoreChannel(channel.Id)
+					if response.Error != nil {
+						return response.Error
+					}
+					return nil
+				},
+			})
+		}
+
+		patch := &model.ChannelPatch{}
+		var changes []string
+		if desired.DisplayName != "" && desired.DisplayName != channel.DisplayName {
+			patch.DisplayName = model.NewString(desired.DisplayName)
+			changes = append(changes, "display_name")
+		}
+		if desired.Header != nil && *desired.Header != channel.Header {
+			patch.Header = desired.Header
+			changes = append(changes, "header")
+		}
+		if desired.Purpose != nil && *desired.Purpose != channel.Purpose {
+			patch.Purpose = desired.Purpose
+			changes = append(changes, "purpose")
+		}
+		if len(changes) > 0 {
+			actions = append(actions, &applyAction{
+				Action: "patch",
+				Target: target,
+				Detail: strings.Join(changes, ", "),
+				run: func(c client.Client) error {
+					_, response := c.PatchChannel(channel.Id, patch)
+					if response.Error != nil {
+						return response.Error
+					}
+					return nil
+				},
+			})
+		}
+
+		if desired.Private != nil && *desired.Private != (channel.Type == model.CHANNEL_PRIVATE) {
+			privacy := model.CHANNEL_OPEN
+			if *desired.Private {
+				privacy = model.CHANNEL_PRIVATE
+			}
+			actions = append(actions, &applyAction{
+				Action: "update privacy of",
+				Target: target,
+				Detail: privacyDescription(*desired.Private),
+				run: func(c client.Client) error {
+					_, response := c.UpdateChannelPrivacy(channel.Id, privacy)
+					if response.Error != nil {
+						return response.Error
+					}
+					return nil
+				},
+			})
+		}
+	}
+
+	for _, member := range desired.Members {
+		user, err := getUser(member)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			if channelMember, _ := c.GetChannelMember(channel.Id, user.Id, ""); channelMember != nil {
+				continue
+			}
+		}
+		actions = append(actions, &applyAction{
+			Action: "add member to",
+			Target: target,
+			Detail: member,
+			run: func(c client.Client) error {
+				if channel.Id == "" {
+					return errors.New("the channel was not created")
+				}
+				_, response := c.AddChannelMember(channel.Id, user.Id)
+				if response.Error != nil {
+					return response.Error
+				}
+				return nil
+			},
+		})
+	}
+
+	if desired.Archived != nil && *desired.Archived && exists && channel.DeleteAt == 0 {
+		actions = append(actions, &applyAction{
+			Action: "archive",
+			Target: target,
+			run: func(c client.Client) error {
+				_, response := c.DeleteChannel(channel.Id)
+				if response.Error != nil {
+					return response.Error
+				}
+				return nil
+			},
+		})
+	}
+
+	return actions, nil
+}
+
+func privacyDescription(private bool) string {
+	if private {
+		return "private"
+	}
+	return "public"
+}
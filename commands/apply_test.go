@@ -0,0 +1,236 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+func (s *MmctlUnitTestSuite) writeManifest(contents string) string {
+	file, err := ioutil.TempFile(os.TempDir(), "mmctl-manifest-*.yaml")
+	s.Require().NoError(err)
+	defer file.Close()
+
+	_, err = file.WriteString(contents)
+	s.Require().NoError(err)
+	return file.Name()
+}
+
+func (s *MmctlUnitTestSuite) TestApplyCmd() {
+	s.Run("should fail with an invalid manifest", func() {
+		printer.Clean()
+		path := s.writeManifest("teams:\n  - display_name: No Name\n")
+		defer os.Remove(path)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("file", path, "")
+		cmd.Flags().Bool("confirm", true, "")
+
+		err := applyCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "team at position 0 has no name")
+	})
+
+	s.Run("should fail with unknown fields", func() {
+		printer.Clean()
+		path := s.writeManifest("teams:\n  - name: myteam\n    unknown: true\n")
+		defer os.Remove(path)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("file", path, "")
+		cmd.Flags().Bool("confirm", true, "")
+
+		err := applyCmdF(s.client, cmd, []string{})
+		s.Require().Error(err)
+		s.Require().Contains(err.Error(), "could not parse manifest")
+	})
+
+	s.Run("should do nothing if the server is in the desired state", func() {
+		printer.Clean()
+		path := s.writeManifest(`teams:
+  - name: myteam
+    display_name: My Team
+    private: false
+    members: [john.doe]
+    channels:
+      - name: mychannel
+        display_name: My Channel
+        header: header
+`)
+		defer os.Remove(path)
+
+		mockTeam := &model.Team{Id: teamID, Name: "myteam", DisplayName: "My Team", Type: model.TEAM_OPEN}
+		mockChannel := &model.Channel{Id: channelID, Name: "mychannel", DisplayName: "My Channel", Header: "header", Type: model.CHANNEL_OPEN}
+		mockUser := &model.User{Id: userID, Username: "john.doe"}
+
+		s.client.EXPECT().GetTeam("myteam", "").Return(nil, &model.Response{}).Times(2)
+		s.client.EXPECT().GetTeamByName("myteam", "").Return(mockTeam, &model.Response{}).Times(2)
+		s.client.EXPECT().GetUserByEmail("john.doe", "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().GetUserByUsername("john.doe", "").Return(mockUser, &model.Response{}).Times(1)
+		s.client.EXPECT().GetTeamMember(teamID, userID, "").Return(&model.TeamMember{TeamId: teamID, UserId: userID}, &model.Response{}).Times(1)
+		s.client.EXPECT().GetChannelByNameIncludeDeleted("mychannel", teamID, "").Return(mockChannel, &model.Response{}).Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("file", path, "")
+		cmd.Flags().Bool("confirm", true, "")
+
+		err := applyCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal("The server is already in the desired state", printer.GetLines()[0])
+		s.Require().Empty(printer.GetErrorLines())
+	})
+
+	s.Run("should create and patch the entities that differ", func() {
+		printer.Clean()
+		path := s.writeManifest(`teams:
+  - name: newteam
+    display_name: New Team
+    private: true
+    members: [john.doe]
+    channels:
+      - name: newchannel
+        display_name: New Channel
+        members: [john.doe]
+  - name: myteam
+    display_name: Renamed Team
+    channels:
+      - name: mychannel
+        purpose: new purpose
+        private: true
+      - name: oldchannel
+        archived: true
+`)
+		defer os.Remove(path)
+
+		mockTeam := &model.Team{Id: teamID, Name: "myteam", DisplayName: "My Team", Type: model.TEAM_OPEN}
+		mockChannel := &model.Channel{Id: channelID, Name: "mychannel", DisplayName: "My Channel", Type: model.CHANNEL_OPEN}
+		mockOldChannel := &model.Channel{Id: "oldChannelID", Name: "oldchannel", DisplayName: "Old Channel", Type: model.CHANNEL_OPEN}
+		mockUser := &model.User{Id: userID, Username: "john.doe"}
+		createdTeam := &model.Team{Id: "newTeamID", Name: "newteam", DisplayName: "New Team", Type: model.TEAM_INVITE}
+		createdChannel := &model.Channel{Id: "newChannelID", TeamId: "newTeamID", Name: "newchannel", DisplayName: "New Channel", Type: model.CHANNEL_OPEN}
+
+		s.client.EXPECT().GetTeam("newteam", "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().GetTeamByName("newteam", "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().GetUserByEmail("john.doe", "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().GetUserByUsername("john.doe", "").Return(mockUser, &model.Response{}).Times(1)
+		s.client.EXPECT().GetTeam("myteam", "").Return(mockTeam, &model.Response{}).Times(3)
+		s.client.EXPECT().GetChannelByNameIncludeDeleted("mychannel", teamID, "").Return(mockChannel, &model.Response{}).Times(1)
+		s.client.EXPECT().GetChannelByNameIncludeDeleted("oldchannel", teamID, "").Return(mockOldChannel, &model.Response{}).Times(1)
+
+		s.client.EXPECT().
+			CreateTeam(&model.Team{Name: "newteam", DisplayName: "New Team", Type: model.TEAM_INVITE}).
+			Return(createdTeam, &model.Response{}).
+			Times(1)
+		s.client.EXPECT().AddTeamMember("newTeamID", userID).Return(&model.TeamMember{}, &model.Response{}).Times(1)
+		s.client.EXPECT().
+			CreateChannel(&model.Channel{TeamId: "newTeamID", Name: "newchannel", DisplayName: "New Channel", Type: model.CHANNEL_OPEN}).
+			Return(createdChannel, &model.Response{}).
+			Times(1)
+		s.client.EXPECT().AddChannelMember("newChannelID", userID).Return(&model.ChannelMember{}, &model.Response{}).Times(1)
+		s.client.EXPECT().
+			PatchTeam(teamID, &model.TeamPatch{DisplayName: model.NewString("Renamed Team")}).
+			Return(mockTeam, &model.Response{}).
+			Times(1)
+		s.client.EXPECT().
+			PatchChannel(channelID, &model.ChannelPatch{Purpose: model.NewString("new purpose")}).
+			Return(mockChannel, &model.Response{}).
+			Times(1)
+		s.client.EXPECT().UpdateChannelPrivacy(channelID, model.CHANNEL_PRIVATE).Return(mockChannel, &model.Response{}).Times(1)
+		s.client.EXPECT().DeleteChannel("oldChannelID").Return(true, &model.Response{}).Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("file", path, "")
+		cmd.Flags().Bool("confirm", true, "")
+
+		err := applyCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 8)
+		s.Require().Equal(&applyAction{Action: "create", Target: "team newteam"}, stripApplyAction(printer.GetLines()[0]))
+		s.Require().Equal(&applyAction{Action: "add member to", Target: "team newteam", Detail: "john.doe"}, stripApplyAction(printer.GetLines()[1]))
+		s.Require().Equal(&applyAction{Action: "create", Target: "channel newteam:newchannel"}, stripApplyAction(printer.GetLines()[2]))
+		s.Require().Equal(&applyAction{Action: "add member to", Target: "channel newteam:newchannel", Detail: "john.doe"}, stripApplyAction(printer.GetLines()[3]))
+		s.Require().Equal(&applyAction{Action: "patch", Target: "team myteam", Detail: "display_name"}, stripApplyAction(printer.GetLines()[4]))
+		s.Require().Equal(&applyAction{Action: "patch", Target: "channel myteam:mychannel", Detail: "purpose"}, stripApplyAction(printer.GetLines()[5]))
+		s.Require().Equal(&applyAction{Action: "update privacy of", Target: "channel myteam:mychannel", Detail: "private"}, stripApplyAction(printer.GetLines()[6]))
+		s.Require().Equal(&applyAction{Action: "archive", Target: "channel myteam:oldchannel"}, stripApplyAction(printer.GetLines()[7]))
+		s.Require().Empty(printer.GetErrorLines())
+	})
+
+	s.Run("should not create the archived entities that don't exist", func() {
+		printer.Clean()
+		path := s.writeManifest(`teams:
+  - name: oldteam
+    archived: true
+    channels:
+      - name: oldchannel
+        display_name: Old Channel
+  - name: myteam
+    channels:
+      - name: oldchannel
+        archived: true
+`)
+		defer os.Remove(path)
+
+		mockTeam := &model.Team{Id: teamID, Name: "myteam", DisplayName: "My Team", Type: model.TEAM_OPEN}
+
+		s.client.EXPECT().GetTeam("oldteam", "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().GetTeamByName("oldteam", "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().GetTeam("myteam", "").Return(mockTeam, &model.Response{}).Times(2)
+		s.client.EXPECT().GetChannelByNameIncludeDeleted("oldchannel", teamID, "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().GetChannel("oldchannel", "").Return(nil, &model.Response{}).Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("file", path, "")
+		cmd.Flags().Bool("confirm", true, "")
+
+		err := applyCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal("The server is already in the desired state", printer.GetLines()[0])
+		s.Require().Empty(printer.GetErrorLines())
+	})
+
+	s.Run("should report the actions that fail", func() {
+		printer.Clean()
+		path := s.writeManifest(`teams:
+  - name: newteam
+    display_name: New Team
+    channels:
+      - name: newchannel
+        display_name: New Channel
+`)
+		defer os.Remove(path)
+
+		s.client.EXPECT().GetTeam("newteam", "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().GetTeamByName("newteam", "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().
+			CreateTeam(&model.Team{Name: "newteam", DisplayName: "New Team", Type: model.TEAM_OPEN}).
+			Return(nil, &model.Response{Error: &model.AppError{Message: "team creation error"}}).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("file", path, "")
+		cmd.Flags().Bool("confirm", true, "")
+
+		err := applyCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "2 of 2 actions failed")
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Len(printer.GetErrorLines(), 2)
+		s.Require().Equal("Unable to create team newteam: : team creation error, ", printer.GetErrorLines()[0])
+		s.Require().Equal("Unable to create channel newteam:newchannel: the team was not created", printer.GetErrorLines()[1])
+	})
+}
+
+// stripApplyAction returns a copy of the action without its run
+// function so it can be compared
+func stripApplyAction(line interface{}) *applyAction {
+	action := line.(*applyAction)
+	return &applyAction{Action: action.Action, Target: action.Target, Detail: action.Detail}
+}
@@ -5,6 +5,7 @@ package commands
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -29,11 +30,16 @@ var AuthCmd = &cobra.Command{
 var LoginCmd = &cobra.Command{
 	Use:   "login [instance url] --name [server name] --username [username] --password [password]",
 	Short: "Login into an instance",
-	Long:  "Login into an instance and store credentials",
+	Long: `Login into an instance and store credentials.
+
+The SSO login redirects to mmctl once it finishes, so the server has to allow it adding "http://127.0.0.1:" to its NativeAppSettings.AppCustomURLSchemes setting.`,
 	Example: `  auth login https://mattermost.example.com
   auth login https://mattermost.example.com --name local-server --username sysadmin --password mysupersecret
   auth login https://mattermost.example.com --name local-server --username sysadmin --password mysupersecret --mfa-token 123456
-  auth login https://mattermost.example.com --name local-server --access-token myaccesstoken`,
+  auth login https://mattermost.example.com --name local-server --username sysadmin --save-password --credentials-store keyring
+  auth login https://mattermost.example.com --name local-server --access-token myaccesstoken
+  auth login https://mattermost.example.com --name local-server --sso
+  auth login https://mattermost.example.com --name local-server --sso --sso-service gitlab --no-browser`,
 	Args: cobra.ExactArgs(1),
 	RunE: loginCmdF,
 }
@@ -72,6 +78,15 @@ var RenewCmd = &cobra.Command{
 	RunE:    renewCmdF,
 }
 
+var StatusCmd = &cobra.Command{
+	Use:     "status",
+	Short:   "Show the status of the credentials",
+	Long:    "Checks each of the stored credentials against its server and shows if they are valid, the version of the server and when their session expires",
+	Example: `  auth status`,
+	Args:    cobra.NoArgs,
+	RunE:    statusCmdF,
+}
+
 var DeleteCmd = &cobra.Command{
 	Use:     "delete [server name]",
 	Short:   "Delete an credentials",
@@ -89,6 +104,19 @@ var CleanCmd = &cobra.Command{
 	RunE:    cleanCmdF,
 }
 
+var MigrateStoreCmd = &cobra.Command{
+	Use:   "migrate-store [store]",
+	Short: "Move the credentials to other store",
+	Long: `Move the credentials to other store. The available stores are:
+  file: the credentials are saved as plain text in the config file
+  encrypted-file: the credentials are encrypted with a passphrase, read from the MMCTL_CREDENTIALS_PASSPHRASE environment variable or asked for, or with the contents of the file set in the --credentials-key-file flag
+  keyring: the credentials are saved in the keyring of the operating system, like the Secret Service or the macOS keychain`,
+	Example: `  auth migrate-store encrypted-file
+  auth migrate-store keyring --from encrypted-file`,
+	Args: cobra.ExactArgs(1),
+	RunE: migrateStoreCmdF,
+}
+
 func init() {
 	LoginCmd.Flags().StringP("name", "n", "", "Name for the credentials")
 	LoginCmd.Flags().StringP("username", "u", "", "Username for the credentials")
@@ -96,10 +124,16 @@ func init() {
 	LoginCmd.Flags().StringP("mfa-token", "m", "", "MFA token for the credentials")
 	LoginCmd.Flags().StringP("password", "p", "", "Password for the credentials")
 	LoginCmd.Flags().Bool("no-activate", false, "If present, it won't activate the credentials after login")
+	LoginCmd.Flags().Bool("sso", false, "Login through the SSO service of the server in the browser. The server has to allow it in NativeAppSettings.AppCustomURLSchemes")
+	LoginCmd.Flags().Bool("save-password", false, "Save the password to renew the session once it expires. Only allowed with the encrypted-file and keyring credentials stores")
+	addSSOFlags(LoginCmd)
 
 	RenewCmd.Flags().StringP("password", "p", "", "Password for the credentials")
 	RenewCmd.Flags().StringP("access-token", "a", "", "Access token to use instead of username/password")
 	RenewCmd.Flags().StringP("mfa-token", "m", "", "MFA token for the credentials")
+	addSSOFlags(RenewCmd)
+
+	MigrateStoreCmd.Flags().String("from", "", "the store to move the credentials from. Defaults to the one in use")
 
 	AuthCmd.AddCommand(
 		LoginCmd,
@@ -107,8 +141,10 @@ func init() {
 		SetCmd,
 		ListCmd,
 		RenewCmd,
+		StatusCmd,
 		DeleteCmd,
 		CleanCmd,
+		MigrateStoreCmd,
 	)
 
 	RootCmd.AddCommand(AuthCmd)
@@ -156,7 +192,22 @@ func loginCmdF(cmd *cobra.Command, args []string) error {
 		return errors.New("you must use --access-token or --username, but not both")
 	}
 
-	if accessToken == "" && username == "" {
+	sso, _ := cmd.Flags().GetBool("sso")
+	if sso && (accessToken != "" || username != "" || password != "" || mfaToken != "") {
+		return errors.New("the --sso flag can't be used along with --username, --password, --mfa-token or --access-token")
+	}
+
+	savePassword, _ := cmd.Flags().GetBool("save-password")
+	if savePassword {
+		if sso || accessToken != "" {
+			return errors.New("the --save-password flag can only be used to login with a username and password")
+		}
+		if err := checkPasswordStore(); err != nil {
+			return err
+		}
+	}
+
+	if !sso && accessToken == "" && username == "" {
 		reader := bufio.NewReader(os.Stdin)
 		fmt.Printf("Username: ")
 		username, err = reader.ReadString('\n')
@@ -175,7 +226,20 @@ func loginCmdF(cmd *cobra.Command, args []string) error {
 		password = stdinPassword
 	}
 
-	if username != "" {
+	sessionID := ""
+	if sso {
+		username, accessToken, err = loginWithSSOFlags(cmd, url)
+		if err != nil {
+			printItemError(err.Error(), err)
+			// We don't want usage to be printed as the command was correctly built
+			return nil
+		}
+		method = MethodSSO
+		if c, err := NewAPIv4Client(url, allowInsecureSHA1, allowInsecureTLS); err == nil {
+			c.SetToken(accessToken)
+			sessionID, _ = getSessionID(c)
+		}
+	} else if username != "" {
 		var c *model.Client4
 		var err error
 		if mfaToken != "" {
@@ -185,11 +249,12 @@ func loginCmdF(cmd *cobra.Command, args []string) error {
 			c, _, err = InitClientWithUsernameAndPassword(username, password, url, allowInsecureSHA1, allowInsecureTLS)
 		}
 		if err != nil {
-			printer.PrintError(err.Error())
+			printItemError(err.Error(), err)
 			// We don't want usage to be printed as the command was correctly built
 			return nil
 		}
 		accessToken = c.AuthToken
+		sessionID, _ = getSessionID(c)
 	} else {
 		username = "Personal Access Token"
 		method = MethodToken
@@ -198,7 +263,7 @@ func loginCmdF(cmd *cobra.Command, args []string) error {
 			AuthToken:   accessToken,
 		}
 		if _, _, err := InitClientWithCredentials(&credentials, allowInsecureSHA1, allowInsecureTLS); err != nil {
-			printer.PrintError(err.Error())
+			printItemError(err.Error(), err)
 			// We don't want usage to be printed as the command was correctly built
 			return nil
 		}
@@ -210,6 +275,26 @@ func loginCmdF(cmd *cobra.Command, args []string) error {
 		Username:    username,
 		AuthToken:   accessToken,
 		AuthMethod:  method,
+		SessionId:   sessionID,
+	}
+	if savePassword {
+		credentials.Password = password
+	}
+	// the connection options needed to reach the server are saved, so
+	// they don't have to be set in every command. Only the flags set in
+	// the command are, as viper also has the defaults of other
+	// credentials
+	for _, key := range connectionDefaultKeys {
+		flag := cmd.Flags().Lookup(key)
+		if flag == nil {
+			flag = cmd.Root().PersistentFlags().Lookup(key)
+		}
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		if err := setContextDefault(&credentials, key+"="+flag.Value.String()); err != nil {
+			return err
+		}
 	}
 
 	if err := SaveCredentials(credentials); err != nil {
@@ -239,6 +324,24 @@ func getPasswordFromStdin() (string, error) {
 	return string(bytePassword), nil
 }
 
+func stdinIsTerminal() bool {
+	//nolint:unconvert
+	return term.IsTerminal(int(syscall.Stdin))
+}
+
+// checkPasswordStore checks that the credentials are kept in a store
+// where the password can be saved safely
+func checkPasswordStore() error {
+	store, err := getCredentialsStore()
+	if err != nil {
+		return err
+	}
+	if store.Name() == CredentialsStoreFile {
+		return errors.Errorf("the password can only be saved in the %s or %s credentials stores", CredentialsStoreEncryptedFile, CredentialsStoreKeyring)
+	}
+	return nil
+}
+
 func currentCmdF(cmd *cobra.Command, args []string) error {
 	credentials, err := GetCurrentCredentials()
 	if err != nil {
@@ -315,6 +418,7 @@ func renewCmdF(cmd *cobra.Command, args []string) error {
 	}
 
 	if (credentials.AuthMethod == MethodPassword || credentials.AuthMethod == MethodMFA) && password == "" {
+		password = credentials.Password
 		if password == "" {
 			fmt.Printf("Password: ")
 			stdinPassword, err := getPasswordFromStdin()
@@ -333,6 +437,7 @@ func renewCmdF(cmd *cobra.Command, args []string) error {
 		}
 
 		credentials.AuthToken = c.AuthToken
+		setSessionID(credentials, c)
 
 	case MethodToken:
 		if accessToken == "" {
@@ -340,6 +445,7 @@ func renewCmdF(cmd *cobra.Command, args []string) error {
 		}
 
 		credentials.AuthToken = accessToken
+		credentials.SessionId = ""
 		if _, _, err := InitClientWithCredentials(credentials, allowInsecureSHA1, allowInsecureTLS); err != nil {
 			return err
 		}
@@ -354,11 +460,30 @@ func renewCmdF(cmd *cobra.Command, args []string) error {
 			return err
 		}
 		credentials.AuthToken = c.AuthToken
+		setSessionID(credentials, c)
+
+	case MethodSSO:
+		_, token, err := loginWithSSOFlags(cmd, credentials.InstanceURL)
+		if err != nil {
+			return err
+		}
+		credentials.AuthToken = token
+		c, err := NewAPIv4Client(credentials.InstanceURL, allowInsecureSHA1, allowInsecureTLS)
+		if err != nil {
+			return err
+		}
+		c.SetToken(token)
+		setSessionID(credentials, c)
 
 	default:
 		return errors.Errorf("invalid auth method %q", credentials.AuthMethod)
 	}
 
+	// the saved password is kept up to date in case it has changed
+	if credentials.Password != "" {
+		credentials.Password = password
+	}
+
 	if err := SaveCredentials(*credentials); err != nil {
 		return err
 	}
@@ -368,6 +493,29 @@ func renewCmdF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func statusCmdF(cmd *cobra.Command, args []string) error {
+	credentialsList, err := ReadCredentialsList()
+	if err != nil {
+		return err
+	}
+
+	if len(*credentialsList) == 0 {
+		return errors.New("there are no registered credentials, maybe you need to use login first")
+	}
+
+	names := []string{}
+	for name := range *credentialsList {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		status := getAuthStatus((*credentialsList)[name])
+		printer.PrintT(`{{.Name}} ({{.Username}}@{{.InstanceURL}}): {{if .Valid}}valid, server version {{.ServerVersion}}, session expires: {{.ExpiresAt}}{{else}}not valid: {{.Error}}{{end}}`, status)
+	}
+	return nil
+}
+
 func deleteCmdF(cmd *cobra.Command, args []string) error {
 	credentialsList, err := ReadCredentialsList()
 	if err != nil {
@@ -390,3 +538,65 @@ func cleanCmdF(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+func migrateStoreCmdF(cmd *cobra.Command, args []string) error {
+	from, _ := cmd.Flags().GetString("from")
+
+	var source CredentialsStore
+	var err error
+	if from != "" {
+		source, err = NewCredentialsStore(from)
+	} else {
+		source, err = getCredentialsStore()
+	}
+	if err != nil {
+		return err
+	}
+
+	target, err := NewCredentialsStore(args[0])
+	if err != nil {
+		return err
+	}
+	if source.Name() == target.Name() {
+		return errors.Errorf("the credentials are already in the %s store", target.Name())
+	}
+
+	if ok, err := source.Exists(); err != nil {
+		return err
+	} else if !ok {
+		return errors.Errorf("there are no credentials in the %s store", source.Name())
+	}
+	if ok, err := target.Exists(); err != nil {
+		return err
+	} else if ok {
+		return errors.Errorf("the %s store already contains credentials, remove them first", target.Name())
+	}
+
+	data, err := source.Read()
+	if err != nil {
+		return errors.WithMessage(err, "cannot read the credentials")
+	}
+	if target.Name() == CredentialsStoreFile {
+		var credentialsList CredentialsList
+		if err := json.Unmarshal(data, &credentialsList); err != nil {
+			return errors.WithMessage(err, "there was a problem parsing the credentials")
+		}
+		for _, c := range credentialsList {
+			if c.Password != "" {
+				return errors.Errorf("the %q credentials have a saved password, which can't be moved to the %s store. Login again without --save-password first", c.Name, CredentialsStoreFile)
+			}
+		}
+	}
+	if err := target.Write(data); err != nil {
+		return errors.WithMessage(err, "cannot save the credentials")
+	}
+
+	// the credentials are only removed from the source once they are
+	// safe in the target
+	if err := source.Remove(); err != nil {
+		return errors.WithMessagef(err, "the credentials were copied to the %s store but couldn't be removed from the %s one", target.Name(), source.Name())
+	}
+
+	printer.Print(fmt.Sprintf("Credentials moved from the %s store to the %s store", source.Name(), target.Name()))
+	return nil
+}
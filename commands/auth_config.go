@@ -0,0 +1,248 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+// contextDefaultParsers are the settings that can have a default
+// value in the credentials, along with the function that checks that
+// a value is valid and returns it in the form it is saved
+var contextDefaultParsers = map[string]func(string) (string, error){
+	"format":                     parseFormatDefault,
+	"strict":                     parseBoolDefault,
+	"insecure-sha1-intermediate": parseBoolDefault,
+	"insecure-tls-version":       parseBoolDefault,
+	"local":                      parseBoolDefault,
+	"local-socket-path":          parseStringDefault,
+	"team":                       parseStringDefault,
+	"ca-cert":                    parseFileDefault,
+	"client-cert":                parseFileDefault,
+	"client-key":                 parseFileDefault,
+	"proxy":                      parseProxyDefault,
+}
+
+// baseDefaults are the defaults of the settings that don't come from
+// the flags of the root command, restored once the defaults of a set
+// of credentials stop being applied
+var baseDefaults = map[string]interface{}{
+	"local-socket-path": model.LOCAL_MODE_SOCKET_PATH,
+}
+
+// activeContextDefaults are the defaults of the credentials that the
+// command is running against
+var activeContextDefaults map[string]string
+
+var AuthConfigCmd = &cobra.Command{
+	Use:   "config [server name]",
+	Short: "Show or edit the defaults of a set of credentials",
+	Long: `Shows or edits the default values of the flags used when a command runs against the server of the credentials, either because they are the current ones or through the --contexts and --all-contexts flags. The flags of the command and the environment variables take precedence over them. The available settings are:
+  format: the format of the command output. Only used with the current credentials, as the output of several servers is printed together
+  strict: only runs commands if the mmctl version matches the server one
+  insecure-sha1-intermediate: allows to use insecure TLS protocols, such as SHA-1
+  insecure-tls-version: allows to use TLS versions 1.0 and 1.1
+  local: communicates with the server through a unix socket
+  local-socket-path: the unix socket used in local mode
+  team: the team used by the commands with a --team flag when it is not set
+  ca-cert: the file with the CA certificates to trust
+  client-cert: the file with the client certificate of the TLS connections
+  client-key: the file with the private key of the client certificate
+  proxy: the URL of the proxy to connect to the server through`,
+	Example: `  auth config local-server
+  auth config local-server --set format=json --set insecure-tls-version=true
+  auth config local-server --unset format`,
+	Args: cobra.ExactArgs(1),
+	RunE: authConfigCmdF,
+}
+
+func init() {
+	AuthConfigCmd.Flags().StringArray("set", []string{}, "a default to set, in key=value format. Can be repeated")
+	AuthConfigCmd.Flags().StringSlice("unset", []string{}, "the defaults to remove")
+
+	AuthCmd.AddCommand(AuthConfigCmd)
+}
+
+// contextDefault is a default of a set of credentials printed by the
+// config command
+type contextDefault struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func parseFormatDefault(value string) (string, error) {
+	for _, format := range []string{printer.FormatPlain, printer.FormatJSON, printer.FormatNDJSON, printer.FormatTable, printer.FormatCSV, printer.FormatTSV, printer.FormatYAML} {
+		if value == format {
+			return value, nil
+		}
+	}
+	return "", errors.Errorf("invalid format %q", value)
+}
+
+func parseBoolDefault(value string) (string, error) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return "", errors.Errorf("invalid boolean value %q", value)
+	}
+	return strconv.FormatBool(b), nil
+}
+
+func parseStringDefault(value string) (string, error) {
+	if value == "" {
+		return "", errors.New("the value can't be empty")
+	}
+	return value, nil
+}
+
+// parseFileDefault checks that the file exists and returns its
+// absolute path, so it is found from any directory
+func parseFileDefault(value string) (string, error) {
+	if value == "" {
+		return "", errors.New("the value can't be empty")
+	}
+	path, err := filepath.Abs(value)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", errors.Wrapf(err, "cannot read the file %s", value)
+	}
+	return path, nil
+}
+
+func parseProxyDefault(value string) (string, error) {
+	if _, err := parseProxyURL(value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+func getContextDefaultKeys() []string {
+	keys := []string{}
+	for key := range contextDefaultParsers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setContextDefault validates a default in key=value format and sets
+// it in the credentials
+func setContextDefault(credentials *Credentials, setting string) error {
+	parts := strings.SplitN(setting, "=", 2)
+	if len(parts) != 2 {
+		return errors.Errorf("invalid default %q, it must be in key=value format", setting)
+	}
+
+	key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	parse, ok := contextDefaultParsers[key]
+	if !ok {
+		return errors.Errorf("invalid default %q, the valid ones are %s", key, strings.Join(getContextDefaultKeys(), ", "))
+	}
+	value, err := parse(value)
+	if err != nil {
+		return errors.WithMessagef(err, "invalid value for %q", key)
+	}
+
+	if credentials.Defaults == nil {
+		credentials.Defaults = map[string]string{}
+	}
+	credentials.Defaults[key] = value
+	return nil
+}
+
+func authConfigCmdF(cmd *cobra.Command, args []string) error {
+	credentials, err := GetCredentials(args[0])
+	if err != nil {
+		return err
+	}
+
+	settings, _ := cmd.Flags().GetStringArray("set")
+	unset, _ := cmd.Flags().GetStringSlice("unset")
+	for _, setting := range settings {
+		if err := setContextDefault(credentials, setting); err != nil {
+			return err
+		}
+	}
+	for _, key := range unset {
+		if _, ok := contextDefaultParsers[key]; !ok {
+			return errors.Errorf("invalid default %q, the valid ones are %s", key, strings.Join(getContextDefaultKeys(), ", "))
+		}
+		delete(credentials.Defaults, key)
+	}
+	if len(credentials.Defaults) == 0 {
+		credentials.Defaults = nil
+	}
+
+	if len(settings) > 0 || len(unset) > 0 {
+		if err := SaveCredentials(*credentials); err != nil {
+			return err
+		}
+	}
+
+	keys := []string{}
+	for key := range credentials.Defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		printer.PrintT("{{.Key}}={{.Value}}", &contextDefault{Key: key, Value: credentials.Defaults[key]})
+	}
+	return nil
+}
+
+// applyContextDefaults sets the defaults of the credentials in viper,
+// so they are used unless the flags or the environment variables set
+// other values. It returns a function that restores the defaults that
+// were applied before, so each set of credentials only uses its own
+func applyContextDefaults(credentials *Credentials) func() {
+	previous := activeContextDefaults
+	setContextDefaults(credentials.Defaults)
+	return func() {
+		setContextDefaults(previous)
+	}
+}
+
+func setContextDefaults(defaults map[string]string) {
+	for key := range contextDefaultParsers {
+		if value, ok := defaults[key]; ok {
+			viper.SetDefault(key, value)
+			continue
+		}
+		// a nil default makes viper fall back to the value of the flag
+		viper.SetDefault(key, baseDefaults[key])
+	}
+	activeContextDefaults = defaults
+}
+
+// applyDefaultTeam sets the default team in the team flag of the
+// command, if it has one and it was not set. It returns a function
+// that unsets the flag again, so the default of other credentials can
+// be applied afterwards
+func applyDefaultTeam(cmd *cobra.Command) (func(), error) {
+	team := viper.GetString("team")
+	flag := cmd.Flags().Lookup("team")
+	if team == "" || flag == nil || flag.Changed {
+		return func() {}, nil
+	}
+	if err := cmd.Flags().Set("team", team); err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = flag.Value.Set(flag.DefValue)
+		flag.Changed = false
+	}, nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+func TestContextDefaults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mmctl-defaults")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	viper.Set("config-path", dir)
+	defer viper.Set("config-path", xdgConfigHomeVar)
+
+	printer.SetFormat(printer.FormatPlain)
+	require.NoError(t, SaveCredentials(Credentials{Name: "legacy", Username: "sysadmin", AuthToken: "token", AuthMethod: MethodToken, InstanceURL: "https://legacy.example.com"}))
+	defer func() {
+		require.NoError(t, CleanCredentials())
+	}()
+
+	newConfigCmd := func(set, unset []string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringArray("set", set, "")
+		cmd.Flags().StringSlice("unset", unset, "")
+		return cmd
+	}
+
+	t.Run("should set the defaults of the credentials", func(t *testing.T) {
+		printer.Clean()
+		err := authConfigCmdF(newConfigCmd([]string{"insecure-tls-version=true", "team=legacy-team", "format=json"}, nil), []string{"legacy"})
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"format=json", "insecure-tls-version=true", "team=legacy-team"}, printer.GetLines())
+
+		printer.Clean()
+		require.NoError(t, authConfigCmdF(newConfigCmd(nil, []string{"format"}), []string{"legacy"}))
+		require.Equal(t, []interface{}{"insecure-tls-version=true", "team=legacy-team"}, printer.GetLines())
+
+		credentials, err := GetCredentials("legacy")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"insecure-tls-version": "true", "team": "legacy-team"}, credentials.Defaults)
+	})
+
+	t.Run("should fail with invalid defaults", func(t *testing.T) {
+		err := authConfigCmdF(newConfigCmd([]string{"timeout=5s"}, nil), []string{"legacy"})
+		require.EqualError(t, err, `invalid default "timeout", the valid ones are ca-cert, client-cert, client-key, format, insecure-sha1-intermediate, insecure-tls-version, local, local-socket-path, proxy, strict, team`)
+
+		err = authConfigCmdF(newConfigCmd([]string{"strict=maybe"}, nil), []string{"legacy"})
+		require.EqualError(t, err, `invalid value for "strict": invalid boolean value "maybe"`)
+
+		err = authConfigCmdF(newConfigCmd([]string{"format"}, nil), []string{"legacy"})
+		require.EqualError(t, err, `invalid default "format", it must be in key=value format`)
+	})
+
+	t.Run("should apply the defaults of each set of credentials", func(t *testing.T) {
+		legacy, err := GetCredentials("legacy")
+		require.NoError(t, err)
+		other := &Credentials{Name: "other", Defaults: map[string]string{"team": "other-team", "local-socket-path": "/tmp/other.socket"}}
+
+		restoreLegacy := applyContextDefaults(legacy)
+		require.True(t, viper.GetBool("insecure-tls-version"))
+		require.Equal(t, "legacy-team", viper.GetString("team"))
+		require.Equal(t, model.LOCAL_MODE_SOCKET_PATH, viper.GetString("local-socket-path"))
+
+		restoreOther := applyContextDefaults(other)
+		require.False(t, viper.GetBool("insecure-tls-version"))
+		require.Equal(t, "other-team", viper.GetString("team"))
+		require.Equal(t, "/tmp/other.socket", viper.GetString("local-socket-path"))
+
+		restoreOther()
+		require.True(t, viper.GetBool("insecure-tls-version"))
+		require.Equal(t, "legacy-team", viper.GetString("team"))
+		require.Equal(t, model.LOCAL_MODE_SOCKET_PATH, viper.GetString("local-socket-path"))
+
+		restoreLegacy()
+		require.False(t, viper.GetBool("insecure-tls-version"))
+		require.Empty(t, viper.GetString("team"))
+		require.Equal(t, model.LOCAL_MODE_SOCKET_PATH, viper.GetString("local-socket-path"))
+	})
+
+	t.Run("should set the default team in the commands", func(t *testing.T) {
+		restore := applyContextDefaults(&Credentials{Defaults: map[string]string{"team": "legacy-team"}})
+		defer restore()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("team", "", "")
+		restoreTeam, err := applyDefaultTeam(cmd)
+		require.NoError(t, err)
+		team, _ := cmd.Flags().GetString("team")
+		require.Equal(t, "legacy-team", team)
+
+		restoreTeam()
+		team, _ = cmd.Flags().GetString("team")
+		require.Empty(t, team)
+		require.False(t, cmd.Flags().Changed("team"))
+
+		require.NoError(t, cmd.Flags().Set("team", "other-team"))
+		restoreTeam, err = applyDefaultTeam(cmd)
+		require.NoError(t, err)
+		restoreTeam()
+		team, _ = cmd.Flags().GetString("team")
+		require.Equal(t, "other-team", team)
+	})
+}
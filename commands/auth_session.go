@@ -0,0 +1,205 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+const (
+	sessionExpiresNever   = "never"
+	sessionExpiresUnknown = "unknown"
+)
+
+// authStatus is the validity of a set of credentials printed by the
+// status command
+type authStatus struct {
+	Name          string `json:"name"`
+	InstanceURL   string `json:"instanceUrl"`
+	Username      string `json:"username"`
+	AuthMethod    string `json:"authMethod"`
+	Valid         bool   `json:"valid"`
+	ServerVersion string `json:"serverVersion,omitempty"`
+	ExpiresAt     string `json:"expiresAt,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// isSessionExpired tells if an error is the server rejecting the
+// session token of the credentials
+func isSessionExpired(err error) bool {
+	appErr, ok := err.(*model.AppError)
+	return ok && appErr.StatusCode == http.StatusUnauthorized
+}
+
+// getSessionID returns the ID of the session a client is logged in
+// with. The server doesn't tell which session a token belongs to, so
+// the newest session of the user is taken, which is the one the login
+// has just created
+func getSessionID(c *model.Client4) (string, error) {
+	user, response := c.GetMe("")
+	if response.Error != nil {
+		return "", response.Error
+	}
+
+	sessions, response := c.GetSessions(user.Id, "")
+	if response.Error != nil {
+		return "", response.Error
+	}
+
+	var newest *model.Session
+	for _, session := range sessions {
+		if newest == nil || session.CreateAt > newest.CreateAt {
+			newest = session
+		}
+	}
+	if newest == nil {
+		return "", nil
+	}
+	return newest.Id, nil
+}
+
+// setSessionID saves in the credentials the ID of the session of the
+// client. The ID is only used to report when the session expires, so
+// failing to get it doesn't prevent the login
+func setSessionID(credentials *Credentials, c *model.Client4) {
+	sessionID, err := getSessionID(c)
+	if err != nil {
+		printer.PrintWarning("Couldn't get the session of the credentials: " + err.Error())
+	}
+	credentials.SessionId = sessionID
+}
+
+// refreshCredentials logs in again with credentials whose session has
+// expired, using the saved password or, if mmctl runs in a terminal,
+// asking for it, and saves the new session token
+func refreshCredentials(credentials *Credentials) (*model.Client4, string, error) {
+	renewErr := errors.Errorf("the session of the %q credentials has expired, please use the %q command", credentials.Name, "auth renew "+credentials.Name)
+	if credentials.AuthMethod != MethodPassword && credentials.AuthMethod != MethodMFA {
+		return nil, "", renewErr
+	}
+
+	interactive := stdinIsTerminal()
+	password := credentials.Password
+	if password == "" {
+		if !interactive {
+			return nil, "", renewErr
+		}
+
+		fmt.Printf("The session of the %q credentials has expired. Password for %s: ", credentials.Name, credentials.Username)
+		stdinPassword, err := getPasswordFromStdin()
+		if err != nil {
+			return nil, "", errors.WithMessage(err, "couldn't read password")
+		}
+		password = stdinPassword
+	}
+
+	allowInsecureSHA1 := viper.GetBool("insecure-sha1-intermediate")
+	allowInsecureTLS := viper.GetBool("insecure-tls-version")
+
+	var c *model.Client4
+	var serverVersion string
+	var err error
+	if credentials.AuthMethod == MethodMFA {
+		// the MFA token changes every time, so it can only be asked for
+		if !interactive {
+			return nil, "", renewErr
+		}
+
+		fmt.Printf("MFA token: ")
+		mfaToken, readErr := bufio.NewReader(os.Stdin).ReadString('\n')
+		if readErr != nil {
+			return nil, "", errors.WithMessage(readErr, "couldn't read the MFA token")
+		}
+		c, serverVersion, err = InitClientWithMFA(credentials.Username, password, strings.TrimSpace(mfaToken), credentials.InstanceURL, allowInsecureSHA1, allowInsecureTLS)
+	} else {
+		c, serverVersion, err = InitClientWithUsernameAndPassword(credentials.Username, password, credentials.InstanceURL, allowInsecureSHA1, allowInsecureTLS)
+	}
+	if err != nil {
+		return nil, "", errors.WithMessagef(err, "couldn't renew the expired session of the %q credentials", credentials.Name)
+	}
+
+	credentials.AuthToken = c.AuthToken
+	setSessionID(credentials, c)
+	if err := SaveCredentials(*credentials); err != nil {
+		return nil, "", err
+	}
+
+	printer.PrintWarning(fmt.Sprintf("The session of the %q credentials had expired and was renewed", credentials.Name))
+	return c, serverVersion, nil
+}
+
+// getAuthStatus checks the credentials against their server, with
+// their own defaults and without renewing them if the session has
+// expired
+func getAuthStatus(credentials *Credentials) *authStatus {
+	status := &authStatus{
+		Name:        credentials.Name,
+		InstanceURL: credentials.InstanceURL,
+		Username:    credentials.Username,
+		AuthMethod:  credentials.AuthMethod,
+	}
+
+	restore := applyContextDefaults(credentials)
+	defer restore()
+	allowInsecureTLS := viper.GetBool("insecure-tls-version")
+	c, err := NewAPIv4Client(credentials.InstanceURL, viper.GetBool("insecure-sha1-intermediate"), allowInsecureTLS)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	c.AuthType = model.HEADER_BEARER
+	c.AuthToken = credentials.AuthToken
+
+	user, response := c.GetMe("")
+	if response.Error != nil {
+		if isSessionExpired(response.Error) {
+			status.Error = "the session has expired"
+		} else {
+			status.Error = checkInsecureTLSError(response.Error, allowInsecureTLS).Error()
+		}
+		return status
+	}
+
+	status.Valid = true
+	status.ServerVersion = response.ServerVersion
+	status.ExpiresAt = getSessionExpiry(c, user.Id, credentials)
+	return status
+}
+
+// getSessionExpiry returns when the session of the credentials
+// expires, in RFC 3339 format
+func getSessionExpiry(c *model.Client4, userID string, credentials *Credentials) string {
+	if credentials.AuthMethod == MethodToken {
+		return sessionExpiresNever
+	}
+	if credentials.SessionId == "" {
+		return sessionExpiresUnknown
+	}
+
+	sessions, response := c.GetSessions(userID, "")
+	if response.Error != nil {
+		return sessionExpiresUnknown
+	}
+	for _, session := range sessions {
+		if session.Id != credentials.SessionId {
+			continue
+		}
+		if session.ExpiresAt == 0 {
+			return sessionExpiresNever
+		}
+		return time.Unix(0, session.ExpiresAt*int64(time.Millisecond)).UTC().Format(time.RFC3339)
+	}
+	return sessionExpiresUnknown
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mmctl/client"
+	"github.com/mattermost/mmctl/fakeserver"
+	"github.com/mattermost/mmctl/printer"
+)
+
+func TestSessionRefresh(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mmctl-session")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	viper.Set("config-path", dir)
+	defer viper.Set("config-path", xdgConfigHomeVar)
+
+	s := fakeserver.NewServer()
+	require.NoError(t, s.Start())
+	defer s.Close()
+
+	kr := fakeKeyring{}
+	originalNewKeyring := newKeyring
+	newKeyring = func() (keyring, error) { return kr, nil }
+	defer func() { newKeyring = originalNewKeyring }()
+
+	bindStringFlag := func(name, value string) {
+		cmd := &cobra.Command{}
+		cmd.Flags().String(name, value, "")
+		_ = viper.BindPFlag(name, cmd.Flags().Lookup(name))
+	}
+	defer bindStringFlag("credentials-store", "")
+
+	printer.SetFormat(printer.FormatJSON)
+	defer printer.SetFormat(printer.FormatPlain)
+
+	newLoginCmd := func(name string, savePassword bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("name", name, "")
+		cmd.Flags().String("username", fakeserver.AdminUsername, "")
+		cmd.Flags().String("password", fakeserver.AdminPassword, "")
+		cmd.Flags().String("access-token", "", "")
+		cmd.Flags().String("mfa-token", "", "")
+		cmd.Flags().Bool("no-activate", false, "")
+		cmd.Flags().Bool("sso", false, "")
+		cmd.Flags().Bool("save-password", savePassword, "")
+		return cmd
+	}
+
+	getAdmin := func(c client.Client, cmd *cobra.Command, args []string) error {
+		if _, response := c.GetUserByUsername(fakeserver.AdminUsername, ""); response.Error != nil {
+			return response.Error
+		}
+		return nil
+	}
+
+	t.Run("should not save the password in the file store", func(t *testing.T) {
+		err := loginCmdF(newLoginCmd("saved", true), []string{s.URL})
+		require.EqualError(t, err, "the password can only be saved in the encrypted-file or keyring credentials stores")
+	})
+
+	bindStringFlag("credentials-store", CredentialsStoreKeyring)
+
+	t.Run("should report the status of the credentials", func(t *testing.T) {
+		require.NoError(t, loginCmdF(newLoginCmd("saved", true), []string{s.URL}))
+		credentials, err := GetCredentials("saved")
+		require.NoError(t, err)
+		require.Equal(t, fakeserver.AdminPassword, credentials.Password)
+		require.NotEmpty(t, credentials.SessionId)
+
+		printer.Clean()
+		require.NoError(t, statusCmdF(&cobra.Command{}, []string{}))
+		require.Len(t, printer.GetLines(), 1)
+		status := printer.GetLines()[0].(*authStatus)
+		require.True(t, status.Valid)
+		require.NotEmpty(t, status.ServerVersion)
+		require.NotEqual(t, sessionExpiresUnknown, status.ExpiresAt)
+
+		s.ExpireSession(credentials.AuthToken)
+		printer.Clean()
+		require.NoError(t, statusCmdF(&cobra.Command{}, []string{}))
+		require.Equal(t, &authStatus{
+			Name:        "saved",
+			InstanceURL: s.URL,
+			Username:    fakeserver.AdminUsername,
+			AuthMethod:  MethodPassword,
+			Error:       "the session has expired",
+		}, printer.GetLines()[0])
+	})
+
+	t.Run("should renew an expired session with the saved password", func(t *testing.T) {
+		credentials, err := GetCredentials("saved")
+		require.NoError(t, err)
+		expiredToken := credentials.AuthToken
+
+		printer.Clean()
+		require.NoError(t, withClient(getAdmin)(&cobra.Command{}, []string{}))
+		require.Equal(t, []interface{}{`The session of the "saved" credentials had expired and was renewed`}, printer.GetWarningLines())
+
+		credentials, err = GetCredentials("saved")
+		require.NoError(t, err)
+		require.NotEqual(t, expiredToken, credentials.AuthToken)
+		require.NotEmpty(t, credentials.SessionId)
+	})
+
+	t.Run("should ask to renew the session without a saved password", func(t *testing.T) {
+		require.NoError(t, loginCmdF(newLoginCmd("unsaved", false), []string{s.URL}))
+		credentials, err := GetCredentials("unsaved")
+		require.NoError(t, err)
+		require.Empty(t, credentials.Password)
+		s.ExpireSession(credentials.AuthToken)
+
+		printer.Clean()
+		err = withClient(getAdmin)(&cobra.Command{}, []string{})
+		require.EqualError(t, err, `the session of the "unsaved" credentials has expired, please use the "auth renew unsaved" command`)
+	})
+
+	t.Run("should not move saved passwords to the file store", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("from", "", "")
+		err := migrateStoreCmdF(cmd, []string{CredentialsStoreFile})
+		require.EqualError(t, err, `the "saved" credentials have a saved password, which can't be moved to the file store. Login again without --save-password first`)
+	})
+
+	require.NoError(t, CleanCredentials())
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	ssoCallbackPath = "/callback"
+	// ssoStateParam is the query parameter of the callback with the
+	// random state of the login. The server keeps the query of the
+	// callback when it redirects to it, so only the redirects of the
+	// login started by mmctl have it
+	ssoStateParam = "state"
+	// ssoCallbackScheme is the prefix of the URL of the SSO login
+	// callback. The server only redirects the mobile logins to the URLs
+	// that start with one of the NativeAppSettings.AppCustomURLSchemes,
+	// so it has to be added to them for the SSO login to work
+	ssoCallbackScheme = "http://127.0.0.1:"
+)
+
+// ssoServices maps the SSO services to the client config setting that
+// tells if they are enabled
+var ssoServices = []struct {
+	name    string
+	setting string
+}{
+	{model.SERVICE_GITLAB, "EnableSignUpWithGitLab"},
+	{model.SERVICE_GOOGLE, "EnableSignUpWithGoogle"},
+	{model.SERVICE_OFFICE365, "EnableSignUpWithOffice365"},
+	{model.SERVICE_OPENID, "EnableSignUpWithOpenId"},
+	{model.USER_AUTH_SERVICE_SAML, "EnableSaml"},
+}
+
+// openBrowser opens a URL in the default browser of the user
+var openBrowser = func(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+func addSSOFlags(cmd *cobra.Command) {
+	cmd.Flags().String("sso-service", "", "SSO service to login with [gitlab, google, office365, openid, saml]. Defaults to the only one enabled in the server")
+	cmd.Flags().Bool("no-browser", false, "If present, the SSO login URL is printed instead of opened in the browser, and the session token is pasted once the login finishes. Useful if the browser runs in another machine")
+	cmd.Flags().Duration("sso-timeout", 5*time.Minute, "The time to wait for the SSO login in the browser to finish")
+}
+
+// getSSOService returns the SSO service to login with. If none is
+// requested, the server must have only one of them enabled
+func getSSOService(instanceURL, service string) (string, error) {
+	names := []string{}
+	for _, ssoService := range ssoServices {
+		names = append(names, ssoService.name)
+	}
+
+	if service != "" {
+		for _, name := range names {
+			if name == service {
+				return service, nil
+			}
+		}
+		return "", errors.Errorf("invalid SSO service %q, the valid services are %s", service, strings.Join(names, ", "))
+	}
+
+	c, err := NewAPIv4Client(instanceURL, viper.GetBool("insecure-sha1-intermediate"), viper.GetBool("insecure-tls-version"))
+	if err != nil {
+		return "", err
+	}
+	config, response := c.GetOldClientConfig("")
+	if response.Error != nil {
+		return "", errors.Wrap(response.Error, "failed to get the client config of the server")
+	}
+
+	enabled := []string{}
+	for _, ssoService := range ssoServices {
+		if config[ssoService.setting] == "true" {
+			enabled = append(enabled, ssoService.name)
+		}
+	}
+
+	switch len(enabled) {
+	case 0:
+		return "", errors.New("the server doesn't have any SSO service enabled")
+	case 1:
+		return enabled[0], nil
+	default:
+		return "", errors.Errorf("the server has several SSO services enabled, use --sso-service to select one of them: %s", strings.Join(enabled, ", "))
+	}
+}
+
+// getSSOLoginURL returns the URL of the server that starts the SSO
+// login and redirects to redirectTo with the session token once it
+// finishes, as the mobile apps do
+func getSSOLoginURL(instanceURL, service, redirectTo string) string {
+	query := url.Values{"redirect_to": {redirectTo}}
+	if service == model.USER_AUTH_SERVICE_SAML {
+		query.Set("action", model.OAUTH_ACTION_MOBILE)
+		return instanceURL + "/login/sso/saml?" + query.Encode()
+	}
+	return instanceURL + "/oauth/" + service + "/mobile_login?" + query.Encode()
+}
+
+// checkSSOLoginURL checks that the server accepts the callback of the
+// SSO login. The server redirects to the SSO service if it does, and
+// renders an error page if the callback is not one of its custom URL
+// schemes
+func checkSSOLoginURL(instanceURL, loginURL string) error {
+	c, err := NewAPIv4Client(instanceURL, viper.GetBool("insecure-sha1-intermediate"), viper.GetBool("insecure-tls-version"))
+	if err != nil {
+		return err
+	}
+	httpClient := *c.HttpClient
+	httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	res, err := httpClient.Get(loginURL)
+	if err != nil {
+		return errors.Wrap(err, "failed to start the SSO login")
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode >= http.StatusMultipleChoices && res.StatusCode < http.StatusBadRequest:
+		return nil
+	case res.StatusCode == http.StatusOK:
+		return errors.Errorf("the server doesn't allow the SSO login of mmctl. A system admin has to add %q to the NativeAppSettings.AppCustomURLSchemes setting of the server", ssoCallbackScheme)
+	default:
+		return errors.WithMessage(model.AppErrorFromJson(res.Body), "failed to start the SSO login")
+	}
+}
+
+// loginWithSSO runs the SSO login of the service in the browser and
+// returns the session token, received through a loopback listener the
+// server redirects to. The callbacks that don't have the state of the
+// login are rejected, so no other page can send a token of its own. If
+// the browser is not opened, it may run in another machine that can't
+// reach the listener, so the user pastes the token instead
+func loginWithSSO(instanceURL, service string, noBrowser bool, timeout time.Duration) (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to listen for the SSO login callback")
+	}
+
+	state := model.NewId()
+	tokens := make(chan string, 1)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != ssoCallbackPath {
+			http.NotFound(w, r)
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(r.URL.Query().Get(ssoStateParam)), []byte(state)) != 1 {
+			http.Error(w, "The state of the login doesn't match the one started by mmctl", http.StatusBadRequest)
+			return
+		}
+
+		token := r.URL.Query().Get(model.SESSION_COOKIE_TOKEN)
+		if token == "" {
+			http.Error(w, "The session token was not received", http.StatusBadRequest)
+			return
+		}
+		// the token is only read from one place, so it is shown to
+		// be pasted if mmctl is waiting for it in the terminal
+		if noBrowser {
+			fmt.Fprintf(w, "Login successful, paste the following token in mmctl:\n\n%s\n", token)
+			return
+		}
+		fmt.Fprintln(w, "Login successful, you can close this window and go back to mmctl")
+		select {
+		case tokens <- token:
+		default:
+		}
+	})}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+
+	callbackURL := "http://" + listener.Addr().String() + ssoCallbackPath + "?" + url.Values{ssoStateParam: {state}}.Encode()
+	loginURL := getSSOLoginURL(instanceURL, service, callbackURL)
+	if err := checkSSOLoginURL(instanceURL, loginURL); err != nil {
+		return "", err
+	}
+	fmt.Printf("Login with %s through the following URL:\n\n  %s\n\n", service, loginURL)
+
+	if noBrowser {
+		return readSSOToken()
+	}
+	if err := openBrowser(loginURL); err != nil {
+		fmt.Println("Couldn't open the browser, please open the URL manually")
+	}
+
+	select {
+	case token := <-tokens:
+		return token, nil
+	case <-time.After(timeout):
+		return "", errors.New("timed out waiting for the SSO login to finish")
+	}
+}
+
+// readSSOToken reads the session token pasted by the user once the SSO
+// login finishes
+func readSSOToken() (string, error) {
+	fmt.Printf("Once the login finishes, paste the token shown by the browser, or the %s parameter of the URL it was redirected to: ", model.SESSION_COOKIE_TOKEN)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	fmt.Println("")
+	token := strings.TrimSpace(line)
+	if token == "" {
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read the session token")
+		}
+		return "", errors.New("the session token can't be empty")
+	}
+	return token, nil
+}
+
+// loginWithSSOFlags runs the SSO login with the options of the command
+// flags and returns the username and the session token
+func loginWithSSOFlags(cmd *cobra.Command, instanceURL string) (string, string, error) {
+	service, _ := cmd.Flags().GetString("sso-service")
+	noBrowser, _ := cmd.Flags().GetBool("no-browser")
+	timeout, _ := cmd.Flags().GetDuration("sso-timeout")
+
+	service, err := getSSOService(instanceURL, service)
+	if err != nil {
+		return "", "", err
+	}
+
+	token, err := loginWithSSO(instanceURL, service, noBrowser, timeout)
+	if err != nil {
+		return "", "", err
+	}
+
+	credentials := Credentials{InstanceURL: instanceURL, AuthToken: token}
+	c, _, err := InitClientWithCredentials(&credentials, viper.GetBool("insecure-sha1-intermediate"), viper.GetBool("insecure-tls-version"))
+	if err != nil {
+		return "", "", errors.WithMessage(err, "the session token is not valid")
+	}
+
+	user, response := c.GetMe("")
+	if response.Error != nil {
+		return "", "", response.Error
+	}
+	return user.Username, token, nil
+}
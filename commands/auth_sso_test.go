@@ -0,0 +1,210 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/mattermost/mattermost-server/v5/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mmctl/fakeserver"
+	"github.com/mattermost/mmctl/printer"
+)
+
+func TestGetSSOLoginURL(t *testing.T) {
+	t.Run("should use the mobile login of the oauth services", func(t *testing.T) {
+		loginURL := getSSOLoginURL("https://mattermost.example.com", model.SERVICE_GITLAB, "http://127.0.0.1:8065/callback")
+		require.Equal(t, "https://mattermost.example.com/oauth/gitlab/mobile_login?redirect_to=http%3A%2F%2F127.0.0.1%3A8065%2Fcallback", loginURL)
+	})
+
+	t.Run("should use the mobile action of saml", func(t *testing.T) {
+		loginURL := getSSOLoginURL("https://mattermost.example.com", model.USER_AUTH_SERVICE_SAML, "http://127.0.0.1:8065/callback")
+		require.Equal(t, "https://mattermost.example.com/login/sso/saml?action=mobile&redirect_to=http%3A%2F%2F127.0.0.1%3A8065%2Fcallback", loginURL)
+	})
+}
+
+func TestLoginWithSSO(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mmctl-sso")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	viper.Set("config-path", dir)
+	defer viper.Set("config-path", xdgConfigHomeVar)
+
+	// the SSO logins are checked as the server does, only redirecting
+	// to the SSO service if the callback starts with one of the custom
+	// URL schemes, and the rest of requests are served by the fake
+	// server
+	s := fakeserver.NewServer()
+	config := &model.Config{}
+	config.SetDefaults()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/oauth/") && r.URL.Path != "/login/sso/saml" {
+			s.ServeHTTP(w, r)
+			return
+		}
+		if !utils.IsValidMobileAuthRedirectURL(config, r.URL.Query().Get("redirect_to")) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<p>Invalid URL scheme</p>"))
+			return
+		}
+		http.Redirect(w, r, "https://gitlab.example.com/oauth/authorize", http.StatusFound)
+	}))
+	defer server.Close()
+
+	// the browser is replaced by a request to the callback with the
+	// token, as the server does once the login finishes
+	originalOpenBrowser := openBrowser
+	defer func() { openBrowser = originalOpenBrowser }()
+	openBrowser = func(loginURL string) error {
+		u, err := url.Parse(loginURL)
+		if err != nil {
+			return err
+		}
+		callback, err := url.Parse(u.Query().Get("redirect_to"))
+		if err != nil {
+			return err
+		}
+		query := callback.Query()
+		query.Set(model.SESSION_COOKIE_TOKEN, s.AdminToken)
+		callback.RawQuery = query.Encode()
+
+		go func() {
+			if res, err := http.Get(callback.String()); err == nil {
+				res.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	newLoginCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("name", "sso-server", "")
+		cmd.Flags().String("username", "", "")
+		cmd.Flags().String("password", "", "")
+		cmd.Flags().String("access-token", "", "")
+		cmd.Flags().String("mfa-token", "", "")
+		cmd.Flags().Bool("no-activate", false, "")
+		cmd.Flags().Bool("sso", true, "")
+		addSSOFlags(cmd)
+		return cmd
+	}
+
+	t.Run("should fail if the server doesn't allow the callback", func(t *testing.T) {
+		cmd := newLoginCmd()
+		require.NoError(t, cmd.Flags().Set("sso-service", model.SERVICE_GITLAB))
+
+		printer.Clean()
+		require.NoError(t, loginCmdF(cmd, []string{server.URL}))
+		require.Equal(t, []interface{}{`the server doesn't allow the SSO login of mmctl. A system admin has to add "http://127.0.0.1:" to the NativeAppSettings.AppCustomURLSchemes setting of the server`}, printer.GetErrorLines())
+		_, err := GetCurrentCredentials()
+		require.Error(t, err)
+	})
+
+	t.Run("should store the session token received through the callback", func(t *testing.T) {
+		config.NativeAppSettings.AppCustomURLSchemes = append(config.NativeAppSettings.AppCustomURLSchemes, ssoCallbackScheme)
+
+		cmd := newLoginCmd()
+		require.NoError(t, cmd.Flags().Set("sso-service", model.SERVICE_GITLAB))
+
+		require.NoError(t, loginCmdF(cmd, []string{server.URL}))
+
+		c := model.NewAPIv4Client(server.URL)
+		c.SetToken(s.AdminToken)
+		sessions, resp := c.GetSessions(s.AdminUser.Id, "")
+		require.Nil(t, resp.Error)
+		require.Len(t, sessions, 1)
+
+		credentials, err := GetCurrentCredentials()
+		require.NoError(t, err)
+		require.Equal(t, &Credentials{
+			Name:        "sso-server",
+			Username:    fakeserver.AdminUsername,
+			AuthToken:   s.AdminToken,
+			AuthMethod:  MethodSSO,
+			InstanceURL: server.URL,
+			Active:      true,
+			SessionId:   sessions[0].Id,
+		}, credentials)
+	})
+
+	t.Run("should reject the callbacks without the state of the login", func(t *testing.T) {
+		sendCallback := openBrowser
+		defer func() { openBrowser = sendCallback }()
+
+		statusCodes := []int{}
+		openBrowser = func(loginURL string) error {
+			u, err := url.Parse(loginURL)
+			require.NoError(t, err)
+			callback, err := url.Parse(u.Query().Get("redirect_to"))
+			require.NoError(t, err)
+			require.NotEmpty(t, callback.Query().Get(ssoStateParam))
+
+			for _, state := range []string{"", model.NewId()} {
+				forged := *callback
+				forged.RawQuery = url.Values{ssoStateParam: {state}, model.SESSION_COOKIE_TOKEN: {"forged-token"}}.Encode()
+				res, err := http.Get(forged.String())
+				require.NoError(t, err)
+				res.Body.Close()
+				statusCodes = append(statusCodes, res.StatusCode)
+			}
+			return sendCallback(loginURL)
+		}
+
+		token, err := loginWithSSO(server.URL, model.SERVICE_GITLAB, false, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, s.AdminToken, token)
+		require.Equal(t, []int{http.StatusBadRequest, http.StatusBadRequest}, statusCodes)
+	})
+
+	t.Run("should read the session token from stdin without the browser", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		_, err = w.WriteString(s.AdminToken + "\n")
+		require.NoError(t, err)
+		w.Close()
+		originalStdin := os.Stdin
+		os.Stdin = r
+		defer func() { os.Stdin = originalStdin }()
+
+		browserOpened := openBrowser
+		openBrowser = func(string) error {
+			t.Error("the browser should not be opened")
+			return nil
+		}
+		defer func() { openBrowser = browserOpened }()
+
+		token, err := loginWithSSO(server.URL, model.SERVICE_GITLAB, true, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, s.AdminToken, token)
+
+		// the input has been consumed, so there is no token to read
+		_, err = loginWithSSO(server.URL, model.SERVICE_GITLAB, true, time.Minute)
+		require.EqualError(t, err, "failed to read the session token: EOF")
+	})
+
+	t.Run("should fail with an invalid service", func(t *testing.T) {
+		_, err := getSSOService(server.URL, "github")
+		require.EqualError(t, err, `invalid SSO service "github", the valid services are gitlab, google, office365, openid, saml`)
+	})
+
+	t.Run("should fail if used along with other login methods", func(t *testing.T) {
+		cmd := newLoginCmd()
+		require.NoError(t, cmd.Flags().Set("access-token", "token"))
+
+		err := loginCmdF(cmd, []string{server.URL})
+		require.EqualError(t, err, "the --sso flag can't be used along with --username, --password, --mfa-token or --access-token")
+	})
+}
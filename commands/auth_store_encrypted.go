@@ -0,0 +1,211 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	encryptedCredentialsSuffix  = ".enc"
+	encryptedCredentialsVersion = 1
+
+	// scrypt parameters recommended for interactive logins
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 16
+)
+
+// typedPassphrases keeps the passphrases typed by the user for each
+// encrypted file, as the credentials may be read several times in the
+// same command
+var typedPassphrases = map[string][]byte{}
+
+// encryptedCredentials is the content of the encrypted credentials
+// file. The key is derived from the passphrase or the key file with
+// scrypt and the data is encrypted with AES-GCM
+type encryptedCredentials struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// encryptedFileCredentialsStore saves the credentials list encrypted
+// with a passphrase or the contents of a key file
+type encryptedFileCredentialsStore struct {
+	path string
+	// secret is read once and kept, so the key file is not read twice
+	// by the same store
+	secret []byte
+}
+
+func (s *encryptedFileCredentialsStore) Name() string {
+	return CredentialsStoreEncryptedFile
+}
+
+func (s *encryptedFileCredentialsStore) Exists() (bool, error) {
+	return fileExists(s.path)
+}
+
+// getSecret returns the secret to derive the key from: the contents
+// of the key file, the passphrase set in the environment or, as a last
+// resort, the passphrase typed by the user
+func (s *encryptedFileCredentialsStore) getSecret(confirm bool) ([]byte, error) {
+	if s.secret != nil {
+		return s.secret, nil
+	}
+
+	if keyFile := viper.GetString("credentials-key-file"); keyFile != "" {
+		secret, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read the credentials key file")
+		}
+		if len(secret) == 0 {
+			return nil, errors.New("the credentials key file is empty")
+		}
+		s.secret = secret
+		return s.secret, nil
+	}
+
+	if passphrase := viper.GetString("credentials-passphrase"); passphrase != "" {
+		s.secret = []byte(passphrase)
+		return s.secret, nil
+	}
+
+	if passphrase, ok := typedPassphrases[s.path]; ok {
+		s.secret = passphrase
+		return s.secret, nil
+	}
+
+	if !stdinIsTerminal() {
+		return nil, errors.New("the encrypted credentials need a passphrase, set it in the MMCTL_CREDENTIALS_PASSPHRASE environment variable or use the --credentials-key-file flag")
+	}
+
+	fmt.Printf("Credentials passphrase: ")
+	passphrase, err := getPasswordFromStdin()
+	if err != nil {
+		return nil, errors.WithMessage(err, "couldn't read the passphrase")
+	}
+	if passphrase == "" {
+		return nil, errors.New("the passphrase can't be empty")
+	}
+
+	if confirm {
+		fmt.Printf("Confirm the passphrase: ")
+		confirmation, err := getPasswordFromStdin()
+		if err != nil {
+			return nil, errors.WithMessage(err, "couldn't read the passphrase")
+		}
+		if confirmation != passphrase {
+			return nil, errors.New("the passphrases don't match")
+		}
+	}
+
+	s.secret = []byte(passphrase)
+	typedPassphrases[s.path] = s.secret
+	return s.secret, nil
+}
+
+func newCredentialsCipher(secret, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(secret, salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (s *encryptedFileCredentialsStore) Read() ([]byte, error) {
+	b, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var encrypted encryptedCredentials
+	if err := json.Unmarshal(b, &encrypted); err != nil {
+		return nil, errors.Wrap(err, "the encrypted credentials file is not valid")
+	}
+	if encrypted.Version != encryptedCredentialsVersion {
+		return nil, errors.Errorf("unsupported encrypted credentials version %d", encrypted.Version)
+	}
+
+	secret, err := s.getSecret(false)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newCredentialsCipher(secret, encrypted.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted.Nonce) != aead.NonceSize() {
+		return nil, errors.New("the encrypted credentials file is not valid")
+	}
+
+	data, err := aead.Open(nil, encrypted.Nonce, encrypted.Data, nil)
+	if err != nil {
+		return nil, errors.New("cannot decrypt the credentials, the passphrase or the key file may be wrong")
+	}
+	return data, nil
+}
+
+func (s *encryptedFileCredentialsStore) Write(data []byte) error {
+	exists, err := s.Exists()
+	if err != nil {
+		return err
+	}
+
+	// the passphrase is confirmed when the file is created, as a typo
+	// would make the credentials unreadable
+	secret, err := s.getSecret(!exists)
+	if err != nil {
+		return err
+	}
+
+	encrypted := encryptedCredentials{
+		Version: encryptedCredentialsVersion,
+		Salt:    make([]byte, saltLen),
+	}
+	if _, err := io.ReadFull(rand.Reader, encrypted.Salt); err != nil {
+		return err
+	}
+
+	aead, err := newCredentialsCipher(secret, encrypted.Salt)
+	if err != nil {
+		return err
+	}
+
+	encrypted.Nonce = make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, encrypted.Nonce); err != nil {
+		return err
+	}
+	encrypted.Data = aead.Seal(nil, encrypted.Nonce, data, nil)
+
+	b, err := json.MarshalIndent(encrypted, "", "    ")
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(s.path, b)
+}
+
+func (s *encryptedFileCredentialsStore) Remove() error {
+	return removeFileIfExists(s.path)
+}
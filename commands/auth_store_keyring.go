@@ -0,0 +1,190 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	keyringCredentialsSuffix = ".keyring"
+	keyringService           = "mmctl"
+	keyringLabel             = "mmctl credentials"
+)
+
+// keyring is a secret storage of the operating system
+type keyring interface {
+	Get(service, account string) ([]byte, error)
+	Set(service, account string, secret []byte) error
+	Delete(service, account string) error
+}
+
+// newKeyring returns the keyring of the operating system, if it is
+// available
+var newKeyring = func() (keyring, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err != nil {
+			return nil, errors.New("the keyring store needs the security command")
+		}
+		return &securityKeyring{}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return nil, errors.New("the keyring store needs the secret-tool command of libsecret to access the Secret Service")
+		}
+		return &secretToolKeyring{}, nil
+	default:
+		return nil, errors.Errorf("the keyring store is not available in %s", runtime.GOOS)
+	}
+}
+
+// runKeyringCommand runs a command of the keyring and returns its
+// output, including the error output in the error if it fails. The
+// secrets are always passed through stdin, as the arguments can be
+// read by any user of the system while the command runs
+var runKeyringCommand = func(stdin []byte, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.Wrapf(err, "%s failed: %s", name, msg)
+		}
+		return nil, errors.Wrapf(err, "%s failed", name)
+	}
+	return out, nil
+}
+
+// secretToolKeyring accesses the Secret Service through the secret-tool
+// command of libsecret
+type secretToolKeyring struct{}
+
+func (k *secretToolKeyring) Get(service, account string) ([]byte, error) {
+	out, err := runKeyringCommand(nil, "secret-tool", "lookup", "service", service, "account", account)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (k *secretToolKeyring) Set(service, account string, secret []byte) error {
+	_, err := runKeyringCommand(secret, "secret-tool", "store", "--label", keyringLabel, "service", service, "account", account)
+	return err
+}
+
+func (k *secretToolKeyring) Delete(service, account string) error {
+	_, err := runKeyringCommand(nil, "secret-tool", "clear", "service", service, "account", account)
+	return err
+}
+
+// securityKeyring accesses the macOS keychain through the security
+// command
+type securityKeyring struct{}
+
+func (k *securityKeyring) Get(service, account string) ([]byte, error) {
+	out, err := runKeyringCommand(nil, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(out, []byte("\n")), nil
+}
+
+// Set runs the security command in interactive mode, as the password
+// can only be passed to add-generic-password as an argument, and
+// sends it the command through stdin
+func (k *securityKeyring) Set(service, account string, secret []byte) error {
+	args := []string{"add-generic-password", "-U", "-l", keyringLabel, "-s", service, "-a", account, "-w", string(secret)}
+	for i, arg := range args {
+		args[i] = quoteSecurityArg(arg)
+	}
+	_, err := runKeyringCommand([]byte(strings.Join(args, " ")+"\n"), "security", "-i")
+	return err
+}
+
+// quoteSecurityArg quotes an argument of a command of the interactive
+// mode of security, which splits the commands by spaces
+func quoteSecurityArg(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+func (k *securityKeyring) Delete(service, account string) error {
+	_, err := runKeyringCommand(nil, "security", "delete-generic-password", "-s", service, "-a", account)
+	return err
+}
+
+// keyringCredentialsStore saves the credentials list in the keyring of
+// the operating system. The keyring can't be queried without asking
+// the user in some systems, so a marker file next to the config file
+// tells that the credentials are there
+type keyringCredentialsStore struct {
+	markerPath string
+	account    string
+}
+
+func (s *keyringCredentialsStore) Name() string {
+	return CredentialsStoreKeyring
+}
+
+func (s *keyringCredentialsStore) Exists() (bool, error) {
+	return fileExists(s.markerPath)
+}
+
+func (s *keyringCredentialsStore) Read() ([]byte, error) {
+	kr, err := newKeyring()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := kr.Get(keyringService, s.account)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot read the credentials from the keyring")
+	}
+
+	// the credentials are encoded as some keyrings only support
+	// printable secrets in a single line
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(secret)))
+	if err != nil {
+		return nil, errors.Wrap(err, "the credentials of the keyring are not valid")
+	}
+	return data, nil
+}
+
+func (s *keyringCredentialsStore) Write(data []byte) error {
+	kr, err := newKeyring()
+	if err != nil {
+		return err
+	}
+
+	if err := kr.Set(keyringService, s.account, []byte(base64.StdEncoding.EncodeToString(data))); err != nil {
+		return errors.WithMessage(err, "cannot save the credentials in the keyring")
+	}
+	return writePrivateFile(s.markerPath, []byte(keyringService+"\n"))
+}
+
+func (s *keyringCredentialsStore) Remove() error {
+	exists, err := s.Exists()
+	if err != nil || !exists {
+		return err
+	}
+
+	kr, err := newKeyring()
+	if err != nil {
+		return err
+	}
+	if err := kr.Delete(keyringService, s.account); err != nil {
+		return errors.WithMessage(err, "cannot remove the credentials from the keyring")
+	}
+	return removeFileIfExists(s.markerPath)
+}
@@ -19,12 +19,23 @@ const (
 	MethodPassword = "P"
 	MethodToken    = "T"
 	MethodMFA      = "M"
+	MethodSSO      = "S"
 
 	userHomeVar      = "$HOME"
 	configFileName   = "mmctl"
 	xdgConfigHomeVar = "$XDG_CONFIG_HOME"
+
+	CredentialsStoreFile          = "file"
+	CredentialsStoreEncryptedFile = "encrypted-file"
+	CredentialsStoreKeyring       = "keyring"
 )
 
+var credentialsStores = []string{CredentialsStoreFile, CredentialsStoreEncryptedFile, CredentialsStoreKeyring}
+
+// ErrNoCredentials is returned when reading the credentials before
+// any of them has been saved
+var ErrNoCredentials = errors.New("cannot read user credentials, maybe you need to use login first")
+
 type Credentials struct {
 	Name        string `json:"name"`
 	Username    string `json:"username"`
@@ -32,6 +43,13 @@ type Credentials struct {
 	AuthMethod  string `json:"authMethod"`
 	InstanceURL string `json:"instanceUrl"`
 	Active      bool   `json:"active"`
+	// Password is only saved when requested at login, to renew the
+	// session once it expires
+	Password  string `json:"password,omitempty"`
+	SessionId string `json:"sessionId,omitempty"`
+	// Defaults are the values of the flags used when the credentials
+	// are the current ones, unless the flags are set
+	Defaults map[string]string `json:"defaults,omitempty"`
 }
 
 type CredentialsList map[string]*Credentials
@@ -49,17 +67,20 @@ func init() {
 
 func getDefaultConfigPath() string {
 	configPath := currentUser.HomeDir
-	// We use the existing $HOME/.mmctl file if it exists.
-	// If not, we try to read XDG_CONFIG_HOME and if we fail,
-	// we fallback to $HOME/.config/mmctl.
-	if _, err := os.Stat(filepath.Join(currentUser.HomeDir, "."+configFileName)); os.IsNotExist(err) {
-		if p, ok := os.LookupEnv(strings.TrimPrefix(xdgConfigHomeVar, "$")); ok {
-			configPath = p
-		} else {
-			configPath = filepath.Join(currentUser.HomeDir, ".config")
+	// We use the existing $HOME/.mmctl file, or the files of the
+	// other credentials stores, if they exist. If not, we try to read
+	// XDG_CONFIG_HOME and if we fail, we fallback to
+	// $HOME/.config/mmctl.
+	for _, suffix := range []string{"", encryptedCredentialsSuffix, keyringCredentialsSuffix} {
+		if _, err := os.Stat(filepath.Join(currentUser.HomeDir, "."+configFileName+suffix)); err == nil {
+			return configPath
 		}
 	}
-	return configPath
+
+	if p, ok := os.LookupEnv(strings.TrimPrefix(xdgConfigHomeVar, "$")); ok {
+		return p
+	}
+	return filepath.Join(currentUser.HomeDir, ".config")
 }
 
 func resolveConfigFilePath() string {
@@ -76,21 +97,127 @@ func resolveConfigFilePath() string {
 	return filepath.Join(configPath, f)
 }
 
+// CredentialsStore is a backend where the credentials list is saved
+type CredentialsStore interface {
+	// Name returns the name the store is selected with
+	Name() string
+	// Exists returns whether the store contains a credentials list
+	Exists() (bool, error)
+	// Read returns the credentials list saved in the store
+	Read() ([]byte, error)
+	// Write saves the credentials list in the store
+	Write(data []byte) error
+	// Remove deletes the credentials list from the store
+	Remove() error
+}
+
+// fileCredentialsStore saves the credentials list as plain JSON
+type fileCredentialsStore struct {
+	path string
+}
+
+func (s *fileCredentialsStore) Name() string {
+	return CredentialsStoreFile
+}
+
+func (s *fileCredentialsStore) Exists() (bool, error) {
+	return fileExists(s.path)
+}
+
+func (s *fileCredentialsStore) Read() ([]byte, error) {
+	return ioutil.ReadFile(s.path)
+}
+
+func (s *fileCredentialsStore) Write(data []byte) error {
+	return writePrivateFile(s.path, data)
+}
+
+func (s *fileCredentialsStore) Remove() error {
+	return removeFileIfExists(s.path)
+}
+
+func fileExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// writePrivateFile writes a file that only the user can read, creating
+// its directory if needed
+func writePrivateFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func removeFileIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// NewCredentialsStore returns the credentials store with the given
+// name
+func NewCredentialsStore(name string) (CredentialsStore, error) {
+	configFilePath := resolveConfigFilePath()
+	switch name {
+	case CredentialsStoreFile:
+		return &fileCredentialsStore{path: configFilePath}, nil
+	case CredentialsStoreEncryptedFile:
+		return &encryptedFileCredentialsStore{path: configFilePath + encryptedCredentialsSuffix}, nil
+	case CredentialsStoreKeyring:
+		return &keyringCredentialsStore{markerPath: configFilePath + keyringCredentialsSuffix, account: configFilePath}, nil
+	default:
+		return nil, errors.Errorf("unknown credentials store %q, the available stores are %s", name, strings.Join(credentialsStores, ", "))
+	}
+}
+
+// getCredentialsStore returns the store selected with the
+// credentials-store flag or, if it is not set, the first store that
+// contains credentials. The file store is used if none of them does
+func getCredentialsStore() (CredentialsStore, error) {
+	if name := viper.GetString("credentials-store"); name != "" {
+		return NewCredentialsStore(name)
+	}
+
+	for _, name := range []string{CredentialsStoreEncryptedFile, CredentialsStoreKeyring} {
+		store, err := NewCredentialsStore(name)
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := store.Exists(); err != nil {
+			return nil, err
+		} else if ok {
+			return store, nil
+		}
+	}
+	return NewCredentialsStore(CredentialsStoreFile)
+}
+
 func ReadCredentialsList() (*CredentialsList, error) {
-	configPath := resolveConfigFilePath()
+	store, err := getCredentialsStore()
+	if err != nil {
+		return nil, err
+	}
 
-	if _, err := os.Stat(configPath); err != nil {
-		return nil, errors.WithMessage(err, "cannot read user credentials, maybe you need to use login first")
+	if ok, err := store.Exists(); err != nil {
+		return nil, errors.WithMessage(err, "cannot read user credentials")
+	} else if !ok {
+		return nil, ErrNoCredentials
 	}
 
-	fileContents, err := ioutil.ReadFile(configPath)
+	fileContents, err := store.Read()
 	if err != nil {
-		return nil, errors.WithMessage(err, "there was a problem reading the credentials file")
+		return nil, errors.WithMessage(err, "there was a problem reading the credentials")
 	}
 
 	var credentialsList CredentialsList
 	if err := json.Unmarshal(fileContents, &credentialsList); err != nil {
-		return nil, errors.WithMessage(err, "there was a problem parsing the credentials file")
+		return nil, errors.WithMessage(err, "there was a problem parsing the credentials")
 	}
 
 	return &credentialsList, nil
@@ -126,12 +253,11 @@ func GetCredentials(name string) (*Credentials, error) {
 
 func SaveCredentials(credentials Credentials) error {
 	credentialsList, err := ReadCredentialsList()
-	if err != nil {
-		if err := os.MkdirAll(strings.TrimSuffix(resolveConfigFilePath(), configFileName), 0700); err != nil {
-			return err
-		}
+	if err == ErrNoCredentials {
 		credentialsList = &CredentialsList{}
 		credentials.Active = true
+	} else if err != nil {
+		return err
 	}
 
 	(*credentialsList)[credentials.Name] = &credentials
@@ -139,11 +265,14 @@ func SaveCredentials(credentials Credentials) error {
 }
 
 func SaveCredentialsList(credentialsList *CredentialsList) error {
-	configPath := resolveConfigFilePath()
+	store, err := getCredentialsStore()
+	if err != nil {
+		return err
+	}
 
 	marshaledCredentialsList, _ := json.MarshalIndent(credentialsList, "", "    ")
 
-	if err := ioutil.WriteFile(configPath, marshaledCredentialsList, 0600); err != nil {
+	if err := store.Write(marshaledCredentialsList); err != nil {
 		return errors.WithMessage(err, "cannot save the credentials")
 	}
 
@@ -174,19 +303,11 @@ func SetCurrent(name string) error {
 }
 
 func CleanCredentials() error {
-	configFilePath := resolveConfigFilePath()
-
-	if _, err := os.Stat(configFilePath); err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
-		return err
-	}
-
-	if err := os.Remove(configFilePath); err != nil {
+	store, err := getCredentialsStore()
+	if err != nil {
 		return err
 	}
-	return nil
+	return store.Remove()
 }
 
 func SetUser(newUser *user.User) {
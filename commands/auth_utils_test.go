@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 )
@@ -96,6 +98,118 @@ func TestResolveConfigFilePath(t *testing.T) {
 	})
 }
 
+// fakeKeyring keeps the secrets in memory
+type fakeKeyring map[string][]byte
+
+func (k fakeKeyring) Get(service, account string) ([]byte, error) {
+	secret, ok := k[service+"/"+account]
+	if !ok {
+		return nil, errors.New("secret not found")
+	}
+	return secret, nil
+}
+
+func (k fakeKeyring) Set(service, account string, secret []byte) error {
+	k[service+"/"+account] = secret
+	return nil
+}
+
+func (k fakeKeyring) Delete(service, account string) error {
+	delete(k, service+"/"+account)
+	return nil
+}
+
+func TestCredentialsStores(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mmctl-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	viper.Set("config-path", dir)
+	defer viper.Set("config-path", xdgConfigHomeVar)
+
+	// the flags are bound instead of setting the values, so they can
+	// be reset once the test finishes
+	bindStringFlag := func(name, value string) {
+		cmd := &cobra.Command{}
+		cmd.Flags().String(name, value, "")
+		_ = viper.BindPFlag(name, cmd.Flags().Lookup(name))
+	}
+	bindStringFlag("credentials-passphrase", "passphrase")
+	defer bindStringFlag("credentials-passphrase", "")
+
+	kr := fakeKeyring{}
+	originalNewKeyring := newKeyring
+	newKeyring = func() (keyring, error) { return kr, nil }
+	defer func() { newKeyring = originalNewKeyring }()
+
+	credentials := Credentials{Name: "local", Username: "sysadmin", AuthToken: "token", AuthMethod: MethodToken, InstanceURL: "https://mattermost.example.com"}
+
+	t.Run("should encrypt the credentials", func(t *testing.T) {
+		store := &encryptedFileCredentialsStore{path: filepath.Join(dir, "encrypted"), secret: []byte("secret")}
+		require.NoError(t, store.Write([]byte(`{"local": {}}`)))
+
+		b, err := ioutil.ReadFile(store.path)
+		require.NoError(t, err)
+		require.NotContains(t, string(b), "local")
+
+		data, err := store.Read()
+		require.NoError(t, err)
+		require.Equal(t, `{"local": {}}`, string(data))
+
+		wrongStore := &encryptedFileCredentialsStore{path: store.path, secret: []byte("wrong")}
+		_, err = wrongStore.Read()
+		require.EqualError(t, err, "cannot decrypt the credentials, the passphrase or the key file may be wrong")
+	})
+
+	t.Run("should migrate the credentials between stores", func(t *testing.T) {
+		require.NoError(t, SaveCredentials(credentials))
+		store, err := getCredentialsStore()
+		require.NoError(t, err)
+		require.Equal(t, CredentialsStoreFile, store.Name())
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("from", "", "")
+		require.NoError(t, migrateStoreCmdF(cmd, []string{CredentialsStoreEncryptedFile}))
+		_, err = os.Stat(resolveConfigFilePath())
+		require.True(t, os.IsNotExist(err))
+
+		store, err = getCredentialsStore()
+		require.NoError(t, err)
+		require.Equal(t, CredentialsStoreEncryptedFile, store.Name())
+		current, err := GetCurrentCredentials()
+		require.NoError(t, err)
+		require.Equal(t, "token", current.AuthToken)
+
+		require.NoError(t, migrateStoreCmdF(cmd, []string{CredentialsStoreKeyring}))
+		require.Len(t, kr, 1)
+
+		store, err = getCredentialsStore()
+		require.NoError(t, err)
+		require.Equal(t, CredentialsStoreKeyring, store.Name())
+		current, err = GetCurrentCredentials()
+		require.NoError(t, err)
+		require.Equal(t, "token", current.AuthToken)
+
+		require.NoError(t, CleanCredentials())
+		require.Empty(t, kr)
+		_, err = ReadCredentialsList()
+		require.Equal(t, ErrNoCredentials, err)
+	})
+
+	t.Run("should fail to migrate to the store in use", func(t *testing.T) {
+		require.NoError(t, SaveCredentials(credentials))
+		defer CleanCredentials()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("from", "", "")
+		err := migrateStoreCmdF(cmd, []string{CredentialsStoreFile})
+		require.EqualError(t, err, "the credentials are already in the file store")
+
+		err = migrateStoreCmdF(cmd, []string{"cloud"})
+		require.EqualError(t, err, `unknown credentials store "cloud", the available stores are file, encrypted-file, keyring`)
+	})
+}
+
 func createFile(path string) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
 		return err
@@ -105,3 +219,38 @@ func createFile(path string) error {
 	}
 	return nil
 }
+
+func TestKeyringCommands(t *testing.T) {
+	type keyringCommand struct {
+		stdin string
+		args  []string
+	}
+	commands := []keyringCommand{}
+	originalRunKeyringCommand := runKeyringCommand
+	runKeyringCommand = func(stdin []byte, name string, args ...string) ([]byte, error) {
+		commands = append(commands, keyringCommand{stdin: string(stdin), args: append([]string{name}, args...)})
+		return nil, nil
+	}
+	defer func() { runKeyringCommand = originalRunKeyringCommand }()
+
+	secret := "c2VjcmV0IGNyZWRlbnRpYWxz"
+
+	t.Run("should pass the secrets through stdin", func(t *testing.T) {
+		for _, kr := range []keyring{&securityKeyring{}, &secretToolKeyring{}} {
+			commands = []keyringCommand{}
+			require.NoError(t, kr.Set(keyringService, "john.doe", []byte(secret)))
+			require.Len(t, commands, 1)
+			require.Contains(t, commands[0].stdin, secret)
+			for _, arg := range commands[0].args {
+				require.NotContains(t, arg, secret)
+			}
+		}
+	})
+
+	t.Run("should quote the arguments of the security interactive mode", func(t *testing.T) {
+		commands = []keyringCommand{}
+		require.NoError(t, (&securityKeyring{}).Set(keyringService, `john "the" doe\`, []byte(secret)))
+		require.Equal(t, []string{"security", "-i"}, commands[0].args)
+		require.Equal(t, `"add-generic-password" "-U" "-l" "mmctl credentials" "-s" "mmctl" "-a" "john \"the\" doe\\" "-w" "`+secret+`"`+"\n", commands[0].stdin)
+	})
+}
@@ -218,13 +218,13 @@ func botEnableCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	users := getUsersFromUserArgs(c, args)
 	for i, user := range users {
 		if user == nil {
-			printer.PrintError(fmt.Sprintf("can't find user '%v'", args[i]))
+			printItemError(fmt.Sprintf("can't find user '%v'", args[i]), ErrEntityNotFound{Type: "user", ID: args[i]})
 			continue
 		}
 
 		bot, res := c.EnableBot(user.Id)
 		if err := res.Error; err != nil {
-			printer.PrintError(fmt.Sprintf("could not enable bot '%v'", args[i]))
+			printItemError(fmt.Sprintf("could not enable bot '%v'", args[i]), err)
 			continue
 		}
 
@@ -238,13 +238,13 @@ func botDisableCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	users := getUsersFromUserArgs(c, args)
 	for i, user := range users {
 		if user == nil {
-			printer.PrintError(fmt.Sprintf("can't find user '%v'", args[i]))
+			printItemError(fmt.Sprintf("can't find user '%v'", args[i]), ErrEntityNotFound{Type: "user", ID: args[i]})
 			continue
 		}
 
 		bot, res := c.DisableBot(user.Id)
 		if err := res.Error; err != nil {
-			printer.PrintError(fmt.Sprintf("could not disable bot '%v'", args[i]))
+			printItemError(fmt.Sprintf("could not disable bot '%v'", args[i]), err)
 			continue
 		}
 
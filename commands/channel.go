@@ -252,11 +252,11 @@ func archiveChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) err
 	channels := getChannelsFromChannelArgs(c, args)
 	for i, channel := range channels {
 		if channel == nil {
-			printer.PrintError("Unable to find channel '" + args[i] + "'")
+			printItemError("Unable to find channel '"+args[i]+"'", ErrEntityNotFound{Type: "channel", ID: args[i]})
 			continue
 		}
 		if _, response := c.DeleteChannel(channel.Id); response.Error != nil {
-			printer.PrintError("Unable to archive channel '" + channel.Name + "' error: " + response.Error.Error())
+			printItemError("Unable to archive channel '"+channel.Name+"' error: "+response.Error.Error(), response.Error)
 		}
 	}
 
@@ -267,13 +267,13 @@ func listChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) error
 	teams := getTeamsFromTeamArgs(c, args)
 	for i, team := range teams {
 		if team == nil {
-			printer.PrintError("Unable to find team '" + args[i] + "'")
+			printItemError("Unable to find team '"+args[i]+"'", ErrEntityNotFound{Type: "team", ID: args[i]})
 			continue
 		}
 
 		publicChannels, response := c.GetPublicChannelsForTeam(team.Id, 0, 10000, "")
 		if response.Error != nil {
-			printer.PrintError("Unable to list public channels for '" + args[i] + "'. Error: " + response.Error.Error())
+			printItemError("Unable to list public channels for '"+args[i]+"'. Error: "+response.Error.Error(), response.Error)
 		}
 		for _, channel := range publicChannels {
 			printer.PrintT("{{.Name}}", channel)
@@ -281,7 +281,7 @@ func listChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) error
 
 		deletedChannels, response := c.GetDeletedChannelsForTeam(team.Id, 0, 10000, "")
 		if response.Error != nil {
-			printer.PrintError("Unable to list archived channels for '" + args[i] + "'. Error: " + response.Error.Error())
+			printItemError("Unable to list archived channels for '"+args[i]+"'. Error: "+response.Error.Error(), response.Error)
 		}
 		for _, channel := range deletedChannels {
 			printer.PrintT("{{.Name}} (archived)", channel)
@@ -289,7 +289,7 @@ func listChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) error
 
 		privateChannels, err := getPrivateChannels(c, team.Id)
 		if err != nil {
-			printer.PrintError("Unable to list private channels for '" + args[i] + "'. Error: " + err.Error())
+			printItemError("Unable to list private channels for '"+args[i]+"'. Error: "+err.Error(), err)
 		}
 		for _, channel := range privateChannels {
 			printer.PrintT("{{.Name}} (private)", channel)
@@ -307,11 +307,11 @@ func unarchiveChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) e
 	channels := getChannelsFromChannelArgs(c, args)
 	for i, channel := range channels {
 		if channel == nil {
-			printer.PrintError("Unable to find channel '" + args[i] + "'")
+			printItemError("Unable to find channel '"+args[i]+"'", ErrEntityNotFound{Type: "channel", ID: args[i]})
 			continue
 		}
 		if _, response := c.RestoreChannel(channel.Id); response.Error != nil {
-			printer.PrintError("Unable to unarchive channel '" + args[i] + "'. Error: " + response.Error.Error())
+			printItemError("Unable to unarchive channel '"+args[i]+"'. Error: "+response.Error.Error(), response.Error)
 		}
 	}
 
@@ -465,7 +465,7 @@ func moveChannelCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	channels := getChannelsFromChannelArgs(c, args[1:])
 	for i, channel := range channels {
 		if channel == nil {
-			printer.PrintError(fmt.Sprintf("Unable to find channel %q", args[i+1]))
+			printItemError(fmt.Sprintf("Unable to find channel %q", args[i+1]), ErrEntityNotFound{Type: "channel", ID: args[i+1]})
 			continue
 		}
 
@@ -475,7 +475,7 @@ func moveChannelCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 
 		newChannel, resp := c.MoveChannel(channel.Id, team.Id, force)
 		if resp.Error != nil {
-			printer.PrintError(fmt.Sprintf("unable to move channel %q: %s", channel.Name, resp.Error))
+			printItemError(fmt.Sprintf("unable to move channel %q: %s", channel.Name, resp.Error), resp.Error)
 			continue
 		}
 		printer.PrintT(fmt.Sprintf("Moved channel {{.Name}} to %q ({{.TeamId}}) from %s.", team.Name, channel.TeamId), newChannel)
@@ -537,11 +537,11 @@ func deleteChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) erro
 	channels := getChannelsFromChannelArgs(c, args)
 	for i, channel := range channels {
 		if channel == nil {
-			printer.PrintError("Unable to find channel '" + args[i] + "'")
+			printItemError("Unable to find channel '"+args[i]+"'", ErrEntityNotFound{Type: "channel", ID: args[i]})
 			continue
 		}
 		if _, response := c.PermanentDeleteChannel(channel.Id); response.Error != nil {
-			printer.PrintError("Unable to delete channel '" + channel.Name + "' error: " + response.Error.Error())
+			printItemError("Unable to delete channel '"+channel.Name+"' error: "+response.Error.Error(), response.Error)
 		} else {
 			printer.PrintT("Deleted channel '{{.Name}}'", channel)
 		}
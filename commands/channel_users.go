@@ -5,7 +5,6 @@ package commands
 
 import (
 	"github.com/mattermost/mmctl/client"
-	"github.com/mattermost/mmctl/printer"
 
 	"github.com/mattermost/mattermost-server/v5/model"
 	"github.com/pkg/errors"
@@ -18,11 +17,17 @@ var ChannelUsersCmd = &cobra.Command{
 }
 
 var ChannelUsersAddCmd = &cobra.Command{
-	Use:     "add [channel] [users]",
-	Short:   "Add users to channel",
-	Long:    "Add some users to channel",
-	Example: "  channel users add myteam:mychannel user@example.com username",
-	RunE:    withClient(channelUsersAddCmdF),
+	Use:   "add [channel] [users]",
+	Short: "Add users to channel",
+	Long:  "Add some users to channel",
+	Example: `  channel users add myteam:mychannel user@example.com username
+
+  # Add the users listed in a CSV file with "channel" and "email" or "username" columns
+  channel users add --from-file users.csv
+
+  # The channel can also be passed as an argument
+  channel users add myteam:mychannel --from-file users.csv`,
+	RunE: withClient(channelUsersAddCmdF),
 }
 
 var ChannelUsersRemoveCmd = &cobra.Command{
@@ -35,6 +40,8 @@ var ChannelUsersRemoveCmd = &cobra.Command{
 }
 
 func init() {
+	addParallelFlag(ChannelUsersAddCmd)
+	addFromFileFlags(ChannelUsersAddCmd)
 	ChannelUsersRemoveCmd.Flags().Bool("all-users", false, "Remove all users from the indicated channel.")
 
 	ChannelUsersCmd.AddCommand(
@@ -46,6 +53,10 @@ func init() {
 }
 
 func channelUsersAddCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	if path, _ := cmd.Flags().GetString("from-file"); path != "" {
+		return runFromFile(c, cmd, args, [][]string{{"channel"}, userColumns}, channelUsersAddCmdF)
+	}
+
 	if len(args) < 2 {
 		return errors.New("not enough arguments")
 	}
@@ -55,22 +66,28 @@ func channelUsersAddCmdF(c client.Client, cmd *cobra.Command, args []string) err
 		return errors.Errorf("unable to find channel %q", args[0])
 	}
 
-	users := getUsersFromUserArgs(c, args[1:])
-	for i, user := range users {
-		addUserToChannel(c, channel, user, args[i+1])
-	}
+	workers := getParallelWorkers(cmd)
+	users := getUsersFromUserArgsInParallel(c, args[1:], workers)
+	errs := runParallel(workers, len(users), func(i int) error {
+		return addUserToChannel(c, channel, users[i], args[i+1])
+	}, func(i int, err error) {
+		if err != nil {
+			printItemError(err.Error(), err)
+		}
+	})
+	printParallelSummary(workers, len(users), countErrors(errs))
 
 	return nil
 }
 
-func addUserToChannel(c client.Client, channel *model.Channel, user *model.User, userArg string) {
+func addUserToChannel(c client.Client, channel *model.Channel, user *model.User, userArg string) error {
 	if user == nil {
-		printer.PrintError("Can't find user '" + userArg + "'")
-		return
+		return errors.New("Can't find user '" + userArg + "'")
 	}
 	if _, response := c.AddChannelMember(channel.Id, user.Id); response.Error != nil {
-		printer.PrintError("Unable to add '" + userArg + "' to " + channel.Name + ". Error: " + response.Error.Error())
+		return errors.New("Unable to add '" + userArg + "' to " + channel.Name + ". Error: " + response.Error.Error())
 	}
+	return nil
 }
 
 func channelUsersRemoveCmdF(c client.Client, cmd *cobra.Command, args []string) error {
@@ -102,23 +119,23 @@ func channelUsersRemoveCmdF(c client.Client, cmd *cobra.Command, args []string)
 
 func removeUserFromChannel(c client.Client, channel *model.Channel, user *model.User, userArg string) {
 	if user == nil {
-		printer.PrintError("Can't find user '" + userArg + "'")
+		printItemError("Can't find user '"+userArg+"'", ErrEntityNotFound{Type: "user", ID: userArg})
 		return
 	}
 	if _, response := c.RemoveUserFromChannel(channel.Id, user.Id); response.Error != nil {
-		printer.PrintError("Unable to remove '" + userArg + "' from " + channel.Name + ". Error: " + response.Error.Error())
+		printItemError("Unable to remove '"+userArg+"' from "+channel.Name+". Error: "+response.Error.Error(), response.Error)
 	}
 }
 
 func removeAllUsersFromChannel(c client.Client, channel *model.Channel) {
 	members, response := c.GetChannelMembers(channel.Id, 0, 10000, "")
 	if response.Error != nil {
-		printer.PrintError("Unable to remove all users from " + channel.Name + ". Error: " + response.Error.Error())
+		printItemError("Unable to remove all users from "+channel.Name+". Error: "+response.Error.Error(), response.Error)
 	}
 
 	for _, member := range *members {
 		if _, response := c.RemoveUserFromChannel(channel.Id, member.UserId); response.Error != nil {
-			printer.PrintError("Unable to remove '" + member.UserId + "' from " + channel.Name + ". Error: " + response.Error.Error())
+			printItemError("Unable to remove '"+member.UserId+"' from "+channel.Name+". Error: "+response.Error.Error(), response.Error)
 		}
 	}
 }
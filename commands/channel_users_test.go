@@ -194,6 +194,54 @@ func (s *MmctlUnitTestSuite) TestChannelUsersAddCmdF() {
 		s.Equal("Unable to add '"+userEmail+"' to "+channelName+". Error: : Mock error, ",
 			printer.GetErrorLines()[0])
 	})
+	s.Run("Add multiple users in parallel keeping the order of the errors", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("parallel", 4, "")
+
+		userArgs := []string{}
+		for i := 0; i < 10; i++ {
+			userArg := fmt.Sprintf("user%d@example.com", i)
+			userArgs = append(userArgs, userArg)
+			user := &model.User{Id: fmt.Sprintf("user%d", i), Email: userArg}
+
+			s.client.
+				EXPECT().
+				GetUserByEmail(userArg, "").
+				Return(user, &model.Response{Error: nil}).
+				Times(1)
+
+			response := &model.Response{Error: nil}
+			if i%3 == 0 {
+				response = &model.Response{Error: &model.AppError{Message: "Mock error"}}
+			}
+			s.client.
+				EXPECT().
+				AddChannelMember(channelID, user.Id).
+				Return(&model.ChannelMember{}, response).
+				Times(1)
+		}
+
+		s.client.
+			EXPECT().
+			GetTeam(teamID, "").
+			Return(&mockTeam, &model.Response{Error: nil}).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetChannelByNameIncludeDeleted(channelName, teamID, "").
+			Return(&mockChannel, &model.Response{Error: nil}).
+			Times(1)
+
+		err := channelUsersAddCmdF(s.client, cmd, append([]string{channelArg}, userArgs...))
+		s.Require().Nil(err)
+		s.Len(printer.GetLines(), 0)
+		s.Require().Len(printer.GetErrorLines(), 4)
+		for i, index := range []int{0, 3, 6, 9} {
+			s.Equal("Unable to add '"+userArgs[index]+"' to "+channelName+". Error: : Mock error, ", printer.GetErrorLines()[i])
+		}
+		s.Equal("Finished processing 10 items: 6 succeeded, 4 failed", printer.GetWarningLines()[0])
+	})
 }
 
 func (s *MmctlUnitTestSuite) TestChannelUsersRemoveCmd() {
@@ -196,12 +196,12 @@ func listCommandCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 
 	for i, team := range teams {
 		if team == nil {
-			printer.PrintError("Unable to find team '" + args[i] + "'")
+			printItemError("Unable to find team '"+args[i]+"'", ErrEntityNotFound{Type: "team", ID: args[i]})
 			continue
 		}
 		commands, response := c.ListCommands(team.Id, true)
 		if response.Error != nil {
-			printer.PrintError("Unable to list commands for '" + team.Id + "'")
+			printItemError("Unable to list commands for '"+team.Id+"'", response.Error)
 			continue
 		}
 		for _, command := range commands {
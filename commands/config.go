@@ -4,6 +4,7 @@
 package commands
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -21,6 +22,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 const defaultEditor = "vi"
@@ -40,12 +42,18 @@ var ConfigGetCmd = &cobra.Command{
 }
 
 var ConfigSetCmd = &cobra.Command{
-	Use:     "set",
-	Short:   "Set config setting",
-	Long:    "Sets the value of a config setting by its name in dot notation. Accepts multiple values for array settings",
-	Example: "config set SqlSettings.DriverName mysql\nconfig set SqlSettings.DataSourceReplicas \"replica1\" \"replica2\"",
-	Args:    cobra.MinimumNArgs(2),
-	RunE:    withClient(configSetCmdF),
+	Use:   "set",
+	Short: "Set config setting",
+	Long: `Sets the value of a config setting by its name in dot notation. Accepts multiple values for array settings.
+Values can be written as JSON literals, which allows to set settings of any type, and elements can be appended to or removed from array settings.`,
+	Example: `config set SqlSettings.DriverName mysql
+config set SqlSettings.DataSourceReplicas "replica1" "replica2"
+config set --append SqlSettings.DataSourceReplicas "replica3"
+config set --remove SqlSettings.DataSourceReplicas "replica1"
+config set PluginSettings.Plugins.com.mattermost.demo-plugin.channelname demo
+config set PluginSettings.Plugins.com.mattermost.demo-plugin --json '{"channelname": "demo", "enablementionuser": true}'`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: withClient(configSetCmdF),
 }
 
 var ConfigPatchCmd = &cobra.Command{
@@ -76,12 +84,13 @@ var ConfigResetCmd = &cobra.Command{
 }
 
 var ConfigShowCmd = &cobra.Command{
-	Use:     "show",
-	Short:   "Writes the server configuration to STDOUT",
-	Long:    "Prints the server configuration and writes to STDOUT in JSON format.",
-	Example: "config show",
-	Args:    cobra.NoArgs,
-	RunE:    withClient(configShowCmdF),
+	Use:   "show",
+	Short: "Writes the server configuration to STDOUT",
+	Long:  "Prints the server configuration and writes to STDOUT in JSON format. The secrets of the configuration, like passwords and keys, can be masked with the --redact flag.",
+	Example: `config show
+config show --redact`,
+	Args: cobra.NoArgs,
+	RunE: withClient(configShowCmdF),
 }
 
 var ConfigReloadCmd = &cobra.Command{
@@ -102,6 +111,29 @@ var ConfigMigrateCmd = &cobra.Command{
 	RunE:    withClient(configMigrateCmdF),
 }
 
+var ConfigDiffCmd = &cobra.Command{
+	Use:   "diff [config-file]",
+	Short: "Show the differences of the config",
+	Long: `Shows the settings of the server's configuration that differ from the ones of a config file, or from the ones of the server of other saved credentials.
+The config file can contain only some of the settings, in which case the rest of them are not compared.`,
+	Example: `  # compare the config with a file
+  mmctl config diff /path/to/config.json
+
+  # compare the config with the one of the server of the "production" credentials
+  mmctl config diff --server production`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: withClient(configDiffCmdF),
+}
+
+var ConfigValidateCmd = &cobra.Command{
+	Use:     "validate <config-file>",
+	Short:   "Validate a config file",
+	Long:    "Validates a config file locally, filling the missing settings with their default values and checking them the same way the server does, without sending anything to it.",
+	Example: "config validate /path/to/config.json",
+	Args:    cobra.ExactArgs(1),
+	RunE:    configValidateCmdF,
+}
+
 var ConfigSubpathCmd = &cobra.Command{
 	Use:   "subpath",
 	Short: "Update client asset loading to use the configured subpath",
@@ -119,8 +151,16 @@ var ConfigSubpathCmd = &cobra.Command{
 }
 
 func init() {
+	ConfigSetCmd.Flags().Bool("json", false, "the values are JSON literals")
+	ConfigSetCmd.Flags().Bool("append", false, "append the values to the array setting")
+	ConfigSetCmd.Flags().Bool("remove", false, "remove the values from the array setting")
+
 	ConfigResetCmd.Flags().Bool("confirm", false, "confirm you really want to reset all configuration settings to its default value")
 
+	ConfigShowCmd.Flags().Bool("redact", false, "mask the secrets of the configuration")
+
+	ConfigDiffCmd.Flags().String("server", "", "name of the saved credentials of the server to compare the config with")
+
 	ConfigSubpathCmd.Flags().StringP("assets-dir", "a", "", "directory of the Mattermost assets in the local filesystem")
 	_ = ConfigSubpathCmd.MarkFlagRequired("assets-dir")
 	ConfigSubpathCmd.Flags().StringP("path", "p", "", "path to update the assets with")
@@ -136,6 +176,8 @@ func init() {
 		ConfigReloadCmd,
 		ConfigMigrateCmd,
 		ConfigSubpathCmd,
+		ConfigDiffCmd,
+		ConfigValidateCmd,
 	)
 	RootCmd.AddCommand(ConfigCmd)
 }
@@ -161,12 +203,14 @@ func getValue(path []string, obj interface{}) (interface{}, bool) {
 		return val.Interface(), true
 	case val.Kind() == reflect.Struct:
 		return getValue(path[1:], val.Interface())
+	case val.Kind() == reflect.Ptr && val.Elem().Kind() == reflect.Struct:
+		return getValue(path[1:], val.Elem().Interface())
 	case val.Kind() == reflect.Map:
 		remainingPath := strings.Join(path[1:], ".")
 		mapIter := val.MapRange()
 		for mapIter.Next() {
 			key := mapIter.Key().String()
-			if strings.HasPrefix(remainingPath, key) {
+			if remainingPath == key || strings.HasPrefix(remainingPath, key+".") {
 				i := strings.Count(key, ".") + 2 // number of dots + a dot on each side
 				mapVal := mapIter.Value()
 				// if no sub field path specified, return the object
@@ -185,42 +229,249 @@ func getValue(path []string, obj interface{}) (interface{}, bool) {
 	return nil, false
 }
 
-func setValueWithConversion(val reflect.Value, newValue interface{}) error {
-	switch val.Kind() {
-	case reflect.Struct:
-		val.Set(reflect.ValueOf(newValue))
-		return nil
-	case reflect.Slice:
-		if val.Type().Elem().Kind() != reflect.String {
-			return errors.New("unsupported type of slice")
+// configField is a setting of the config and its type
+type configField struct {
+	Key  string
+	Type reflect.Type
+}
+
+// getConfigFields returns every setting of the config type with its
+// name in dot notation. Maps are considered a single setting
+func getConfigFields(t reflect.Type, prefix string) []configField {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	fields := []configField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
 		}
-		v := reflect.ValueOf(newValue)
-		if v.Kind() != reflect.Slice {
-			return errors.New("target value is of type Array and provided value is not")
+
+		key := field.Name
+		if prefix != "" {
+			key = prefix + "." + key
 		}
-		val.Set(v)
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct {
+			fields = append(fields, getConfigFields(fieldType, key)...)
+			continue
+		}
+		fields = append(fields, configField{Key: key, Type: fieldType})
+	}
+	return fields
+}
+
+// getConfigKeys returns the name in dot notation of every setting of
+// the config type
+func getConfigKeys(t reflect.Type, prefix string) []string {
+	keys := []string{}
+	for _, field := range getConfigFields(t, prefix) {
+		keys = append(keys, field.Key)
+	}
+	return keys
+}
+
+// configDiff is a setting that has a different value in two configs
+type configDiff struct {
+	Setting string      `json:"setting"`
+	Current interface{} `json:"current"`
+	Target  interface{} `json:"target"`
+}
+
+func (d *configDiff) CurrentValue() string {
+	return formatConfigValue(d.Current)
+}
+
+func (d *configDiff) TargetValue() string {
+	return formatConfigValue(d.Target)
+}
+
+func formatConfigValue(value interface{}) string {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// dereferenceConfigValue returns the value a setting points to, or nil
+// if the setting is not set
+func dereferenceConfigValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr {
+		return value
+	}
+	if v.IsNil() {
 		return nil
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		bits := val.Type().Bits()
-		v, err := strconv.ParseInt(newValue.(string), 10, bits)
+	}
+	return v.Elem().Interface()
+}
+
+// diffConfigs returns the settings that have different values in the
+// two configs. Settings hidden by the server can't be compared, so
+// they are skipped
+func diffConfigs(current, target *model.Config) []*configDiff {
+	diffs := []*configDiff{}
+	for _, key := range getConfigKeys(reflect.TypeOf(*current), "") {
+		path := parseConfigPath(key)
+		currentValue, _ := getValue(path, *current)
+		targetValue, _ := getValue(path, *target)
+		currentValue = dereferenceConfigValue(currentValue)
+		targetValue = dereferenceConfigValue(targetValue)
+
+		if reflect.DeepEqual(currentValue, targetValue) {
+			continue
+		}
+		if currentValue == model.FAKE_SETTING || targetValue == model.FAKE_SETTING {
+			continue
+		}
+
+		diffs = append(diffs, &configDiff{Setting: key, Current: currentValue, Target: targetValue})
+	}
+	return diffs
+}
+
+func getConfigFromCredentials(name string) (*model.Config, error) {
+	credentials, err := GetCredentials(name)
+	if err != nil {
+		return nil, err
+	}
+
+	restore := applyContextDefaults(credentials)
+	defer restore()
+	c, _, err := InitClientWithCredentials(credentials, viper.GetBool("insecure-sha1-intermediate"), viper.GetBool("insecure-tls-version"))
+	if err != nil {
+		return nil, err
+	}
+
+	config, response := c.GetConfig()
+	if response.Error != nil {
+		return nil, errors.Wrapf(response.Error, "failed to get the config of server %s", name)
+	}
+	return config, nil
+}
+
+// jsonConfigValue is a value for a setting written as a JSON literal
+type jsonConfigValue string
+
+// convertConfigValue converts the string representation of a value to
+// the given type. Structs and maps are expected in JSON
+func convertConfigValue(t reflect.Type, value string) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Ptr:
+		elem, err := convertConfigValue(t.Elem(), value)
 		if err != nil {
-			return fmt.Errorf("target value is of type %v and provided value is not", val.Kind())
+			return v, err
 		}
-		val.SetInt(v)
-		return nil
+		v.Set(reflect.New(t.Elem()))
+		v.Elem().Set(elem)
 	case reflect.String:
-		val.SetString(newValue.(string))
-		return nil
+		v.SetString(value)
 	case reflect.Bool:
-		v, err := strconv.ParseBool(newValue.(string))
+		b, err := strconv.ParseBool(value)
 		if err != nil {
-			return errors.New("target value is of type Bool and provided value is not")
+			return v, errors.New("target value is of type Bool and provided value is not")
 		}
-		val.SetBool(v)
-		return nil
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, t.Bits())
+		if err != nil {
+			return v, fmt.Errorf("target value is of type %v and provided value is not", t.Kind())
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, t.Bits())
+		if err != nil {
+			return v, fmt.Errorf("target value is of type %v and provided value is not", t.Kind())
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, t.Bits())
+		if err != nil {
+			return v, fmt.Errorf("target value is of type %v and provided value is not", t.Kind())
+		}
+		v.SetFloat(f)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(value))
+	case reflect.Struct, reflect.Map:
+		return decodeConfigValue(t, value)
 	default:
-		return errors.New("target value type is not supported")
+		return v, errors.New("target value type is not supported")
+	}
+	return v, nil
+}
+
+// decodeConfigValue converts a JSON literal to the given type
+func decodeConfigValue(t reflect.Type, value string) (reflect.Value, error) {
+	v := reflect.New(t)
+	if err := json.Unmarshal([]byte(value), v.Interface()); err != nil {
+		return v.Elem(), fmt.Errorf("target value is of type %v and provided value is not valid for it: %s", t.Kind(), err)
 	}
+	return v.Elem(), nil
+}
+
+func setValueWithConversion(val reflect.Value, newValue interface{}) error {
+	var converted reflect.Value
+	var err error
+	switch value := newValue.(type) {
+	case jsonConfigValue:
+		converted, err = decodeConfigValue(val.Type(), string(value))
+	case string:
+		if val.Kind() == reflect.Slice {
+			return errors.New("target value is of type Array and provided value is not")
+		}
+		converted, err = convertConfigValue(val.Type(), value)
+	case []string:
+		if val.Kind() != reflect.Slice {
+			return fmt.Errorf("target value is of type %v and multiple values were provided", val.Kind())
+		}
+		converted = reflect.MakeSlice(val.Type(), len(value), len(value))
+		for i, item := range value {
+			var elem reflect.Value
+			if elem, err = convertConfigValue(val.Type().Elem(), item); err != nil {
+				break
+			}
+			converted.Index(i).Set(elem)
+		}
+	default:
+		converted = reflect.ValueOf(newValue)
+		if !converted.IsValid() || !converted.Type().AssignableTo(val.Type()) {
+			return errors.New("target value type is not supported")
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	val.Set(converted)
+	return nil
+}
+
+// newConfigMapEntry adds an empty entry to a map of maps or structs, so
+// its settings can be set before it exists in the config
+func newConfigMapEntry(m reflect.Value, key string) (reflect.Value, bool) {
+	var entry reflect.Value
+	switch elemType := m.Type().Elem(); {
+	case elemType.Kind() == reflect.Map:
+		entry = reflect.MakeMap(elemType)
+		m.SetMapIndex(reflect.ValueOf(key), entry)
+	case elemType.Kind() == reflect.Ptr && elemType.Elem().Kind() == reflect.Struct:
+		ptr := reflect.New(elemType.Elem())
+		m.SetMapIndex(reflect.ValueOf(key), ptr)
+		entry = ptr.Elem()
+	default:
+		return entry, false
+	}
+	return entry, true
 }
 
 func setValue(path []string, obj reflect.Value, newValue interface{}) error {
@@ -232,6 +483,14 @@ func setValue(path []string, obj reflect.Value, newValue interface{}) error {
 		val = obj.MapIndex(reflect.ValueOf(path[0]))
 		if val.IsValid() {
 			val = val.Elem()
+		} else if len(path) == 1 {
+			// the key doesn't exist yet, so it is added to the map
+			tmpVal := reflect.New(obj.Type().Elem())
+			if err := setValueWithConversion(tmpVal.Elem(), newValue); err != nil {
+				return err
+			}
+			obj.SetMapIndex(reflect.ValueOf(path[0]), tmpVal.Elem())
+			return nil
 		}
 	default:
 		val = obj
@@ -243,23 +502,49 @@ func setValue(path []string, obj reflect.Value, newValue interface{}) error {
 
 	if len(path) == 1 {
 		if val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				if _, isJSON := newValue.(jsonConfigValue); isJSON && val.CanSet() {
+					return setValueWithConversion(val, newValue)
+				}
+				if !val.CanSet() {
+					return errors.New("selected path object is not valid")
+				}
+				val.Set(reflect.New(val.Type().Elem()))
+			}
 			return setValue(path, val.Elem(), newValue)
 		} else if obj.Kind() == reflect.Map {
 			// since we cannot set map elements directly, we clone the value, set it, and then put it back in the map
 			mapKey := reflect.ValueOf(path[0])
 			subVal := obj.MapIndex(mapKey)
 			if subVal.IsValid() {
-				tmpVal := reflect.New(subVal.Elem().Type())
+				// settings stored as interfaces keep the type of their
+				// current value, unless a JSON literal is provided
+				elemType := obj.Type().Elem()
+				if _, isJSON := newValue.(jsonConfigValue); !isJSON && elemType.Kind() == reflect.Interface && subVal.Elem().IsValid() {
+					elemType = subVal.Elem().Type()
+				}
+				tmpVal := reflect.New(elemType)
 				if err := setValueWithConversion(tmpVal.Elem(), newValue); err != nil {
 					return err
 				}
-				obj.SetMapIndex(mapKey, tmpVal)
+				obj.SetMapIndex(mapKey, tmpVal.Elem())
 				return nil
 			}
 		}
 		return setValueWithConversion(val, newValue)
 	}
 
+	// pointers to structs are created if they are not set
+	if val.Kind() == reflect.Ptr && val.Type().Elem().Kind() == reflect.Struct {
+		if val.IsNil() {
+			if !val.CanSet() {
+				return errors.New("selected path object is not valid")
+			}
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		val = val.Elem()
+	}
+
 	if val.Kind() == reflect.Struct {
 		return setValue(path[1:], val, newValue)
 	} else if val.Kind() == reflect.Map {
@@ -267,22 +552,36 @@ func setValue(path []string, obj reflect.Value, newValue interface{}) error {
 		mapIter := val.MapRange()
 		for mapIter.Next() {
 			key := mapIter.Key().String()
-			if strings.HasPrefix(remainingPath, key) {
-				mapVal := mapIter.Value()
+			if remainingPath == key || strings.HasPrefix(remainingPath, key+".") {
+				i := len(strings.Split(key, ".")) + 1
+				if i > len(path)-1 { // leaf element
+					return setValue([]string{key}, val, newValue)
+				}
 
+				mapVal := mapIter.Value()
 				if mapVal.Kind() == reflect.Ptr {
 					mapVal = mapVal.Elem() // if value is a pointer, dereference it
 				}
-				i := len(strings.Split(key, ".")) + 1
-
-				if i > len(path)-1 { // leaf element
-					i = 1
-					mapVal = val
-				}
 				// pass subpath
 				return setValue(path[i:], mapVal, newValue)
 			}
 		}
+
+		if val.IsNil() {
+			if !val.CanSet() {
+				return errors.New("selected path object is not valid")
+			}
+			val.Set(reflect.MakeMap(val.Type()))
+		}
+
+		// if no entry matches, a JSON value is the whole new entry, and
+		// otherwise the last element of the path is a setting of it
+		if _, isJSON := newValue.(jsonConfigValue); isJSON || len(path) == 2 {
+			return setValue([]string{remainingPath}, val, newValue)
+		}
+		if entry, ok := newConfigMapEntry(val, strings.Join(path[1:len(path)-1], ".")); ok {
+			return setValue(path[len(path)-1:], entry, newValue)
+		}
 	}
 	return errors.New("path object type is not supported")
 }
@@ -294,20 +593,55 @@ func setConfigValue(path []string, config *model.Config, newValue []string) erro
 	return setValue(path, reflect.ValueOf(config).Elem(), newValue[0])
 }
 
-func resetConfigValue(path []string, config *model.Config, newValue interface{}) error {
-	nv := reflect.ValueOf(newValue)
-	if nv.Kind() == reflect.Ptr {
-		switch nv.Elem().Kind() {
-		case reflect.Int:
-			return setValue(path, reflect.ValueOf(config).Elem(), strconv.Itoa(*newValue.(*int)))
-		case reflect.Bool:
-			return setValue(path, reflect.ValueOf(config).Elem(), strconv.FormatBool(*newValue.(*bool)))
-		default:
-			return setValue(path, reflect.ValueOf(config).Elem(), *newValue.(*string))
+// updateConfigSlice adds or removes elements of an array setting
+func updateConfigSlice(path []string, config *model.Config, values []string, isJSON, remove bool) error {
+	current, ok := getValue(path, *config)
+	if !ok {
+		return errors.New("invalid key")
+	}
+
+	slice := reflect.ValueOf(dereferenceConfigValue(current))
+	if slice.Kind() != reflect.Slice {
+		return errors.New("elements can only be appended to or removed from array settings")
+	}
+
+	elems := []reflect.Value{}
+	for _, value := range values {
+		var elem reflect.Value
+		var err error
+		if isJSON {
+			elem, err = decodeConfigValue(slice.Type().Elem(), value)
+		} else {
+			elem, err = convertConfigValue(slice.Type().Elem(), value)
+		}
+		if err != nil {
+			return err
+		}
+		elems = append(elems, elem)
+	}
+
+	newSlice := reflect.MakeSlice(slice.Type(), 0, slice.Len()+len(elems))
+	for i := 0; i < slice.Len(); i++ {
+		removed := false
+		for _, elem := range elems {
+			if remove && reflect.DeepEqual(slice.Index(i).Interface(), elem.Interface()) {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			newSlice = reflect.Append(newSlice, slice.Index(i))
 		}
-	} else {
-		return setValue(path, reflect.ValueOf(config).Elem(), newValue)
 	}
+	if !remove {
+		newSlice = reflect.Append(newSlice, elems...)
+	}
+
+	return setValue(path, reflect.ValueOf(config).Elem(), newSlice.Interface())
+}
+
+func resetConfigValue(path []string, config *model.Config, newValue interface{}) error {
+	return setValue(path, reflect.ValueOf(config).Elem(), dereferenceConfigValue(newValue))
 }
 
 func configGetCmdF(c client.Client, _ *cobra.Command, args []string) error {
@@ -329,20 +663,42 @@ func configGetCmdF(c client.Client, _ *cobra.Command, args []string) error {
 	return nil
 }
 
-func configSetCmdF(c client.Client, _ *cobra.Command, args []string) error {
+func configSetCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	isJSON, _ := cmd.Flags().GetBool("json")
+	appendFlag, _ := cmd.Flags().GetBool("append")
+	removeFlag, _ := cmd.Flags().GetBool("remove")
+	if appendFlag && removeFlag {
+		return errors.New("the --append and --remove flags can't be used together")
+	}
+	if isJSON && !appendFlag && !removeFlag && len(args) > 2 {
+		return errors.New("only one JSON value can be set, use an array literal to set an array setting")
+	}
+
 	config, response := c.GetConfig()
 	if response.Error != nil {
 		return response.Error
 	}
 
+	previousConfig := config.Clone()
+
 	path := parseConfigPath(args[0])
-	if err := setConfigValue(path, config, args[1:]); err != nil {
+	var err error
+	switch {
+	case appendFlag || removeFlag:
+		err = updateConfigSlice(path, config, args[1:], isJSON, removeFlag)
+	case isJSON:
+		err = setValue(path, reflect.ValueOf(config).Elem(), jsonConfigValue(args[1]))
+	default:
+		err = setConfigValue(path, config, args[1:])
+	}
+	if err != nil {
 		return err
 	}
 	newConfig, res := c.PatchConfig(config)
 	if res.Error != nil {
 		return res.Error
 	}
+	snapshotReplacedConfig(previousConfig, "config set")
 
 	printer.PrintT("Value changed successfully", newConfig)
 	return nil
@@ -359,6 +715,8 @@ func configPatchCmdF(c client.Client, _ *cobra.Command, args []string) error {
 		return res.Error
 	}
 
+	previousConfig := config.Clone()
+
 	if err := json.Unmarshal(configBytes, config); err != nil {
 		return err
 	}
@@ -367,6 +725,7 @@ func configPatchCmdF(c client.Client, _ *cobra.Command, args []string) error {
 	if res.Error != nil {
 		return res.Error
 	}
+	snapshotReplacedConfig(previousConfig, "config patch")
 
 	printer.PrintT("Config patched successfully", newConfig)
 	return nil
@@ -378,6 +737,8 @@ func configEditCmdF(c client.Client, _ *cobra.Command, _ []string) error {
 		return response.Error
 	}
 
+	previousConfig := config.Clone()
+
 	configBytes, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return err
@@ -422,6 +783,7 @@ func configEditCmdF(c client.Client, _ *cobra.Command, _ []string) error {
 	if response.Error != nil {
 		return response.Error
 	}
+	snapshotReplacedConfig(previousConfig, "config edit")
 
 	printer.PrintT("Config updated successfully", newConfig)
 	return nil
@@ -450,6 +812,8 @@ func configResetCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 		return response.Error
 	}
 
+	previousConfig := config.Clone()
+
 	for _, arg := range args {
 		path := parseConfigPath(arg)
 		defaultValue, ok := getValue(path, *defaultConfig)
@@ -465,12 +829,26 @@ func configResetCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	if res.Error != nil {
 		return res.Error
 	}
+	snapshotReplacedConfig(previousConfig, "config reset")
 
 	printer.PrintT("Value/s reset successfully", newConfig)
 	return nil
 }
 
-func configShowCmdF(c client.Client, _ *cobra.Command, _ []string) error {
+// redactConfig masks the secrets of the config, the ones the server
+// masks when it sends the config to the clients and the data sources of
+// the replica lag settings
+func redactConfig(config *model.Config) {
+	config.Sanitize()
+
+	for _, replicaLag := range config.SqlSettings.ReplicaLagSettings {
+		if replicaLag != nil && replicaLag.DataSource != nil {
+			*replicaLag.DataSource = model.FAKE_SETTING
+		}
+	}
+}
+
+func configShowCmdF(c client.Client, cmd *cobra.Command, _ []string) error {
 	printer.SetSingle(true)
 	printer.SetFormat(printer.FormatJSON)
 	config, response := c.GetConfig()
@@ -478,11 +856,85 @@ func configShowCmdF(c client.Client, _ *cobra.Command, _ []string) error {
 		return response.Error
 	}
 
+	if redact, _ := cmd.Flags().GetBool("redact"); redact {
+		redactConfig(config)
+	}
+
 	printer.Print(config)
 
 	return nil
 }
 
+func configDiffCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	server, _ := cmd.Flags().GetString("server")
+	if (len(args) == 0) == (server == "") {
+		return errors.New("either a config file or the --server flag must be specified")
+	}
+
+	config, response := c.GetConfig()
+	if response.Error != nil {
+		return response.Error
+	}
+
+	var target *model.Config
+	if server != "" {
+		var err error
+		if target, err = getConfigFromCredentials(server); err != nil {
+			return err
+		}
+	} else {
+		configBytes, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+
+		// the file is applied over the current config, so only the
+		// settings present in it are compared
+		target = config.Clone()
+		if err := json.Unmarshal(configBytes, target); err != nil {
+			return errors.Wrap(err, "failed to parse the config file")
+		}
+	}
+
+	diffs := diffConfigs(config, target)
+	if len(diffs) == 0 {
+		printer.Print("No differences found")
+		return nil
+	}
+
+	for _, diff := range diffs {
+		printer.PrintT("{{.Setting}}: {{.CurrentValue}} => {{.TargetValue}}", diff)
+	}
+
+	return nil
+}
+
+func configValidateCmdF(_ *cobra.Command, args []string) error {
+	configBytes, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	config := &model.Config{}
+	decoder := json.NewDecoder(bytes.NewReader(configBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return errors.Wrap(err, "failed to parse the config file")
+	}
+
+	config.SetDefaults()
+	if appErr := config.IsValid(); appErr != nil {
+		msg := appErr.Id
+		if appErr.DetailedError != "" {
+			msg += ": " + appErr.DetailedError
+		}
+		return errors.Errorf("the config is not valid: %s", msg)
+	}
+
+	printer.Print("The config is valid")
+	return nil
+}
+
 func parseConfigPath(configPath string) []string {
 	return strings.Split(configPath, ".")
 }
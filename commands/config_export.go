@@ -0,0 +1,129 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/client"
+	"github.com/mattermost/mmctl/printer"
+)
+
+const configEnvPrefix = "MM_"
+
+// configEnvSafeValue matches the values that can be used in a shell
+// without quoting them
+var configEnvSafeValue = regexp.MustCompile(`^[a-zA-Z0-9_./:@%+,=-]*$`)
+
+var ConfigExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the server configuration",
+	Long: `Exports the server configuration in JSON format or, with the --env flag, as the environment variables the server reads its configuration from, like MM_SERVICESETTINGS_SITEURL.
+Settings that are not set are not exported as environment variables, and neither are the settings that can't be set through them, like the plugin settings.`,
+	Example: `  # export the config as environment variables
+  mmctl config export --env
+
+  # export the config as environment variables with its secrets masked
+  mmctl config export --env --redact`,
+	Args: cobra.NoArgs,
+	RunE: withClient(configExportCmdF),
+}
+
+func init() {
+	ConfigExportCmd.Flags().Bool("env", false, "export the config as environment variables")
+	ConfigExportCmd.Flags().Bool("redact", false, "mask the secrets of the configuration")
+
+	ConfigCmd.AddCommand(ConfigExportCmd)
+}
+
+// configEnvVar is a setting of the config as an environment variable
+type configEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// QuotedValue returns the value quoted to be used in a shell, if it
+// needs to be
+func (e *configEnvVar) QuotedValue() string {
+	if configEnvSafeValue.MatchString(e.Value) {
+		return e.Value
+	}
+	return "'" + strings.Replace(e.Value, "'", `'\''`, -1) + "'"
+}
+
+// formatConfigEnvValue formats the value of a setting the way the
+// server parses it from an environment variable. Settings that can't be
+// set through environment variables are reported as not supported
+func formatConfigEnvValue(value interface{}) (string, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64:
+		return fmt.Sprint(value), true
+	case reflect.Slice:
+		if values, ok := value.([]string); ok {
+			return strings.Join(values, " "), true
+		}
+	}
+	return "", false
+}
+
+// getConfigEnvVars returns the environment variables that set each of
+// the settings of the config, and the settings that can't be set
+// through them
+func getConfigEnvVars(config *model.Config) ([]*configEnvVar, []string) {
+	envVars := []*configEnvVar{}
+	unsupported := []string{}
+	for _, key := range getConfigKeys(reflect.TypeOf(*config), "") {
+		value, _ := getValue(parseConfigPath(key), *config)
+		value = dereferenceConfigValue(value)
+		if value == nil {
+			continue
+		}
+
+		envValue, ok := formatConfigEnvValue(value)
+		if !ok {
+			unsupported = append(unsupported, key)
+			continue
+		}
+
+		envVars = append(envVars, &configEnvVar{
+			Name:  configEnvPrefix + strings.ToUpper(strings.Replace(key, ".", "_", -1)),
+			Value: envValue,
+		})
+	}
+	return envVars, unsupported
+}
+
+func configExportCmdF(c client.Client, cmd *cobra.Command, _ []string) error {
+	config, response := c.GetConfig()
+	if response.Error != nil {
+		return response.Error
+	}
+
+	if redact, _ := cmd.Flags().GetBool("redact"); redact {
+		redactConfig(config)
+	}
+
+	if env, _ := cmd.Flags().GetBool("env"); !env {
+		printer.SetSingle(true)
+		printer.SetFormat(printer.FormatJSON)
+		printer.Print(config)
+		return nil
+	}
+
+	envVars, unsupported := getConfigEnvVars(config)
+	for _, envVar := range envVars {
+		printer.PrintT("{{.Name}}={{.QuotedValue}}", envVar)
+	}
+	if len(unsupported) > 0 {
+		printer.PrintWarning("The following settings can't be set through environment variables and were not exported: " + strings.Join(unsupported, ", "))
+	}
+	return nil
+}
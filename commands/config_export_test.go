@@ -0,0 +1,106 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestConfigExportCmd() {
+	newConfig := func() *model.Config {
+		config := &model.Config{}
+		config.SetDefaults()
+		config.ServiceSettings.SiteURL = model.NewString("https://mattermost.example.com")
+		config.TeamSettings.SiteName = model.NewString("It's Mattermost")
+		config.SqlSettings.DataSourceReplicas = []string{"replica1", "replica2"}
+		config.PluginSettings.Plugins = map[string]map[string]interface{}{"com.example.plugin": {"key": "value"}}
+		return config
+	}
+
+	getEnvVar := func(name string) *configEnvVar {
+		for _, line := range printer.GetLines() {
+			if envVar := line.(*configEnvVar); envVar.Name == name {
+				return envVar
+			}
+		}
+		return nil
+	}
+
+	s.Run("Export the config as environment variables", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("env", true, "")
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(newConfig(), &model.Response{Error: nil}).
+			Times(1)
+
+		err := configExportCmdF(s.client, cmd, []string{})
+		s.Require().Nil(err)
+
+		siteURL := getEnvVar("MM_SERVICESETTINGS_SITEURL")
+		s.Require().NotNil(siteURL)
+		s.Require().Equal("https://mattermost.example.com", siteURL.QuotedValue())
+
+		siteName := getEnvVar("MM_TEAMSETTINGS_SITENAME")
+		s.Require().NotNil(siteName)
+		s.Require().Equal(`'It'\''s Mattermost'`, siteName.QuotedValue())
+
+		replicas := getEnvVar("MM_SQLSETTINGS_DATASOURCEREPLICAS")
+		s.Require().NotNil(replicas)
+		s.Require().Equal("replica1 replica2", replicas.Value)
+
+		enableDeveloper := getEnvVar("MM_SERVICESETTINGS_ENABLEDEVELOPER")
+		s.Require().NotNil(enableDeveloper)
+		s.Require().Equal("false", enableDeveloper.Value)
+
+		s.Require().Nil(getEnvVar("MM_PLUGINSETTINGS_PLUGINS"))
+		s.Require().Len(printer.GetWarningLines(), 1)
+		s.Require().Equal("The following settings can't be set through environment variables and were not exported: SqlSettings.ReplicaLagSettings, PluginSettings.Plugins, PluginSettings.PluginStates", printer.GetWarningLines()[0])
+	})
+
+	s.Run("Export the config as environment variables with the secrets redacted", func() {
+		printer.Clean()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("env", true, "")
+		cmd.Flags().Bool("redact", true, "")
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(newConfig(), &model.Response{Error: nil}).
+			Times(1)
+
+		err := configExportCmdF(s.client, cmd, []string{})
+		s.Require().Nil(err)
+
+		dataSource := getEnvVar("MM_SQLSETTINGS_DATASOURCE")
+		s.Require().NotNil(dataSource)
+		s.Require().Equal(model.FAKE_SETTING, dataSource.Value)
+		s.Require().Equal("'"+model.FAKE_SETTING+"'", dataSource.QuotedValue())
+	})
+
+	s.Run("Export the config as JSON", func() {
+		printer.Clean()
+		config := newConfig()
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{Error: nil}).
+			Times(1)
+
+		err := configExportCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(config, printer.GetLines()[0])
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+var ConfigKeysCmd = &cobra.Command{
+	Use:   "keys [prefix]",
+	Short: "List the config settings",
+	Long:  "Lists the name in dot notation of every config setting, optionally only the ones that start with a prefix, along with its type and default value.",
+	Example: `  # list all the settings
+  mmctl config keys
+
+  # list the settings of a section
+  mmctl config keys ServiceSettings.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeFirstConfigKey,
+	RunE:              configKeysCmdF,
+}
+
+func init() {
+	ConfigGetCmd.ValidArgsFunction = completeFirstConfigKey
+	ConfigSetCmd.ValidArgsFunction = completeFirstConfigKey
+	ConfigResetCmd.ValidArgsFunction = completeConfigKeys
+
+	ConfigCmd.AddCommand(ConfigKeysCmd)
+}
+
+// configKey is a setting of the config printed by the keys command
+type configKey struct {
+	Key     string      `json:"key"`
+	Type    string      `json:"type"`
+	Default interface{} `json:"default"`
+}
+
+func (k *configKey) DefaultValue() string {
+	return formatConfigValue(k.Default)
+}
+
+// completeConfigKeys completes the names of the config settings one
+// section at a time, so the sections are suggested before their
+// settings
+func completeConfigKeys(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	directive := cobra.ShellCompDirectiveNoFileComp
+	suggestions := []string{}
+	seen := map[string]bool{}
+	for _, key := range getConfigKeys(reflect.TypeOf(model.Config{}), "") {
+		if !strings.HasPrefix(key, toComplete) {
+			continue
+		}
+
+		if i := strings.Index(key[len(toComplete):], "."); i != -1 {
+			key = key[:len(toComplete)+i+1]
+			directive |= cobra.ShellCompDirectiveNoSpace
+		}
+		if !seen[key] {
+			seen[key] = true
+			suggestions = append(suggestions, key)
+		}
+	}
+	return suggestions, directive
+}
+
+// completeFirstConfigKey completes the name of a config setting only
+// for the first argument of the command
+func completeFirstConfigKey(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeConfigKeys(cmd, args, toComplete)
+}
+
+func configKeysCmdF(_ *cobra.Command, args []string) error {
+	prefix := ""
+	if len(args) > 0 {
+		prefix = args[0]
+	}
+
+	defaultConfig := &model.Config{}
+	defaultConfig.SetDefaults()
+
+	keys := []*configKey{}
+	for _, field := range getConfigFields(reflect.TypeOf(*defaultConfig), "") {
+		if !strings.HasPrefix(field.Key, prefix) {
+			continue
+		}
+
+		defaultValue, _ := getValue(parseConfigPath(field.Key), *defaultConfig)
+		keys = append(keys, &configKey{
+			Key:     field.Key,
+			Type:    field.Type.String(),
+			Default: dereferenceConfigValue(defaultValue),
+		})
+	}
+
+	if len(keys) == 0 {
+		return fmt.Errorf("there are no config settings starting with %q", prefix)
+	}
+
+	for _, key := range keys {
+		printer.PrintT("{{.Key}} ({{.Type}}): {{.DefaultValue}}", key)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/mattermost/mmctl/client"
+	"github.com/mattermost/mmctl/printer"
+)
+
+const (
+	configSnapshotsDirSuffix = "-snapshots"
+	configSnapshotIDFormat   = "20060102-150405.000"
+)
+
+var ConfigHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the config snapshots",
+	Long: `Lists the snapshots of the server's configuration, from the newest to the oldest, along with the settings that differ from the current configuration.
+A snapshot of the configuration is saved locally every time a command modifies it.`,
+	Example: "config history",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(configHistoryCmdF),
+}
+
+var ConfigRollbackCmd = &cobra.Command{
+	Use:   "rollback <snapshot-id>",
+	Short: "Restore a config snapshot",
+	Long:  "Restores the server's configuration saved in a snapshot. A snapshot of the configuration being replaced is saved too, so the rollback can be undone.",
+	Example: `  # list the snapshots to find the one to restore
+  mmctl config history
+
+  # restore it
+  mmctl config rollback 20201015-093010.421`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(configRollbackCmdF),
+}
+
+func init() {
+	ConfigRollbackCmd.Flags().Bool("confirm", false, "confirm you really want to restore the snapshot")
+
+	ConfigCmd.AddCommand(
+		ConfigHistoryCmd,
+		ConfigRollbackCmd,
+	)
+}
+
+// ConfigSnapshot is a copy of the configuration of a server, saved
+// before a command modified it
+type ConfigSnapshot struct {
+	Id       string        `json:"id"`
+	Server   string        `json:"server"`
+	Command  string        `json:"command"`
+	CreateAt int64         `json:"create_at"`
+	Config   *model.Config `json:"config"`
+}
+
+// configSnapshotEntry is the summary of a snapshot printed by the
+// history command
+type configSnapshotEntry struct {
+	Id        string   `json:"id"`
+	Command   string   `json:"command"`
+	CreatedAt string   `json:"created_at"`
+	Settings  []string `json:"settings"`
+}
+
+func (e *configSnapshotEntry) Summary() string {
+	switch {
+	case len(e.Settings) == 0:
+		return "no settings differ from the current config"
+	case len(e.Settings) > 3:
+		return fmt.Sprintf("%d settings differ from the current config: %s and %d more", len(e.Settings), strings.Join(e.Settings[:3], ", "), len(e.Settings)-3)
+	default:
+		return fmt.Sprintf("%d settings differ from the current config: %s", len(e.Settings), strings.Join(e.Settings, ", "))
+	}
+}
+
+func getConfigSnapshotsDir() string {
+	return resolveConfigFilePath() + configSnapshotsDirSuffix
+}
+
+// saveConfigSnapshot stores the config that a command replaced, along
+// with the server it belongs to, so the snapshots of different
+// servers are not mixed. Nothing is stored in dry run mode, as the
+// config doesn't change
+func saveConfigSnapshot(config *model.Config, command string) error {
+	if viper.GetBool("dry-run") {
+		return nil
+	}
+
+	dir := getConfigSnapshotsDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Wrap(err, "failed to create the config snapshots directory")
+	}
+
+	// the ID is advanced if other snapshot was saved in the same
+	// millisecond, so the snapshots keep their order
+	now := time.Now()
+	path := filepath.Join(dir, now.UTC().Format(configSnapshotIDFormat)+".json")
+	for _, err := os.Stat(path); err == nil; _, err = os.Stat(path) {
+		now = now.Add(time.Millisecond)
+		path = filepath.Join(dir, now.UTC().Format(configSnapshotIDFormat)+".json")
+	}
+
+	snapshot := &ConfigSnapshot{
+		Id:       now.UTC().Format(configSnapshotIDFormat),
+		Server:   currentServer,
+		Command:  command,
+		CreateAt: now.UnixNano() / int64(time.Millisecond),
+		Config:   config,
+	}
+
+	b, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the config snapshot")
+	}
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		return errors.Wrap(err, "failed to save the config snapshot")
+	}
+	return nil
+}
+
+// snapshotReplacedConfig saves the snapshot of the config once the
+// server accepts the update, so the failed updates don't add entries
+// to the history. The config has already changed by then, so a
+// snapshot that can't be saved is reported without failing the command
+func snapshotReplacedConfig(config *model.Config, command string) {
+	if err := saveConfigSnapshot(config, command); err != nil {
+		printer.PrintWarning("The config was updated, but its previous version couldn't be saved as a snapshot: " + err.Error())
+	}
+}
+
+func readConfigSnapshot(path string) (*ConfigSnapshot, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot ConfigSnapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse config snapshot %s", filepath.Base(path))
+	}
+	return &snapshot, nil
+}
+
+func getConfigSnapshot(id string) (*ConfigSnapshot, error) {
+	if checkDots(id) || checkSlash(id) {
+		return nil, fmt.Errorf("invalid snapshot id %q", id)
+	}
+
+	snapshot, err := readConfigSnapshot(filepath.Join(getConfigSnapshotsDir(), id+".json"))
+	if os.IsNotExist(errors.Cause(err)) {
+		return nil, ErrEntityNotFound{Type: "config snapshot", ID: id}
+	}
+	return snapshot, err
+}
+
+// listConfigSnapshots returns the snapshots of the server, from the
+// newest to the oldest
+func listConfigSnapshots(server string) ([]*ConfigSnapshot, error) {
+	files, err := ioutil.ReadDir(getConfigSnapshotsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the config snapshots directory")
+	}
+
+	snapshots := []*ConfigSnapshot{}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		snapshot, err := readConfigSnapshot(filepath.Join(getConfigSnapshotsDir(), file.Name()))
+		if err != nil {
+			printer.PrintWarning(err.Error())
+			continue
+		}
+		if snapshot.Server == server {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Id > snapshots[j].Id
+	})
+	return snapshots, nil
+}
+
+func configHistoryCmdF(c client.Client, _ *cobra.Command, _ []string) error {
+	config, response := c.GetConfig()
+	if response.Error != nil {
+		return response.Error
+	}
+
+	snapshots, err := listConfigSnapshots(currentServer)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) == 0 {
+		printer.Print("There are no config snapshots for this server")
+		return nil
+	}
+
+	for _, snapshot := range snapshots {
+		entry := &configSnapshotEntry{
+			Id:        snapshot.Id,
+			Command:   snapshot.Command,
+			CreatedAt: time.Unix(0, snapshot.CreateAt*int64(time.Millisecond)).Format(time.RFC3339),
+			Settings:  []string{},
+		}
+		for _, diff := range diffConfigs(config, snapshot.Config) {
+			entry.Settings = append(entry.Settings, diff.Setting)
+		}
+		printer.PrintT("{{.Id}}: {{.Command}} at {{.CreatedAt}}, {{.Summary}}", entry)
+	}
+
+	return nil
+}
+
+func configRollbackCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	snapshot, err := getConfigSnapshot(args[0])
+	if err != nil {
+		return err
+	}
+
+	if snapshot.Server != currentServer {
+		return fmt.Errorf("snapshot %s belongs to server %q and not to the current one %q", snapshot.Id, snapshot.Server, currentServer)
+	}
+
+	config, response := c.GetConfig()
+	if response.Error != nil {
+		return response.Error
+	}
+
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+	if !confirmFlag {
+		var confirm string
+		fmt.Printf("Are you sure you want to restore the config of snapshot %s? (YES/NO): ", snapshot.Id)
+		_, _ = fmt.Scanln(&confirm)
+		if confirm != "YES" {
+			return errors.New("aborted: You did not answer YES exactly, in all capitals")
+		}
+	}
+
+	newConfig, response := c.UpdateConfig(snapshot.Config)
+	if response.Error != nil {
+		return response.Error
+	}
+	snapshotReplacedConfig(config, "config rollback")
+
+	printer.PrintT("Config restored from snapshot "+snapshot.Id, newConfig)
+	return nil
+}
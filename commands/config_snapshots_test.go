@@ -0,0 +1,200 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"os"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestConfigSnapshots() {
+	newConfig := func(siteName string) *model.Config {
+		config := &model.Config{}
+		config.SetDefaults()
+		config.ServiceSettings.SiteURL = model.NewString("https://mattermost.example.com")
+		config.TeamSettings.SiteName = model.NewString(siteName)
+		return config
+	}
+
+	currentServer = "https://mattermost.example.com"
+	defer func() { currentServer = "" }()
+
+	s.Run("Save a snapshot of the config replaced by a command", func() {
+		printer.Clean()
+		defer os.RemoveAll(getConfigSnapshotsDir())
+
+		config := newConfig("Before")
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(config, &model.Response{Error: nil}).
+			Times(1)
+		s.client.
+			EXPECT().
+			PatchConfig(config).
+			Return(config, &model.Response{Error: nil}).
+			Times(1)
+
+		err := configSetCmdF(s.client, &cobra.Command{}, []string{"TeamSettings.SiteName", "After"})
+		s.Require().Nil(err)
+
+		snapshots, err := listConfigSnapshots("https://mattermost.example.com")
+		s.Require().Nil(err)
+		s.Require().Len(snapshots, 1)
+		s.Require().Equal("config set", snapshots[0].Command)
+		s.Require().Equal("Before", *snapshots[0].Config.TeamSettings.SiteName)
+	})
+
+	s.Run("Don't save a snapshot if the update fails", func() {
+		printer.Clean()
+		defer os.RemoveAll(getConfigSnapshotsDir())
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(newConfig("Before"), &model.Response{Error: nil}).
+			Times(2)
+		s.client.
+			EXPECT().
+			PatchConfig(gomock.Any()).
+			Return(nil, &model.Response{Error: &model.AppError{Message: "patch error"}}).
+			Times(1)
+		s.client.
+			EXPECT().
+			UpdateConfig(gomock.Any()).
+			Return(nil, &model.Response{Error: &model.AppError{Message: "update error"}}).
+			Times(1)
+
+		err := configSetCmdF(s.client, &cobra.Command{}, []string{"TeamSettings.SiteName", "After"})
+		s.Require().EqualError(err, ": patch error, ")
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("confirm", true, "")
+		err = configResetCmdF(s.client, cmd, []string{"TeamSettings.SiteName"})
+		s.Require().EqualError(err, ": update error, ")
+
+		snapshots, err := listConfigSnapshots("https://mattermost.example.com")
+		s.Require().Nil(err)
+		s.Require().Empty(snapshots)
+	})
+
+	s.Run("List the snapshots of the server from the newest", func() {
+		printer.Clean()
+		defer os.RemoveAll(getConfigSnapshotsDir())
+
+		s.Require().Nil(saveConfigSnapshot(newConfig("First"), "config set"))
+		s.Require().Nil(saveConfigSnapshot(newConfig("Second"), "config patch"))
+		currentServer = "https://other.example.com"
+		s.Require().Nil(saveConfigSnapshot(newConfig("Other"), "config set"))
+		currentServer = "https://mattermost.example.com"
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(newConfig("Second"), &model.Response{Error: nil}).
+			Times(1)
+
+		err := configHistoryCmdF(s.client, &cobra.Command{}, []string{})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 2)
+
+		newest := printer.GetLines()[0].(*configSnapshotEntry)
+		s.Require().Equal("config patch", newest.Command)
+		s.Require().Empty(newest.Settings)
+		s.Require().Equal("no settings differ from the current config", newest.Summary())
+
+		oldest := printer.GetLines()[1].(*configSnapshotEntry)
+		s.Require().Equal("config set", oldest.Command)
+		s.Require().Equal([]string{"TeamSettings.SiteName"}, oldest.Settings)
+		s.Require().Equal("1 settings differ from the current config: TeamSettings.SiteName", oldest.Summary())
+	})
+
+	s.Run("Restore a snapshot", func() {
+		printer.Clean()
+		defer os.RemoveAll(getConfigSnapshotsDir())
+
+		s.Require().Nil(saveConfigSnapshot(newConfig("Before"), "config set"))
+		snapshots, err := listConfigSnapshots("https://mattermost.example.com")
+		s.Require().Nil(err)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("confirm", true, "")
+
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(newConfig("After"), &model.Response{Error: nil}).
+			Times(1)
+		s.client.
+			EXPECT().
+			UpdateConfig(snapshots[0].Config).
+			Return(snapshots[0].Config, &model.Response{Error: nil}).
+			Times(1)
+
+		err = configRollbackCmdF(s.client, cmd, []string{snapshots[0].Id})
+		s.Require().Nil(err)
+		s.Require().Len(printer.GetLines(), 1)
+
+		// the replaced config is saved so the rollback can be undone
+		snapshots, err = listConfigSnapshots("https://mattermost.example.com")
+		s.Require().Nil(err)
+		s.Require().Len(snapshots, 2)
+		s.Require().Equal("config rollback", snapshots[0].Command)
+		s.Require().Equal("After", *snapshots[0].Config.TeamSettings.SiteName)
+	})
+
+	s.Run("Restore a snapshot with a different site URL", func() {
+		printer.Clean()
+		defer os.RemoveAll(getConfigSnapshotsDir())
+
+		s.Require().Nil(saveConfigSnapshot(newConfig("Before"), "config set"))
+		snapshots, err := listConfigSnapshots("https://mattermost.example.com")
+		s.Require().Nil(err)
+
+		changedConfig := newConfig("Before")
+		changedConfig.ServiceSettings.SiteURL = model.NewString("https://changed.example.com")
+		s.client.
+			EXPECT().
+			GetConfig().
+			Return(changedConfig, &model.Response{Error: nil}).
+			Times(1)
+		s.client.
+			EXPECT().
+			UpdateConfig(snapshots[0].Config).
+			Return(snapshots[0].Config, &model.Response{Error: nil}).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("confirm", true, "")
+		err = configRollbackCmdF(s.client, cmd, []string{snapshots[0].Id})
+		s.Require().Nil(err)
+	})
+
+	s.Run("Fail to restore a snapshot of other server", func() {
+		printer.Clean()
+		defer os.RemoveAll(getConfigSnapshotsDir())
+
+		s.Require().Nil(saveConfigSnapshot(newConfig("Before"), "config set"))
+		snapshots, err := listConfigSnapshots("https://mattermost.example.com")
+		s.Require().Nil(err)
+
+		currentServer = "https://other.example.com"
+		defer func() { currentServer = "https://mattermost.example.com" }()
+
+		err = configRollbackCmdF(s.client, &cobra.Command{}, []string{snapshots[0].Id})
+		s.Require().EqualError(err, "snapshot "+snapshots[0].Id+` belongs to server "https://mattermost.example.com" and not to the current one "https://other.example.com"`)
+	})
+
+	s.Run("Fail to restore a snapshot that doesn't exist", func() {
+		printer.Clean()
+
+		err := configRollbackCmdF(s.client, &cobra.Command{}, []string{"20200101-000000.000"})
+		s.Require().EqualError(err, "config snapshot 20200101-000000.000 not found")
+	})
+}
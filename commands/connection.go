@@ -0,0 +1,125 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// connectionDefaultKeys are the options of the connection to the
+// server that are saved with the credentials when they are used to
+// login
+var connectionDefaultKeys = []string{"ca-cert", "client-cert", "client-key", "proxy"}
+
+// newTLSConfig returns the TLS configuration for the connections to
+// the server, with the CA certificates and the client certificate set
+// through the flags
+func newTLSConfig(allowInsecureSHA1, allowInsecureTLS bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if allowInsecureTLS {
+		tlsConfig.MinVersion = tls.VersionTLS10
+	}
+
+	if !allowInsecureSHA1 {
+		tlsConfig.VerifyPeerCertificate = VerifyCertificates
+	}
+
+	if caCert := viper.GetString("ca-cert"); caCert != "" {
+		pem, err := ioutil.ReadFile(caCert)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read the CA certificates file")
+		}
+
+		// the CA certificates are added to the ones of the system, so
+		// the servers with public certificates are still trusted
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("there are no valid certificates in the CA certificates file %s", caCert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	clientCert := viper.GetString("client-cert")
+	clientKey := viper.GetString("client-key")
+	if (clientCert == "") != (clientKey == "") {
+		return nil, errors.New("the --client-cert and --client-key flags must be used together")
+	}
+	if clientCert != "" {
+		certificate, err := tls.LoadX509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot load the client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// parseProxyURL checks that a proxy URL is valid
+func parseProxyURL(proxy string) (*url.URL, error) {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid proxy URL %q", proxy)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.Errorf("invalid proxy URL %q, the scheme must be http, https or socks5", proxy)
+	}
+	if proxyURL.Host == "" {
+		return nil, errors.Errorf("invalid proxy URL %q, it has no host", proxy)
+	}
+	return proxyURL, nil
+}
+
+// getProxy returns the function that selects the proxy of each
+// request: the one set through the flags or, if none is, the one of
+// the HTTP_PROXY and HTTPS_PROXY environment variables
+func getProxy() (func(*http.Request) (*url.URL, error), error) {
+	proxy := viper.GetString("proxy")
+	if proxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := parseProxyURL(proxy)
+	if err != nil {
+		return nil, err
+	}
+	return http.ProxyURL(proxyURL), nil
+}
+
+// newWebSocketDialer returns the dialer of the websocket connections,
+// with the same TLS configuration and proxy as the REST client
+func newWebSocketDialer(allowInsecureSHA1, allowInsecureTLS bool) (*websocket.Dialer, error) {
+	tlsConfig, err := newTLSConfig(allowInsecureSHA1, allowInsecureTLS)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy, err := getProxy()
+	if err != nil {
+		return nil, err
+	}
+
+	return &websocket.Dialer{
+		Proxy:            proxy,
+		TLSClientConfig:  tlsConfig,
+		HandshakeTimeout: 45 * time.Second,
+	}, nil
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mmctl/fakeserver"
+	"github.com/mattermost/mmctl/printer"
+)
+
+// testCertificate creates a certificate signed by the parent one, or
+// a self signed CA if there is no parent
+func testCertificate(t *testing.T, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return certificate, key
+}
+
+func writePEM(t *testing.T, path, blockType string, data []byte) {
+	require.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0600))
+}
+
+func TestConnectionOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mmctl-connection")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the flags are bound instead of setting the values, so they can
+	// be reset once the test finishes
+	bindStringFlag := func(name, value string) {
+		cmd := &cobra.Command{}
+		cmd.Flags().String(name, value, "")
+		_ = viper.BindPFlag(name, cmd.Flags().Lookup(name))
+	}
+	for _, key := range connectionDefaultKeys {
+		defer bindStringFlag(key, "")
+	}
+
+	ca, caKey := testCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Internal CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+	serverCert, serverKey := testCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+	clientCert, clientKey := testCertificate(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "mmctl"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	caPath := filepath.Join(dir, "ca.pem")
+	writePEM(t, caPath, "CERTIFICATE", ca.Raw)
+	clientCertPath := filepath.Join(dir, "client.pem")
+	writePEM(t, clientCertPath, "CERTIFICATE", clientCert.Raw)
+	clientKeyBytes, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+	clientKeyPath := filepath.Join(dir, "client-key.pem")
+	writePEM(t, clientKeyPath, "EC PRIVATE KEY", clientKeyBytes)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "OK"}`))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	t.Run("should connect with the CA and the client certificate", func(t *testing.T) {
+		bindStringFlag("ca-cert", caPath)
+		bindStringFlag("client-cert", clientCertPath)
+		bindStringFlag("client-key", clientKeyPath)
+		defer bindStringFlag("ca-cert", "")
+		defer bindStringFlag("client-cert", "")
+		defer bindStringFlag("client-key", "")
+
+		c, err := NewAPIv4Client(server.URL, false, false)
+		require.NoError(t, err)
+		status, response := c.GetPing()
+		require.Nil(t, response.Error)
+		require.Equal(t, model.STATUS_OK, status)
+	})
+
+	t.Run("should fail without the client certificate", func(t *testing.T) {
+		bindStringFlag("ca-cert", caPath)
+		defer bindStringFlag("ca-cert", "")
+
+		c, err := NewAPIv4Client(server.URL, false, false)
+		require.NoError(t, err)
+		_, response := c.GetPing()
+		require.NotNil(t, response.Error)
+	})
+
+	t.Run("should fail with invalid options", func(t *testing.T) {
+		bindStringFlag("client-cert", clientCertPath)
+		_, err := NewAPIv4Client(server.URL, false, false)
+		require.EqualError(t, err, "the --client-cert and --client-key flags must be used together")
+		bindStringFlag("client-cert", "")
+
+		bindStringFlag("ca-cert", clientKeyPath)
+		_, err = NewAPIv4Client(server.URL, false, false)
+		require.EqualError(t, err, "there are no valid certificates in the CA certificates file "+clientKeyPath)
+		bindStringFlag("ca-cert", "")
+
+		bindStringFlag("proxy", "proxy.example.com:3128")
+		_, err = NewAPIv4Client(server.URL, false, false)
+		require.EqualError(t, err, `invalid proxy URL "proxy.example.com:3128", the scheme must be http, https or socks5`)
+		bindStringFlag("proxy", "")
+	})
+
+	t.Run("should send the requests through the proxy", func(t *testing.T) {
+		requested := ""
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested = r.URL.String()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status": "OK"}`))
+		}))
+		defer proxy.Close()
+
+		bindStringFlag("proxy", proxy.URL)
+		defer bindStringFlag("proxy", "")
+
+		c, err := NewAPIv4Client("http://mattermost.example.com", false, false)
+		require.NoError(t, err)
+		status, response := c.GetPing()
+		require.Nil(t, response.Error)
+		require.Equal(t, model.STATUS_OK, status)
+		require.Equal(t, "http://mattermost.example.com/api/v4/system/ping", requested)
+	})
+}
+
+func TestLoginConnectionDefaults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mmctl-connection")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	viper.Set("config-path", dir)
+	defer viper.Set("config-path", xdgConfigHomeVar)
+
+	s := fakeserver.NewServer()
+	require.NoError(t, s.Start())
+	defer s.Close()
+
+	// the fake server handles the requests sent to it as a proxy too
+	proxy := httptest.NewServer(s)
+	defer proxy.Close()
+
+	printer.SetFormat(printer.FormatPlain)
+	newLoginCmd := func(name string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("name", name, "")
+		cmd.Flags().String("username", fakeserver.AdminUsername, "")
+		cmd.Flags().String("password", fakeserver.AdminPassword, "")
+		cmd.Flags().String("access-token", "", "")
+		cmd.Flags().String("mfa-token", "", "")
+		cmd.Flags().Bool("no-activate", true, "")
+		cmd.Flags().Bool("sso", false, "")
+		cmd.Flags().Bool("save-password", false, "")
+		for _, key := range connectionDefaultKeys {
+			cmd.Flags().String(key, "", "")
+		}
+		return cmd
+	}
+	defer func() {
+		require.NoError(t, CleanCredentials())
+	}()
+
+	t.Run("should save the connection options set in the command", func(t *testing.T) {
+		cmd := newLoginCmd("proxied")
+		require.NoError(t, cmd.Flags().Set("proxy", proxy.URL))
+		_ = viper.BindPFlag("proxy", cmd.Flags().Lookup("proxy"))
+		defer func() {
+			resetCmd := &cobra.Command{}
+			resetCmd.Flags().String("proxy", "", "")
+			_ = viper.BindPFlag("proxy", resetCmd.Flags().Lookup("proxy"))
+		}()
+
+		printer.Clean()
+		require.NoError(t, loginCmdF(cmd, []string{s.URL}))
+		require.Empty(t, printer.GetErrorLines())
+
+		credentials, err := GetCredentials("proxied")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"proxy": proxy.URL}, credentials.Defaults)
+	})
+
+	t.Run("should not save the defaults of other credentials", func(t *testing.T) {
+		restore := applyContextDefaults(&Credentials{Defaults: map[string]string{"proxy": proxy.URL}})
+		defer restore()
+		require.Equal(t, proxy.URL, viper.GetString("proxy"))
+
+		printer.Clean()
+		require.NoError(t, loginCmdF(newLoginCmd("direct"), []string{s.URL}))
+		require.Empty(t, printer.GetErrorLines())
+
+		credentials, err := GetCredentials("direct")
+		require.NoError(t, err)
+		require.Nil(t, credentials.Defaults)
+	})
+}
@@ -0,0 +1,600 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+
+	"github.com/mattermost/mmctl/client"
+	"github.com/mattermost/mmctl/printer"
+)
+
+const dryRunRedacted = "********"
+
+// dryRunClient wraps a client serving the read requests normally and
+// intercepting the ones that would modify the server. Intercepted
+// requests are printed and a synthetic successful response is
+// returned, so the command logic can run unchanged
+type dryRunClient struct {
+	client.Client
+}
+
+func newDryRunClient(c client.Client) client.Client {
+	return &dryRunClient{Client: c}
+}
+
+// record prints the method and the arguments that would have been
+// sent to the server
+func (c *dryRunClient) record(method string, args ...interface{}) {
+	encodedArgs := make([]string, 0, len(args))
+	for _, arg := range args {
+		b, err := json.Marshal(arg)
+		if err != nil {
+			b, _ = json.Marshal(fmt.Sprintf("%v", arg))
+		}
+		encodedArgs = append(encodedArgs, string(b))
+	}
+	printer.PrintWarning(fmt.Sprintf("[dry-run] %s(%s)", method, strings.Join(encodedArgs, ", ")))
+}
+
+func dryRunResponse(statusCode int) *model.Response {
+	return &model.Response{StatusCode: statusCode}
+}
+
+func dryRunID(id string) string {
+	if id == "" {
+		return model.NewId()
+	}
+	return id
+}
+
+func (c *dryRunClient) CreateChannel(channel *model.Channel) (*model.Channel, *model.Response) {
+	c.record("CreateChannel", channel)
+	newChannel := *channel
+	newChannel.Id = dryRunID(newChannel.Id)
+	return &newChannel, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) RemoveUserFromChannel(channelId, userId string) (bool, *model.Response) {
+	c.record("RemoveUserFromChannel", channelId, userId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) AddChannelMember(channelId, userId string) (*model.ChannelMember, *model.Response) {
+	c.record("AddChannelMember", channelId, userId)
+	return &model.ChannelMember{ChannelId: channelId, UserId: userId}, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) DeleteChannel(channelId string) (bool, *model.Response) {
+	c.record("DeleteChannel", channelId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) PermanentDeleteChannel(channelId string) (bool, *model.Response) {
+	c.record("PermanentDeleteChannel", channelId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) MoveChannel(channelId, teamId string, force bool) (*model.Channel, *model.Response) {
+	c.record("MoveChannel", channelId, teamId, force)
+	channel, response := c.Client.GetChannel(channelId, "")
+	if response.Error != nil {
+		return nil, response
+	}
+	channel.TeamId = teamId
+	return channel, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) RestoreChannel(channelId string) (*model.Channel, *model.Response) {
+	c.record("RestoreChannel", channelId)
+	channel, response := c.Client.GetChannel(channelId, "")
+	if response.Error != nil {
+		return nil, response
+	}
+	channel.DeleteAt = 0
+	return channel, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) PatchChannel(channelId string, patch *model.ChannelPatch) (*model.Channel, *model.Response) {
+	c.record("PatchChannel", channelId, patch)
+	channel, response := c.Client.GetChannel(channelId, "")
+	if response.Error != nil {
+		return nil, response
+	}
+	channel.Patch(patch)
+	return channel, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UpdateChannelPrivacy(channelId string, privacy string) (*model.Channel, *model.Response) {
+	c.record("UpdateChannelPrivacy", channelId, privacy)
+	channel, response := c.Client.GetChannel(channelId, "")
+	if response.Error != nil {
+		return nil, response
+	}
+	channel.Type = privacy
+	return channel, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) CreateTeam(team *model.Team) (*model.Team, *model.Response) {
+	c.record("CreateTeam", team)
+	newTeam := *team
+	newTeam.Id = dryRunID(newTeam.Id)
+	return &newTeam, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) PatchTeam(teamId string, patch *model.TeamPatch) (*model.Team, *model.Response) {
+	c.record("PatchTeam", teamId, patch)
+	team, response := c.Client.GetTeam(teamId, "")
+	if response.Error != nil {
+		return nil, response
+	}
+	team.Patch(patch)
+	return team, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UpdateTeam(team *model.Team) (*model.Team, *model.Response) {
+	c.record("UpdateTeam", team)
+	newTeam := *team
+	return &newTeam, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) AddTeamMember(teamId, userId string) (*model.TeamMember, *model.Response) {
+	c.record("AddTeamMember", teamId, userId)
+	return &model.TeamMember{TeamId: teamId, UserId: userId}, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) RemoveTeamMember(teamId, userId string) (bool, *model.Response) {
+	c.record("RemoveTeamMember", teamId, userId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) SoftDeleteTeam(teamId string) (bool, *model.Response) {
+	c.record("SoftDeleteTeam", teamId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) PermanentDeleteTeam(teamId string) (bool, *model.Response) {
+	c.record("PermanentDeleteTeam", teamId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) RestoreTeam(teamId string) (*model.Team, *model.Response) {
+	c.record("RestoreTeam", teamId)
+	team, response := c.Client.GetTeam(teamId, "")
+	if response.Error != nil {
+		return nil, response
+	}
+	team.DeleteAt = 0
+	return team, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UpdateTeamPrivacy(teamId string, privacy string) (*model.Team, *model.Response) {
+	c.record("UpdateTeamPrivacy", teamId, privacy)
+	team, response := c.Client.GetTeam(teamId, "")
+	if response.Error != nil {
+		return nil, response
+	}
+	team.Type = privacy
+	return team, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) CreatePost(post *model.Post) (*model.Post, *model.Response) {
+	c.record("CreatePost", post)
+	newPost := post.Clone()
+	newPost.Id = dryRunID(newPost.Id)
+	return newPost, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) DoApiPost(url string, data string) (*http.Response, *model.AppError) {
+	var body interface{} = data
+	if json.Valid([]byte(data)) {
+		body = json.RawMessage(data)
+	}
+	c.record("DoApiPost", url, body)
+	return &http.Response{
+		StatusCode: http.StatusCreated,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+func (c *dryRunClient) UploadLicenseFile(data []byte) (bool, *model.Response) {
+	c.record("UploadLicenseFile", fmt.Sprintf("%d bytes", len(data)))
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) RemoveLicenseFile() (bool, *model.Response) {
+	c.record("RemoveLicenseFile")
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) PatchRole(roleId string, patch *model.RolePatch) (*model.Role, *model.Response) {
+	c.record("PatchRole", roleId, patch)
+	role := &model.Role{Id: roleId}
+	role.Patch(patch)
+	return role, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UploadPlugin(file io.Reader) (*model.Manifest, *model.Response) {
+	c.record("UploadPlugin", "<plugin bundle>")
+	return &model.Manifest{}, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) RemovePlugin(id string) (bool, *model.Response) {
+	c.record("RemovePlugin", id)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) EnablePlugin(id string) (bool, *model.Response) {
+	c.record("EnablePlugin", id)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) DisablePlugin(id string) (bool, *model.Response) {
+	c.record("DisablePlugin", id)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) InstallPluginFromUrl(url string, force bool) (*model.Manifest, *model.Response) {
+	c.record("InstallPluginFromUrl", url, force)
+	return &model.Manifest{}, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) InstallMarketplacePlugin(request *model.InstallMarketplacePluginRequest) (*model.Manifest, *model.Response) {
+	c.record("InstallMarketplacePlugin", request)
+	return &model.Manifest{Id: request.Id, Version: request.Version}, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) PermanentDeleteUser(userId string) (bool, *model.Response) {
+	c.record("PermanentDeleteUser", userId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) PermanentDeleteAllUsers() (bool, *model.Response) {
+	c.record("PermanentDeleteAllUsers")
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) CreateUser(user *model.User) (*model.User, *model.Response) {
+	recordedUser := *user
+	if recordedUser.Password != "" {
+		recordedUser.Password = dryRunRedacted
+	}
+	c.record("CreateUser", &recordedUser)
+
+	newUser := *user
+	newUser.Id = dryRunID(newUser.Id)
+	newUser.Password = ""
+	return &newUser, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) VerifyUserEmailWithoutToken(userId string) (*model.User, *model.Response) {
+	c.record("VerifyUserEmailWithoutToken", userId)
+	user, response := c.Client.GetUser(userId, "")
+	if response.Error != nil {
+		return nil, response
+	}
+	user.EmailVerified = true
+	return user, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UpdateUserRoles(userId, roles string) (bool, *model.Response) {
+	c.record("UpdateUserRoles", userId, roles)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) InviteUsersToTeam(teamId string, userEmails []string) (bool, *model.Response) {
+	c.record("InviteUsersToTeam", teamId, userEmails)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) SendPasswordResetEmail(email string) (bool, *model.Response) {
+	c.record("SendPasswordResetEmail", email)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UpdateUser(user *model.User) (*model.User, *model.Response) {
+	recordedUser := *user
+	if recordedUser.Password != "" {
+		recordedUser.Password = dryRunRedacted
+	}
+	c.record("UpdateUser", &recordedUser)
+
+	newUser := *user
+	newUser.Password = ""
+	return &newUser, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UpdateUserMfa(userId, code string, activate bool) (bool, *model.Response) {
+	c.record("UpdateUserMfa", userId, code, activate)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UpdateUserPassword(userId, currentPassword, newPassword string) (bool, *model.Response) {
+	c.record("UpdateUserPassword", userId, dryRunRedacted, dryRunRedacted)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UpdateUserHashedPassword(userId, newHashedPassword string) (bool, *model.Response) {
+	c.record("UpdateUserHashedPassword", userId, dryRunRedacted)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UpdateUserActive(userId string, activate bool) (bool, *model.Response) {
+	c.record("UpdateUserActive", userId, activate)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) CreateUserAccessToken(userId, description string) (*model.UserAccessToken, *model.Response) {
+	c.record("CreateUserAccessToken", userId, description)
+	return &model.UserAccessToken{
+		Id:          model.NewId(),
+		UserId:      userId,
+		Description: description,
+		IsActive:    true,
+	}, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) RevokeUserAccessToken(tokenId string) (bool, *model.Response) {
+	c.record("RevokeUserAccessToken", tokenId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) ConvertUserToBot(userId string) (*model.Bot, *model.Response) {
+	c.record("ConvertUserToBot", userId)
+	user, response := c.Client.GetUser(userId, "")
+	if response.Error != nil {
+		return nil, response
+	}
+	return model.BotFromUser(user), dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) ConvertBotToUser(userId string, userPatch *model.UserPatch, setSystemAdmin bool) (*model.User, *model.Response) {
+	recordedPatch := *userPatch
+	if recordedPatch.Password != nil {
+		recordedPatch.Password = model.NewString(dryRunRedacted)
+	}
+	c.record("ConvertBotToUser", userId, &recordedPatch, setSystemAdmin)
+
+	user, response := c.Client.GetUser(userId, "")
+	if response.Error != nil {
+		return nil, response
+	}
+	user.Patch(userPatch)
+	user.Password = ""
+	return user, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) PromoteGuestToUser(userId string) (bool, *model.Response) {
+	c.record("PromoteGuestToUser", userId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) DemoteUserToGuest(guestId string) (bool, *model.Response) {
+	c.record("DemoteUserToGuest", guestId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) CreateCommand(cmd *model.Command) (*model.Command, *model.Response) {
+	c.record("CreateCommand", cmd)
+	newCmd := *cmd
+	newCmd.Id = dryRunID(newCmd.Id)
+	return &newCmd, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) UpdateCommand(cmd *model.Command) (*model.Command, *model.Response) {
+	c.record("UpdateCommand", cmd)
+	newCmd := *cmd
+	return &newCmd, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) MoveCommand(teamId string, commandId string) (bool, *model.Response) {
+	c.record("MoveCommand", teamId, commandId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) DeleteCommand(commandId string) (bool, *model.Response) {
+	c.record("DeleteCommand", commandId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UpdateConfig(config *model.Config) (*model.Config, *model.Response) {
+	c.record("UpdateConfig", config)
+	return config, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) PatchConfig(config *model.Config) (*model.Config, *model.Response) {
+	c.record("PatchConfig", config)
+	return config, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) ReloadConfig() (bool, *model.Response) {
+	c.record("ReloadConfig")
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) MigrateConfig(from, to string) (bool, *model.Response) {
+	c.record("MigrateConfig", from, to)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) SyncLdap(includeRemovedMembers bool) (bool, *model.Response) {
+	c.record("SyncLdap", includeRemovedMembers)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) MigrateIdLdap(toAttribute string) (bool, *model.Response) {
+	c.record("MigrateIdLdap", toAttribute)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) MigrateAuthToLdap(fromAuthService string, matchField string, force bool) (bool, *model.Response) {
+	c.record("MigrateAuthToLdap", fromAuthService, matchField, force)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) MigrateAuthToSaml(fromAuthService string, usersMap map[string]string, auto bool) (bool, *model.Response) {
+	c.record("MigrateAuthToSaml", fromAuthService, usersMap, auto)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) ResetSamlAuthDataToEmail(includeDeleted bool, dryRun bool, userIDs []string) (int64, *model.Response) {
+	// the server supports a dry run for this request, so we rely on
+	// it to get an accurate count of the affected users
+	c.record("ResetSamlAuthDataToEmail", includeDeleted, dryRun, userIDs)
+	return c.Client.ResetSamlAuthDataToEmail(includeDeleted, true, userIDs)
+}
+
+func (c *dryRunClient) CreateBot(bot *model.Bot) (*model.Bot, *model.Response) {
+	c.record("CreateBot", bot)
+	newBot := *bot
+	newBot.UserId = dryRunID(newBot.UserId)
+	return &newBot, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) PatchBot(userId string, patch *model.BotPatch) (*model.Bot, *model.Response) {
+	c.record("PatchBot", userId, patch)
+	bot := &model.Bot{UserId: userId}
+	bot.Patch(patch)
+	return bot, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) DisableBot(botUserId string) (*model.Bot, *model.Response) {
+	c.record("DisableBot", botUserId)
+	return &model.Bot{UserId: botUserId, DeleteAt: model.GetMillis()}, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) EnableBot(botUserId string) (*model.Bot, *model.Response) {
+	c.record("EnableBot", botUserId)
+	return &model.Bot{UserId: botUserId}, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) AssignBot(botUserId, newOwnerId string) (*model.Bot, *model.Response) {
+	c.record("AssignBot", botUserId, newOwnerId)
+	return &model.Bot{UserId: botUserId, OwnerId: newOwnerId}, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) SetServerBusy(secs int) (bool, *model.Response) {
+	c.record("SetServerBusy", secs)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) ClearServerBusy() (bool, *model.Response) {
+	c.record("ClearServerBusy")
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) CreateUpload(us *model.UploadSession) (*model.UploadSession, *model.Response) {
+	c.record("CreateUpload", us)
+	newUs := *us
+	newUs.Id = dryRunID(newUs.Id)
+	return &newUs, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) UploadData(uploadId string, data io.Reader) (*model.FileInfo, *model.Response) {
+	c.record("UploadData", uploadId, "<data>")
+	return &model.FileInfo{Id: model.NewId()}, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) CreateJob(job *model.Job) (*model.Job, *model.Response) {
+	c.record("CreateJob", job)
+	newJob := *job
+	newJob.Id = dryRunID(newJob.Id)
+	newJob.Status = model.JOB_STATUS_PENDING
+	return &newJob, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) CancelJob(jobId string) (bool, *model.Response) {
+	c.record("CancelJob", jobId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) CreateIncomingWebhook(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.Response) {
+	c.record("CreateIncomingWebhook", hook)
+	newHook := *hook
+	newHook.Id = dryRunID(newHook.Id)
+	return &newHook, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) UpdateIncomingWebhook(hook *model.IncomingWebhook) (*model.IncomingWebhook, *model.Response) {
+	c.record("UpdateIncomingWebhook", hook)
+	newHook := *hook
+	return &newHook, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) DeleteIncomingWebhook(hookID string) (bool, *model.Response) {
+	c.record("DeleteIncomingWebhook", hookID)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) CreateOutgoingWebhook(hook *model.OutgoingWebhook) (*model.OutgoingWebhook, *model.Response) {
+	c.record("CreateOutgoingWebhook", hook)
+	newHook := *hook
+	newHook.Id = dryRunID(newHook.Id)
+	return &newHook, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) UpdateOutgoingWebhook(hook *model.OutgoingWebhook) (*model.OutgoingWebhook, *model.Response) {
+	c.record("UpdateOutgoingWebhook", hook)
+	newHook := *hook
+	return &newHook, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) RegenOutgoingHookToken(hookId string) (*model.OutgoingWebhook, *model.Response) {
+	c.record("RegenOutgoingHookToken", hookId)
+	hook, response := c.Client.GetOutgoingWebhook(hookId)
+	if response.Error != nil {
+		return nil, response
+	}
+	hook.Token = model.NewId()
+	return hook, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) DeleteOutgoingWebhook(hookId string) (bool, *model.Response) {
+	c.record("DeleteOutgoingWebhook", hookId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) DeleteExport(name string) (bool, *model.Response) {
+	c.record("DeleteExport", name)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) CreateScheme(scheme *model.Scheme) (*model.Scheme, *model.Response) {
+	c.record("CreateScheme", scheme)
+	newScheme := *scheme
+	newScheme.Id = dryRunID(newScheme.Id)
+	return &newScheme, dryRunResponse(http.StatusCreated)
+}
+
+func (c *dryRunClient) PatchScheme(id string, patch *model.SchemePatch) (*model.Scheme, *model.Response) {
+	c.record("PatchScheme", id, patch)
+	scheme, response := c.Client.GetScheme(id)
+	if response.Error != nil {
+		return nil, response
+	}
+	scheme.Patch(patch)
+	return scheme, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) DeleteScheme(id string) (bool, *model.Response) {
+	c.record("DeleteScheme", id)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UpdateTeamScheme(teamId, schemeId string) (bool, *model.Response) {
+	c.record("UpdateTeamScheme", teamId, schemeId)
+	return true, dryRunResponse(http.StatusOK)
+}
+
+func (c *dryRunClient) UpdateChannelScheme(channelId, schemeId string) (bool, *model.Response) {
+	c.record("UpdateChannelScheme", channelId, schemeId)
+	return true, dryRunResponse(http.StatusOK)
+}
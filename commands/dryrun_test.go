@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestDryRunClient() {
+	s.Run("should serve read requests through the wrapped client", func() {
+		printer.Clean()
+		mockTeam := &model.Team{Id: teamID, Name: "team-name"}
+
+		s.client.
+			EXPECT().
+			GetTeam(teamID, "").
+			Return(mockTeam, &model.Response{}).
+			Times(1)
+
+		team, response := newDryRunClient(s.client).GetTeam(teamID, "")
+		s.Require().Nil(response.Error)
+		s.Require().Equal(mockTeam, team)
+		s.Require().Empty(printer.GetWarningLines())
+	})
+
+	s.Run("should intercept create requests and return the entity", func() {
+		printer.Clean()
+		team := &model.Team{Name: "team-name", DisplayName: "Team Name", Type: model.TEAM_OPEN}
+
+		newTeam, response := newDryRunClient(s.client).CreateTeam(team)
+		s.Require().Nil(response.Error)
+		s.Require().Equal(http.StatusCreated, response.StatusCode)
+		s.Require().NotEmpty(newTeam.Id)
+		s.Require().Equal(team.Name, newTeam.Name)
+		s.Require().Empty(team.Id)
+		s.Require().Len(printer.GetWarningLines(), 1)
+		s.Require().Contains(printer.GetWarningLines()[0], `[dry-run] CreateTeam({"id":"",`)
+	})
+
+	s.Run("should apply patches over the current state", func() {
+		printer.Clean()
+		mockChannel := &model.Channel{Id: channelID, Name: channelName, DisplayName: "Old Name"}
+		patch := &model.ChannelPatch{DisplayName: model.NewString("New Name")}
+
+		s.client.
+			EXPECT().
+			GetChannel(channelID, "").
+			Return(mockChannel, &model.Response{}).
+			Times(1)
+
+		channel, response := newDryRunClient(s.client).PatchChannel(channelID, patch)
+		s.Require().Nil(response.Error)
+		s.Require().Equal("New Name", channel.DisplayName)
+		s.Require().Len(printer.GetWarningLines(), 1)
+		s.Require().Equal(`[dry-run] PatchChannel("channelID", {"display_name":"New Name","name":null,"header":null,"purpose":null,"group_constrained":null})`, printer.GetWarningLines()[0])
+	})
+
+	s.Run("should redact passwords", func() {
+		printer.Clean()
+		user := &model.User{Username: "john.doe", Password: "mysupersecret"}
+
+		newUser, response := newDryRunClient(s.client).CreateUser(user)
+		s.Require().Nil(response.Error)
+		s.Require().Empty(newUser.Password)
+		s.Require().Equal("mysupersecret", user.Password)
+		s.Require().Len(printer.GetWarningLines(), 1)
+		s.Require().NotContains(printer.GetWarningLines()[0], "mysupersecret")
+	})
+
+	s.Run("should let the command logic run unchanged", func() {
+		printer.Clean()
+		mockTeam := &model.Team{Id: teamID, Name: "team-name"}
+
+		s.client.
+			EXPECT().
+			GetTeam("team-name", "").
+			Return(mockTeam, &model.Response{}).
+			Times(1)
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("confirm", true, "")
+
+		err := archiveTeamsCmdF(newDryRunClient(s.client), cmd, []string{"team-name"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(mockTeam, printer.GetLines()[0])
+		s.Require().Len(printer.GetWarningLines(), 1)
+		s.Require().Equal(`[dry-run] SoftDeleteTeam("teamID")`, printer.GetWarningLines()[0])
+	})
+}
@@ -4,10 +4,39 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/mattermost/mattermost-server/v5/model"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+// Exit codes used by mmctl depending on the error returned by the
+// command
+const (
+	ExitCodeSuccess        = 0
+	ExitCodeError          = 1
+	ExitCodeUsage          = 2
+	ExitCodeNotFound       = 3
+	ExitCodeBadRequest     = 4
+	ExitCodeUnauthorized   = 5
+	ExitCodeForbidden      = 6
+	ExitCodeServerError    = 7
+	ExitCodePartialFailure = 8
+)
+
+// Error codes used in the error objects printed with the json formats
+const (
+	ErrorCodeUsage          = "usage"
+	ErrorCodeNotFound       = "not_found"
+	ErrorCodeBadRequest     = "bad_request"
+	ErrorCodeUnauthorized   = "unauthorized"
+	ErrorCodeForbidden      = "forbidden"
+	ErrorCodeServerError    = "server_error"
+	ErrorCodePartialFailure = "partial_failure"
 )
 
 // ErrEntityNotFound is thrown when an entity (user, team, etc.)
@@ -37,6 +66,56 @@ func (e *BadRequestError) Error() string {
 	return e.Msg
 }
 
+// UnauthorizedError is returned when the credentials are not valid or
+// the session has expired
+type UnauthorizedError struct {
+	Msg string
+}
+
+func (e *UnauthorizedError) Error() string {
+	return e.Msg
+}
+
+// ForbiddenError is returned when the user doesn't have the
+// permissions to perform an action
+type ForbiddenError struct {
+	Msg string
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Msg
+}
+
+// ServerError is returned when the server fails or can't be reached
+type ServerError struct {
+	Msg        string
+	StatusCode int
+}
+
+func (e *ServerError) Error() string {
+	return e.Msg
+}
+
+// UsageError is returned when the command is called with invalid
+// flags or arguments
+type UsageError struct {
+	Msg string
+}
+
+func (e *UsageError) Error() string {
+	return e.Msg
+}
+
+// PartialFailureError is returned when a command that processes
+// several items finishes but some of them failed
+type PartialFailureError struct {
+	Failed int
+}
+
+func (e *PartialFailureError) Error() string {
+	return fmt.Sprintf("%d errors occurred", e.Failed)
+}
+
 // ExtractErrorFromResponse extracts the error from the response,
 // encapsulating it if matches the common cases, such as when it's
 // not found, and when we've made a bad request
@@ -46,7 +125,109 @@ func ExtractErrorFromResponse(r *model.Response) error {
 		return &NotFoundError{Msg: r.Error.Error()}
 	case http.StatusBadRequest:
 		return &BadRequestError{Msg: r.Error.Error()}
-	default:
-		return r.Error
+	case http.StatusUnauthorized:
+		return &UnauthorizedError{Msg: r.Error.Error()}
+	case http.StatusForbidden:
+		return &ForbiddenError{Msg: r.Error.Error()}
+	}
+	if r.Error.StatusCode >= http.StatusInternalServerError {
+		return &ServerError{Msg: r.Error.Error(), StatusCode: r.Error.StatusCode}
+	}
+	return r.Error
+}
+
+// classifyError returns the error object that describes an error
+// returned by a command and the exit code that corresponds to it
+func classifyError(err error) (*printer.ErrorObject, int) {
+	e := &printer.ErrorObject{Code: printer.ErrorCodeGeneric, Message: err.Error()}
+
+	var entityErr ErrEntityNotFound
+	var nfErr *NotFoundError
+	var badRequestErr *BadRequestError
+	var unauthorizedErr *UnauthorizedError
+	var forbiddenErr *ForbiddenError
+	var serverErr *ServerError
+	var usageErr *UsageError
+	var partialErr *PartialFailureError
+	var appErr *model.AppError
+
+	switch {
+	case errors.As(err, &entityErr):
+		e.Code, e.Entity, e.ID = ErrorCodeNotFound, entityErr.Type, entityErr.ID
+		return e, ExitCodeNotFound
+	case errors.As(err, &nfErr):
+		e.Code, e.Status = ErrorCodeNotFound, http.StatusNotFound
+		return e, ExitCodeNotFound
+	case errors.As(err, &badRequestErr):
+		e.Code, e.Status = ErrorCodeBadRequest, http.StatusBadRequest
+		return e, ExitCodeBadRequest
+	case errors.As(err, &unauthorizedErr):
+		e.Code, e.Status = ErrorCodeUnauthorized, http.StatusUnauthorized
+		return e, ExitCodeUnauthorized
+	case errors.As(err, &forbiddenErr):
+		e.Code, e.Status = ErrorCodeForbidden, http.StatusForbidden
+		return e, ExitCodeForbidden
+	case errors.As(err, &serverErr):
+		e.Code, e.Status = ErrorCodeServerError, serverErr.StatusCode
+		return e, ExitCodeServerError
+	case errors.As(err, &usageErr):
+		e.Code = ErrorCodeUsage
+		return e, ExitCodeUsage
+	case errors.As(err, &partialErr):
+		e.Code = ErrorCodePartialFailure
+		return e, ExitCodePartialFailure
+	case errors.As(err, &appErr):
+		return classifyAppError(e, appErr)
+	case strings.HasPrefix(err.Error(), "unknown command"):
+		// cobra doesn't use a specific type for this error
+		e.Code = ErrorCodeUsage
+		return e, ExitCodeUsage
+	}
+
+	return e, ExitCodeError
+}
+
+// classifyAppError fills the error object from the status code of an
+// error returned by the server
+func classifyAppError(e *printer.ErrorObject, appErr *model.AppError) (*printer.ErrorObject, int) {
+	e.Status = appErr.StatusCode
+	switch {
+	case appErr.StatusCode == http.StatusNotFound:
+		e.Code = ErrorCodeNotFound
+		return e, ExitCodeNotFound
+	case appErr.StatusCode == http.StatusBadRequest:
+		e.Code = ErrorCodeBadRequest
+		return e, ExitCodeBadRequest
+	case appErr.StatusCode == http.StatusUnauthorized:
+		e.Code = ErrorCodeUnauthorized
+		return e, ExitCodeUnauthorized
+	case appErr.StatusCode == http.StatusForbidden:
+		e.Code = ErrorCodeForbidden
+		return e, ExitCodeForbidden
+	case appErr.StatusCode == 0 || appErr.StatusCode >= http.StatusInternalServerError:
+		// the client uses a zero status code when the server can't
+		// be reached
+		e.Code = ErrorCodeServerError
+		return e, ExitCodeServerError
 	}
+
+	return e, ExitCodeError
+}
+
+// ExitCode returns the exit code for an error returned by a command
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+	_, code := classifyError(err)
+	return code
+}
+
+// printItemError prints the error of an item that failed while the
+// command continues with the rest, with the code of the error that
+// caused it in the json formats
+func printItemError(msg string, err error) {
+	e, _ := classifyError(err)
+	e.Message = msg
+	printer.PrintErrorObject(msg, e)
 }
@@ -0,0 +1,82 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+func TestExitCode(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Err      error
+		Expected int
+	}{
+		{"no error", nil, ExitCodeSuccess},
+		{"generic error", errors.New("some error"), ExitCodeError},
+		{"entity not found", ErrEntityNotFound{Type: "user", ID: "userID"}, ExitCodeNotFound},
+		{"wrapped not found", pkgerrors.Wrap(&NotFoundError{Msg: "not found"}, "failed"), ExitCodeNotFound},
+		{"bad request", &BadRequestError{Msg: "bad request"}, ExitCodeBadRequest},
+		{"unauthorized", &UnauthorizedError{Msg: "unauthorized"}, ExitCodeUnauthorized},
+		{"forbidden", &ForbiddenError{Msg: "forbidden"}, ExitCodeForbidden},
+		{"server error", &ServerError{Msg: "server error", StatusCode: http.StatusBadGateway}, ExitCodeServerError},
+		{"usage", &UsageError{Msg: "unknown flag: --foo"}, ExitCodeUsage},
+		{"unknown command", errors.New(`unknown command "foo" for "mmctl"`), ExitCodeUsage},
+		{"partial failure", &PartialFailureError{Failed: 2}, ExitCodePartialFailure},
+		{"app error forbidden", &model.AppError{StatusCode: http.StatusForbidden}, ExitCodeForbidden},
+		{"app error unreachable", &model.AppError{StatusCode: 0}, ExitCodeServerError},
+		{"app error internal", &model.AppError{StatusCode: http.StatusInternalServerError}, ExitCodeServerError},
+		{"app error conflict", &model.AppError{StatusCode: http.StatusConflict}, ExitCodeError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.Expected, ExitCode(tc.Err))
+		})
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	t.Run("should include the entity of not found errors", func(t *testing.T) {
+		e, code := classifyError(pkgerrors.Wrap(ErrEntityNotFound{Type: "team", ID: "teamID"}, "failed"))
+		require.Equal(t, ExitCodeNotFound, code)
+		require.Equal(t, &printer.ErrorObject{
+			Code:    ErrorCodeNotFound,
+			Entity:  "team",
+			ID:      "teamID",
+			Message: "failed: team teamID not found",
+		}, e)
+	})
+
+	t.Run("should include the status of server errors", func(t *testing.T) {
+		e, code := classifyError(&model.AppError{Message: "forbidden", StatusCode: http.StatusForbidden})
+		require.Equal(t, ExitCodeForbidden, code)
+		require.Equal(t, ErrorCodeForbidden, e.Code)
+		require.Equal(t, http.StatusForbidden, e.Status)
+	})
+}
+
+func TestExtractErrorFromResponse(t *testing.T) {
+	newResponse := func(status int) *model.Response {
+		return &model.Response{Error: &model.AppError{Message: "error", StatusCode: status}}
+	}
+
+	var unauthorizedErr *UnauthorizedError
+	require.True(t, errors.As(ExtractErrorFromResponse(newResponse(http.StatusUnauthorized)), &unauthorizedErr))
+
+	var forbiddenErr *ForbiddenError
+	require.True(t, errors.As(ExtractErrorFromResponse(newResponse(http.StatusForbidden)), &forbiddenErr))
+
+	var serverErr *ServerError
+	require.True(t, errors.As(ExtractErrorFromResponse(newResponse(http.StatusServiceUnavailable)), &serverErr))
+	require.Equal(t, http.StatusServiceUnavailable, serverErr.StatusCode)
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mmctl/fakeserver"
+	"github.com/mattermost/mmctl/printer"
+)
+
+func TestCommandsWithFakeServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mmctl-fakeserver")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := fakeserver.NewServer()
+	require.NoError(t, s.Start())
+	require.NoError(t, s.StartUnix(filepath.Join(dir, "mattermost_local.socket")))
+	defer s.Close()
+
+	// the flags are bound instead of setting the values, as values set
+	// in viper can't be overridden by the flags bound in other tests
+	localCmd := &cobra.Command{}
+	localCmd.PersistentFlags().Bool("local", true, "")
+	localCmd.PersistentFlags().String("local-socket-path", "", "")
+	// the flag is set, as the defaults of viper take precedence over
+	// the default values of the flags
+	require.NoError(t, localCmd.PersistentFlags().Set("local-socket-path", s.SocketPath))
+	_ = viper.BindPFlag("local", localCmd.PersistentFlags().Lookup("local"))
+	_ = viper.BindPFlag("local-socket-path", localCmd.PersistentFlags().Lookup("local-socket-path"))
+	defer func() {
+		resetCmd := &cobra.Command{}
+		resetCmd.PersistentFlags().Bool("local", false, "")
+		resetCmd.PersistentFlags().String("local-socket-path", model.LOCAL_MODE_SOCKET_PATH, "")
+		_ = viper.BindPFlag("local", resetCmd.PersistentFlags().Lookup("local"))
+		_ = viper.BindPFlag("local-socket-path", resetCmd.PersistentFlags().Lookup("local-socket-path"))
+	}()
+
+	printer.Clean()
+	printer.SetFormat(printer.FormatJSON)
+	defer printer.SetFormat(printer.FormatPlain)
+
+	teamCmd := &cobra.Command{}
+	teamCmd.Flags().String("name", "team", "")
+	teamCmd.Flags().String("display_name", "Team", "")
+	require.NoError(t, withClient(createTeamCmdF)(teamCmd, []string{}))
+
+	channelCmd := &cobra.Command{}
+	channelCmd.Flags().String("name", "channel", "")
+	channelCmd.Flags().String("display_name", "Channel", "")
+	channelCmd.Flags().String("team", "team", "")
+	require.NoError(t, withClient(createChannelCmdF)(channelCmd, []string{}))
+
+	userCmd := &cobra.Command{}
+	userCmd.Flags().String("username", "john.doe", "")
+	userCmd.Flags().String("email", "john@example.com", "")
+	userCmd.Flags().String("password", "Password1", "")
+	require.NoError(t, withClient(userCreateCmdF)(userCmd, []string{}))
+
+	require.NoError(t, withClient(teamUsersAddCmdF)(&cobra.Command{}, []string{"team", "john.doe"}))
+	require.NoError(t, withClient(channelUsersAddCmdF)(&cobra.Command{}, []string{"team:channel", "john.doe"}))
+	require.Empty(t, printer.GetErrorLines())
+
+	// the changes are checked through the network listener
+	c := model.NewAPIv4Client(s.URL)
+	c.SetToken(s.AdminToken)
+
+	team, resp := c.GetTeamByName("team", "")
+	require.Nil(t, resp.Error)
+	user, resp := c.GetUserByUsername("john.doe", "")
+	require.Nil(t, resp.Error)
+	channel, resp := c.GetChannelByName("channel", team.Id, "")
+	require.Nil(t, resp.Error)
+
+	_, resp = c.GetChannelMember(channel.Id, user.Id, "")
+	require.Nil(t, resp.Error)
+}
+
+func TestCommandsInContextsWithFakeServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mmctl-contexts")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	servers := map[string]*fakeserver.Server{}
+	credentialsList := CredentialsList{}
+	for _, name := range []string{"prod-eu", "prod-us"} {
+		s := fakeserver.NewServer()
+		require.NoError(t, s.Start())
+		defer s.Close()
+
+		servers[name] = s
+		credentialsList[name] = &Credentials{
+			Name:        name,
+			Username:    fakeserver.AdminUsername,
+			AuthToken:   s.AdminToken,
+			AuthMethod:  MethodToken,
+			InstanceURL: s.URL,
+		}
+	}
+
+	viper.Set("config-path", dir)
+	defer viper.Set("config-path", xdgConfigHomeVar)
+	require.NoError(t, SaveCredentialsList(&credentialsList))
+
+	contextsCmd := &cobra.Command{}
+	contextsCmd.PersistentFlags().StringSlice("contexts", []string{"prod-eu", "prod-us", "prod-asia"}, "")
+	_ = viper.BindPFlag("contexts", contextsCmd.PersistentFlags().Lookup("contexts"))
+	defer func() {
+		resetCmd := &cobra.Command{}
+		resetCmd.PersistentFlags().StringSlice("contexts", []string{}, "")
+		_ = viper.BindPFlag("contexts", resetCmd.PersistentFlags().Lookup("contexts"))
+	}()
+
+	printer.Clean()
+	printer.SetFormat(printer.FormatPlain)
+	teamCmd := &cobra.Command{}
+	teamCmd.Flags().String("name", "team", "")
+	teamCmd.Flags().String("display_name", "Team", "")
+	err = withClient(createTeamCmdF)(teamCmd, []string{})
+	require.EqualError(t, err, "the command failed in 1 of 3 contexts: prod-asia")
+	require.Len(t, printer.GetLines(), 2)
+	require.Equal(t, []interface{}{`couldn't find credentials for connection "prod-asia"`}, printer.GetErrorLines())
+
+	// the team is created in each of the servers
+	for _, s := range servers {
+		c := model.NewAPIv4Client(s.URL)
+		c.SetToken(s.AdminToken)
+
+		_, resp := c.GetTeamByName("team", "")
+		require.Nil(t, resp.Error)
+	}
+
+	t.Run("should apply the defaults of each context", func(t *testing.T) {
+		for name, s := range servers {
+			c := model.NewAPIv4Client(s.URL)
+			c.SetToken(s.AdminToken)
+			_, resp := c.CreateTeam(&model.Team{Name: name, DisplayName: name, Type: model.TEAM_OPEN})
+			require.Nil(t, resp.Error)
+			credentialsList[name].Defaults = map[string]string{"team": name}
+		}
+		require.NoError(t, SaveCredentialsList(&credentialsList))
+
+		printer.Clean()
+		channelCmd := &cobra.Command{}
+		channelCmd.Flags().String("name", "defaults", "")
+		channelCmd.Flags().String("display_name", "Defaults", "")
+		channelCmd.Flags().String("team", "", "")
+		err := withClient(createChannelCmdF)(channelCmd, []string{})
+		require.EqualError(t, err, "the command failed in 1 of 3 contexts: prod-asia")
+		require.Len(t, printer.GetLines(), 2)
+		require.False(t, channelCmd.Flags().Changed("team"))
+
+		// the channel is created in the default team of each server
+		for name, s := range servers {
+			c := model.NewAPIv4Client(s.URL)
+			c.SetToken(s.AdminToken)
+
+			team, resp := c.GetTeamByName(name, "")
+			require.Nil(t, resp.Error)
+			_, resp = c.GetChannelByName("defaults", team.Id, "")
+			require.Nil(t, resp.Error)
+		}
+	})
+
+	t.Run("should check each credentials with their own defaults", func(t *testing.T) {
+		credentialsList["prod-us"].Defaults = map[string]string{"proxy": "http://127.0.0.1:1"}
+		require.NoError(t, SaveCredentialsList(&credentialsList))
+
+		printer.Clean()
+		printer.SetFormat(printer.FormatJSON)
+		defer printer.SetFormat(printer.FormatPlain)
+		require.NoError(t, statusCmdF(&cobra.Command{}, []string{}))
+		require.Len(t, printer.GetLines(), 2)
+		require.True(t, printer.GetLines()[0].(*authStatus).Valid)
+		status := printer.GetLines()[1].(*authStatus)
+		require.Equal(t, "prod-us", status.Name)
+		require.False(t, status.Valid)
+		require.Contains(t, status.Error, "proxyconnect")
+		require.Empty(t, viper.GetString("proxy"))
+	})
+}
@@ -4,12 +4,12 @@
 package commands
 
 import (
-	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"net/http"
 	"os"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/mattermost/mattermost-server/v5/model"
@@ -29,6 +29,11 @@ var (
 		x509.ECDSAWithSHA1: true,
 	}
 	expectedSocketMode os.FileMode = os.ModeSocket | 0600
+
+	// currentServer identifies the server that the command is running
+	// against: the URL of the credentials, or the socket path in local
+	// mode
+	currentServer string
 )
 
 func CheckVersionMatch(version, serverVersion string) bool {
@@ -40,29 +45,158 @@ func CheckVersionMatch(version, serverVersion string) bool {
 
 func withClient(fn func(c client.Client, cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
+		contexts, err := getTargetContexts()
+		if err != nil {
+			return err
+		}
+
 		if viper.GetBool("local") {
-			c, err := InitUnixClient(viper.GetString("local-socket-path"))
-			if err != nil {
-				return err
+			if len(contexts) > 0 {
+				return errors.New("the --local flag can't be used along with --contexts or --all-contexts")
 			}
-			return fn(c, cmd, args)
+			return runLocal(fn, cmd, args)
 		}
 
-		c, serverVersion, err := InitClient(viper.GetBool("insecure-sha1-intermediate"), viper.GetBool("insecure-tls-version"))
+		if len(contexts) > 0 {
+			return runInContexts(fn, cmd, args, contexts)
+		}
+
+		credentials, err := GetCurrentCredentials()
 		if err != nil {
 			return err
 		}
-		valid := CheckVersionMatch(Version, serverVersion)
-		if !valid {
-			if viper.GetBool("strict") {
-				printer.PrintError("ERROR: server version " + serverVersion + " doesn't match with mmctl version " + Version + ". Strict flag is set, so the command will not be run")
-				os.Exit(1)
-			}
-			printer.PrintError("WARNING: server version " + serverVersion + " doesn't match mmctl version " + Version)
+		restore := applyContextDefaults(credentials)
+		defer restore()
+		// the format of the output can only be taken from the
+		// credentials when the command runs against a single server
+		if _, ok := credentials.Defaults["format"]; ok {
+			applyFormat(cmd)
 		}
+		return runWithCredentials(fn, cmd, args, credentials)
+	}
+}
 
-		return fn(c, cmd, args)
+// runLocal runs the command against the server listening on the unix
+// socket of the local mode
+func runLocal(fn func(c client.Client, cmd *cobra.Command, args []string) error, cmd *cobra.Command, args []string) error {
+	socketPath := viper.GetString("local-socket-path")
+	c, err := InitUnixClient(socketPath)
+	if err != nil {
+		return err
 	}
+	currentServer = socketPath
+	return fn(wrapClient(c), cmd, args)
+}
+
+// runWithCredentials runs the command against the server of the
+// credentials, once their defaults have been applied
+func runWithCredentials(fn func(c client.Client, cmd *cobra.Command, args []string) error, cmd *cobra.Command, args []string, credentials *Credentials) error {
+	restoreTeam, err := applyDefaultTeam(cmd)
+	if err != nil {
+		return err
+	}
+	defer restoreTeam()
+
+	// the credentials may use the local mode by default
+	if viper.GetBool("local") {
+		return runLocal(fn, cmd, args)
+	}
+
+	c, err := initCheckedClient(credentials)
+	if err != nil {
+		return err
+	}
+	return fn(c, cmd, args)
+}
+
+// getTargetContexts returns the names of the credentials that the
+// command has to run against, or none if it runs against the current
+// ones
+func getTargetContexts() ([]string, error) {
+	contexts := viper.GetStringSlice("contexts")
+	if !viper.GetBool("all-contexts") {
+		return contexts, nil
+	}
+
+	if len(contexts) > 0 {
+		return nil, errors.New("the --contexts and --all-contexts flags can't be used together")
+	}
+
+	credentialsList, err := ReadCredentialsList()
+	if err != nil {
+		return nil, err
+	}
+	for name := range *credentialsList {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+	return contexts, nil
+}
+
+// runInContexts runs the command against the server of each of the
+// credentials, one after another. The output of each server is
+// identified by the name of its credentials, and the command continues
+// with the next server if it fails in one of them
+func runInContexts(fn func(c client.Client, cmd *cobra.Command, args []string) error, cmd *cobra.Command, args []string, contexts []string) error {
+	failed := []string{}
+	for _, name := range contexts {
+		printer.SetInstance(name)
+		if err := runInContext(fn, cmd, args, name); err != nil {
+			e, _ := classifyError(err)
+			printer.PrintErrorObject(err.Error(), e)
+			failed = append(failed, name)
+		}
+	}
+	printer.SetInstance("")
+
+	if len(failed) > 0 {
+		return fmt.Errorf("the command failed in %d of %d contexts: %s", len(failed), len(contexts), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func runInContext(fn func(c client.Client, cmd *cobra.Command, args []string) error, cmd *cobra.Command, args []string, name string) error {
+	credentials, err := GetCredentials(name)
+	if err != nil {
+		return err
+	}
+
+	restore := applyContextDefaults(credentials)
+	defer restore()
+	return runWithCredentials(fn, cmd, args, credentials)
+}
+
+// initCheckedClient creates the client for the server of the
+// credentials, checking that its version matches the mmctl one. If the
+// session of the credentials has expired, they are renewed when
+// possible
+func initCheckedClient(credentials *Credentials) (client.Client, error) {
+	c, serverVersion, err := InitClientWithCredentials(credentials, viper.GetBool("insecure-sha1-intermediate"), viper.GetBool("insecure-tls-version"))
+	if err != nil && isSessionExpired(err) {
+		c, serverVersion, err = refreshCredentials(credentials)
+	}
+	if err != nil {
+		return nil, err
+	}
+	valid := CheckVersionMatch(Version, serverVersion)
+	if !valid {
+		if viper.GetBool("strict") {
+			return nil, errors.New("server version " + serverVersion + " doesn't match with mmctl version " + Version + ". Strict flag is set, so the command will not be run")
+		}
+		printer.PrintWarning("WARNING: server version " + serverVersion + " doesn't match mmctl version " + Version)
+	}
+
+	currentServer = credentials.InstanceURL
+	return wrapClient(c), nil
+}
+
+// wrapClient applies the client wrappers enabled through the root
+// flags
+func wrapClient(c client.Client) client.Client {
+	if viper.GetBool("dry-run") {
+		return newDryRunClient(c)
+	}
+	return c
 }
 
 func localOnlyPrecheck(cmd *cobra.Command, args []string) {
@@ -103,34 +237,36 @@ func VerifyCertificates(rawCerts [][]byte, verifiedChains [][]*x509.Certificate)
 	return fmt.Errorf("insecure algorithm found in the certificate chain. Use --insecure-sha1-intermediate flag to ignore. Aborting")
 }
 
-func NewAPIv4Client(instanceURL string, allowInsecureSHA1, allowInsecureTLS bool) *model.Client4 {
+func NewAPIv4Client(instanceURL string, allowInsecureSHA1, allowInsecureTLS bool) (*model.Client4, error) {
 	client := model.NewAPIv4Client(instanceURL)
 	userAgent := fmt.Sprintf("mmctl/%s (%s)", Version, runtime.GOOS)
 	client.HttpHeader = map[string]string{"User-Agent": userAgent}
 
-	tlsConfig := &tls.Config{
-		MinVersion: tls.VersionTLS12,
-	}
-
-	if allowInsecureTLS {
-		tlsConfig.MinVersion = tls.VersionTLS10
+	tlsConfig, err := newTLSConfig(allowInsecureSHA1, allowInsecureTLS)
+	if err != nil {
+		return nil, err
 	}
 
-	if !allowInsecureSHA1 {
-		tlsConfig.VerifyPeerCertificate = VerifyCertificates
+	proxy, err := getProxy()
+	if err != nil {
+		return nil, err
 	}
 
 	client.HttpClient = &http.Client{
-		Transport: &http.Transport{
+		Transport: newRetryTransport(wrapTrace(&http.Transport{
+			Proxy:           proxy,
 			TLSClientConfig: tlsConfig,
-		},
+		}), viper.GetInt("max-retries"), viper.GetDuration("timeout"), viper.GetBool("verbose-requests")),
 	}
 
-	return client
+	return client, nil
 }
 
 func InitClientWithUsernameAndPassword(username, password, instanceURL string, allowInsecureSHA1, allowInsecureTLS bool) (*model.Client4, string, error) {
-	client := NewAPIv4Client(instanceURL, allowInsecureSHA1, allowInsecureTLS)
+	client, err := NewAPIv4Client(instanceURL, allowInsecureSHA1, allowInsecureTLS)
+	if err != nil {
+		return nil, "", err
+	}
 
 	_, response := client.Login(username, password)
 	if response.Error != nil {
@@ -140,7 +276,10 @@ func InitClientWithUsernameAndPassword(username, password, instanceURL string, a
 }
 
 func InitClientWithMFA(username, password, mfaToken, instanceURL string, allowInsecureSHA1, allowInsecureTLS bool) (*model.Client4, string, error) {
-	client := NewAPIv4Client(instanceURL, allowInsecureSHA1, allowInsecureTLS)
+	client, err := NewAPIv4Client(instanceURL, allowInsecureSHA1, allowInsecureTLS)
+	if err != nil {
+		return nil, "", err
+	}
 	_, response := client.LoginWithMFA(username, password, mfaToken)
 	if response.Error != nil {
 		return nil, "", checkInsecureTLSError(response.Error, allowInsecureTLS)
@@ -149,7 +288,10 @@ func InitClientWithMFA(username, password, mfaToken, instanceURL string, allowIn
 }
 
 func InitClientWithCredentials(credentials *Credentials, allowInsecureSHA1, allowInsecureTLS bool) (*model.Client4, string, error) {
-	client := NewAPIv4Client(credentials.InstanceURL, allowInsecureSHA1, allowInsecureTLS)
+	client, err := NewAPIv4Client(credentials.InstanceURL, allowInsecureSHA1, allowInsecureTLS)
+	if err != nil {
+		return nil, "", err
+	}
 
 	client.AuthType = model.HEADER_BEARER
 	client.AuthToken = credentials.AuthToken
@@ -175,7 +317,13 @@ func InitWebSocketClient() (*model.WebSocketClient, error) {
 	if err != nil {
 		return nil, err
 	}
-	client, appErr := model.NewWebSocketClient4(strings.Replace(credentials.InstanceURL, "http", "ws", 1), credentials.AuthToken)
+	restore := applyContextDefaults(credentials)
+	defer restore()
+	dialer, err := newWebSocketDialer(viper.GetBool("insecure-sha1-intermediate"), viper.GetBool("insecure-tls-version"))
+	if err != nil {
+		return nil, err
+	}
+	client, appErr := model.NewWebSocketClient4WithDialer(dialer, strings.Replace(credentials.InstanceURL, "http", "ws", 1), credentials.AuthToken)
 	if appErr != nil {
 		return nil, errors.Wrap(appErr, "unable to create the websockets connection")
 	}
@@ -187,7 +335,9 @@ func InitUnixClient(socketPath string) (*model.Client4, error) {
 		return nil, err
 	}
 
-	return model.NewAPIv4SocketClient(socketPath), nil
+	client := model.NewAPIv4SocketClient(socketPath)
+	client.HttpClient.Transport = wrapTrace(client.HttpClient.Transport)
+	return client, nil
 }
 
 func checkInsecureTLSError(err *model.AppError, allowInsecureTLS bool) error {
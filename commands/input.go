@@ -0,0 +1,367 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/mattermost/mmctl/client"
+	"github.com/mattermost/mmctl/printer"
+)
+
+const (
+	inputFormatCSV  = "csv"
+	inputFormatJSON = "json"
+
+	// inputErrorColumn is the column added to the failures report.
+	// It is ignored when reading an input file, so the report can be
+	// used as the input of a new run
+	inputErrorColumn = "error"
+)
+
+// userColumns are the columns of an input file that can identify a
+// user when it is passed as a positional argument
+var userColumns = []string{"user", "id", "email", "username"}
+
+// inputRow is a row of an input file, with its values indexed by
+// column name
+type inputRow map[string]string
+
+// inputFile is the parsed content of an input file
+type inputFile struct {
+	format  string
+	columns []string
+	rows    []inputRow
+}
+
+// addFromFileFlags registers the flags to read the arguments of a
+// command from a file, and sets the PreRun of the command so its
+// required flags can be read from the file as well
+func addFromFileFlags(cmd *cobra.Command) {
+	cmd.Flags().String("from-file", "", "Read the arguments and flags from a CSV or JSON file, or from the standard input if \"-\". Each row runs the command once, with its columns mapped onto the flags of the same name")
+	cmd.Flags().String("failures-file", "", "Write the rows of the input file that failed to this path, in the same format, so they can be used as input again")
+	cmd.PreRun = clearRequiredFlagsFromFile
+}
+
+// argsFromFile wraps a positional arguments validator so it is skipped
+// when the arguments are read from a file
+func argsFromFile(validator cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if path, _ := cmd.Flags().GetString("from-file"); path != "" {
+			return nil
+		}
+		return validator(cmd, args)
+	}
+}
+
+// clearRequiredFlagsFromFile removes the required mark from the flags
+// of the command if the input comes from a file, as their values are
+// read from its columns
+func clearRequiredFlagsFromFile(cmd *cobra.Command, args []string) {
+	if path, _ := cmd.Flags().GetString("from-file"); path == "" {
+		return
+	}
+
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if _, ok := f.Annotations[cobra.BashCompOneRequiredFlag]; ok {
+			_ = cmd.Flags().SetAnnotation(f.Name, cobra.BashCompOneRequiredFlag, []string{"false"})
+		}
+	})
+}
+
+// readInputFile reads and parses an input file. JSON files contain an
+// array of objects and CSV files have a header with the column names
+func readInputFile(path string) (*inputFile, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read input file")
+	}
+
+	format := inputFormatCSV
+	if strings.EqualFold(filepath.Ext(path), ".json") || bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		format = inputFormatJSON
+	}
+
+	input := &inputFile{format: format}
+	if format == inputFormatJSON {
+		err = input.parseJSON(data)
+	} else {
+		err = input.parseCSV(data)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse input file")
+	}
+
+	return input, nil
+}
+
+func (f *inputFile) parseCSV(data []byte) error {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return errors.New("the file has no header")
+	}
+
+	for _, column := range records[0] {
+		f.columns = append(f.columns, normalizeInputColumn(column))
+	}
+	for _, record := range records[1:] {
+		row := inputRow{}
+		for i, value := range record {
+			row[f.columns[i]] = strings.TrimSpace(value)
+		}
+		f.rows = append(f.rows, row)
+	}
+
+	return nil
+}
+
+func (f *inputFile) parseJSON(data []byte) error {
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for _, object := range objects {
+		row := inputRow{}
+		for key, value := range object {
+			column := normalizeInputColumn(key)
+			if !seen[column] {
+				seen[column] = true
+				f.columns = append(f.columns, column)
+			}
+			if value != nil {
+				row[column] = strings.TrimSpace(fmt.Sprint(value))
+			}
+		}
+		f.rows = append(f.rows, row)
+	}
+	sort.Strings(f.columns)
+
+	return nil
+}
+
+func normalizeInputColumn(column string) string {
+	return strings.ToLower(strings.TrimSpace(column))
+}
+
+// writeFailures writes the rows that failed with their errors, using
+// the same format and columns as the input file
+func (f *inputFile) writeFailures(path string, rows []inputRow) error {
+	columns := append([]string{}, f.columns...)
+	hasErrorColumn := false
+	for _, column := range columns {
+		if column == inputErrorColumn {
+			hasErrorColumn = true
+		}
+	}
+	if !hasErrorColumn {
+		columns = append(columns, inputErrorColumn)
+	}
+
+	var buf bytes.Buffer
+	if f.format == inputFormatJSON {
+		b, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		buf.WriteString("\n")
+	} else {
+		w := csv.NewWriter(&buf)
+		_ = w.Write(columns)
+		for _, row := range rows {
+			record := make([]string, len(columns))
+			for i, column := range columns {
+				record[i] = row[column]
+			}
+			_ = w.Write(record)
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+	}
+
+	return ioutil.WriteFile(path, buf.Bytes(), 0600)
+}
+
+// lookupInputFlag returns the flag of a command that corresponds to a
+// column, accepting both dashes and underscores as separators
+func lookupInputFlag(flags *pflag.FlagSet, column string) *pflag.Flag {
+	for _, name := range []string{column, strings.ReplaceAll(column, "_", "-"), strings.ReplaceAll(column, "-", "_")} {
+		if f := flags.Lookup(name); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// newRowCommand creates a command with the same flags as cmd, with
+// the values set in the command line, and then sets the flags present
+// in the row
+func newRowCommand(cmd *cobra.Command, row inputRow) (*cobra.Command, error) {
+	rowCmd := &cobra.Command{}
+	rowFlags := rowCmd.Flags()
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Name == "from-file" || f.Name == "failures-file" {
+			return
+		}
+
+		switch f.Value.Type() {
+		case "bool":
+			rowFlags.Bool(f.Name, false, f.Usage)
+		case "int":
+			rowFlags.Int(f.Name, 0, f.Usage)
+		default:
+			rowFlags.String(f.Name, "", f.Usage)
+		}
+
+		if f.Changed {
+			_ = rowFlags.Set(f.Name, f.Value.String())
+		} else {
+			_ = rowFlags.Lookup(f.Name).Value.Set(f.DefValue)
+		}
+	})
+
+	for column, value := range row {
+		if value == "" {
+			continue
+		}
+		f := lookupInputFlag(rowFlags, column)
+		if f == nil {
+			continue
+		}
+		if err := rowFlags.Set(f.Name, value); err != nil {
+			return nil, errors.Wrapf(err, "invalid value for column %q", column)
+		}
+	}
+
+	return rowCmd, nil
+}
+
+// runFromFile runs a command once for each row of the file set in the
+// from-file flag. The flags of the command are read from the columns
+// with the same name, and its positional arguments are read in order
+// from the argColumns groups, using the first column of each group
+// with a value. Positional arguments passed in the command line are
+// used as the first ones for every row
+func runFromFile(c client.Client, cmd *cobra.Command, args []string, argColumns [][]string, fn func(c client.Client, cmd *cobra.Command, args []string) error) error {
+	path, _ := cmd.Flags().GetString("from-file")
+	failuresPath, _ := cmd.Flags().GetString("failures-file")
+
+	input, err := readInputFile(path)
+	if err != nil {
+		return err
+	}
+
+	if len(args) > len(argColumns) {
+		return errors.Errorf("expected at most %d arguments when reading from a file", len(argColumns))
+	}
+	argColumns = argColumns[len(args):]
+
+	knownColumns := map[string]bool{inputErrorColumn: true}
+	for _, group := range argColumns {
+		for _, column := range group {
+			knownColumns[column] = true
+		}
+	}
+	for _, column := range input.columns {
+		if !knownColumns[column] && lookupInputFlag(cmd.Flags(), column) == nil {
+			return errors.Errorf("unknown column %q in input file", column)
+		}
+	}
+
+	failures := []inputRow{}
+	for i, row := range input.rows {
+		printed, rowErr := runInputRow(c, cmd, row, args, argColumns, fn)
+		if rowErr == nil {
+			continue
+		}
+
+		// the errors that the command already printed are not printed
+		// again, so each failure is reported once
+		if !printed {
+			printItemError(fmt.Sprintf("Row %d failed: %s", i+1, rowErr), rowErr)
+		}
+		failure := inputRow{}
+		for column, value := range row {
+			failure[column] = value
+		}
+		failure[inputErrorColumn] = rowErr.Error()
+		failures = append(failures, failure)
+	}
+
+	if failuresPath != "" && len(failures) > 0 {
+		if err := input.writeFailures(failuresPath, failures); err != nil {
+			return errors.Wrap(err, "could not write the failures file")
+		}
+	}
+
+	if len(failures) > 0 {
+		return errors.Errorf("%d of %d rows failed", len(failures), len(input.rows))
+	}
+	return nil
+}
+
+// runInputRow runs the command for a row. As most commands print the
+// errors of each item instead of returning them, the errors printed
+// while the command runs are also considered failures. The returned
+// boolean tells whether the error was already printed
+func runInputRow(c client.Client, cmd *cobra.Command, row inputRow, args []string, argColumns [][]string, fn func(c client.Client, cmd *cobra.Command, args []string) error) (bool, error) {
+	rowArgs := append([]string{}, args...)
+	for _, group := range argColumns {
+		value := ""
+		for _, column := range group {
+			if value = row[column]; value != "" {
+				break
+			}
+		}
+		if value == "" {
+			return false, errors.Errorf("missing value for column %q", group[0])
+		}
+		rowArgs = append(rowArgs, value)
+	}
+
+	rowCmd, err := newRowCommand(cmd, row)
+	if err != nil {
+		return false, err
+	}
+
+	printedErrors := len(printer.GetErrorLines())
+	if err := fn(c, rowCmd, rowArgs); err != nil {
+		return false, err
+	}
+
+	if errorLines := printer.GetErrorLines()[printedErrors:]; len(errorLines) > 0 {
+		messages := make([]string, len(errorLines))
+		for i, line := range errorLines {
+			messages[i] = fmt.Sprint(line)
+		}
+		return true, errors.New(strings.Join(messages, "; "))
+	}
+
+	return false, nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+func (s *MmctlUnitTestSuite) writeInputFile(name, contents string) string {
+	dir, err := ioutil.TempDir("", "mmctl-input")
+	s.Require().NoError(err)
+
+	path := filepath.Join(dir, name)
+	s.Require().NoError(ioutil.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+func (s *MmctlUnitTestSuite) TestRunFromFile() {
+	s.Run("should create users from a CSV file and report the failures", func() {
+		printer.Clean()
+		path := s.writeInputFile("users.csv", "username,email,password,system_admin\n"+
+			"john.doe,john@example.com,Password1,true\n"+
+			"jane.doe,jane@example.com,Password2,\n")
+		defer os.RemoveAll(filepath.Dir(path))
+		failuresPath := filepath.Join(filepath.Dir(path), "failures.csv")
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("username", "", "")
+		cmd.Flags().String("email", "", "")
+		cmd.Flags().String("password", "", "")
+		cmd.Flags().String("nickname", "", "")
+		cmd.Flags().Bool("system_admin", false, "")
+		addFromFileFlags(cmd)
+		s.Require().NoError(cmd.Flags().Set("from-file", path))
+		s.Require().NoError(cmd.Flags().Set("failures-file", failuresPath))
+		s.Require().NoError(cmd.Flags().Set("nickname", "imported"))
+
+		johnDoe := &model.User{Id: "johnID", Username: "john.doe"}
+		s.client.
+			EXPECT().
+			CreateUser(&model.User{Username: "john.doe", Email: "john@example.com", Password: "Password1", Nickname: "imported"}).
+			Return(johnDoe, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			UpdateUserRoles(johnDoe.Id, "system_user system_admin").
+			Return(true, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			CreateUser(&model.User{Username: "jane.doe", Email: "jane@example.com", Password: "Password2", Nickname: "imported"}).
+			Return(nil, &model.Response{Error: &model.AppError{Message: "username taken"}}).
+			Times(1)
+
+		err := userCreateCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "1 of 2 rows failed")
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(johnDoe, printer.GetLines()[0])
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().Equal("Row 2 failed: Unable to create user. Error: : username taken, ", printer.GetErrorLines()[0])
+
+		failures, err := ioutil.ReadFile(failuresPath)
+		s.Require().NoError(err)
+		s.Require().Equal("username,email,password,system_admin,error\n"+
+			"jane.doe,jane@example.com,Password2,,\"Unable to create user. Error: : username taken, \"\n", string(failures))
+	})
+
+	s.Run("should add users to a channel from a JSON file", func() {
+		printer.Clean()
+		path := s.writeInputFile("users.json", `[{"email": "john@example.com"}, {"username": "jane.doe"}]`)
+		defer os.RemoveAll(filepath.Dir(path))
+
+		cmd := &cobra.Command{}
+		addFromFileFlags(cmd)
+		s.Require().NoError(cmd.Flags().Set("from-file", path))
+
+		mockTeam := &model.Team{Id: teamID}
+		mockChannel := &model.Channel{Id: channelID, Name: channelName}
+		s.client.EXPECT().GetTeam(teamID, "").Return(mockTeam, &model.Response{}).Times(2)
+		s.client.EXPECT().GetChannelByNameIncludeDeleted(channelName, teamID, "").Return(mockChannel, &model.Response{}).Times(2)
+		s.client.EXPECT().GetUserByEmail("john@example.com", "").Return(&model.User{Id: "johnID"}, &model.Response{}).Times(1)
+		s.client.EXPECT().GetUserByEmail("jane.doe", "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().GetUserByUsername("jane.doe", "").Return(&model.User{Id: "janeID"}, &model.Response{}).Times(1)
+		s.client.EXPECT().AddChannelMember(channelID, "johnID").Return(&model.ChannelMember{}, &model.Response{}).Times(1)
+		s.client.EXPECT().AddChannelMember(channelID, "janeID").Return(&model.ChannelMember{}, &model.Response{}).Times(1)
+
+		err := channelUsersAddCmdF(s.client, cmd, []string{teamID + ":" + channelName})
+		s.Require().NoError(err)
+		s.Require().Empty(printer.GetErrorLines())
+	})
+
+	s.Run("should report the errors printed by the command once", func() {
+		printer.Clean()
+		path := s.writeInputFile("users.csv", "email\njohn@example.com\nunknown@example.com\n")
+		defer os.RemoveAll(filepath.Dir(path))
+
+		cmd := &cobra.Command{}
+		addFromFileFlags(cmd)
+		s.Require().NoError(cmd.Flags().Set("from-file", path))
+
+		mockTeam := &model.Team{Id: teamID}
+		mockChannel := &model.Channel{Id: channelID, Name: channelName}
+		s.client.EXPECT().GetTeam(teamID, "").Return(mockTeam, &model.Response{}).Times(2)
+		s.client.EXPECT().GetChannelByNameIncludeDeleted(channelName, teamID, "").Return(mockChannel, &model.Response{}).Times(2)
+		s.client.EXPECT().GetUserByEmail("john@example.com", "").Return(&model.User{Id: "johnID"}, &model.Response{}).Times(1)
+		s.client.EXPECT().GetUserByEmail("unknown@example.com", "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().GetUserByUsername("unknown@example.com", "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().GetUser("unknown@example.com", "").Return(nil, &model.Response{}).Times(1)
+		s.client.EXPECT().AddChannelMember(channelID, "johnID").Return(&model.ChannelMember{}, &model.Response{}).Times(1)
+
+		err := channelUsersAddCmdF(s.client, cmd, []string{teamID + ":" + channelName})
+		s.Require().EqualError(err, "1 of 2 rows failed")
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().Equal("Can't find user 'unknown@example.com'", printer.GetErrorLines()[0])
+	})
+
+	s.Run("should fail with unknown columns", func() {
+		printer.Clean()
+		path := s.writeInputFile("users.csv", "username,unknown\njohn.doe,value\n")
+		defer os.RemoveAll(filepath.Dir(path))
+
+		cmd := &cobra.Command{}
+		addFromFileFlags(cmd)
+		s.Require().NoError(cmd.Flags().Set("from-file", path))
+
+		err := userDeactivateCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, `unknown column "unknown" in input file`)
+	})
+
+	s.Run("should fail the rows without the positional arguments", func() {
+		printer.Clean()
+		path := s.writeInputFile("users.csv", "team,username\n,john.doe\n")
+		defer os.RemoveAll(filepath.Dir(path))
+
+		cmd := &cobra.Command{}
+		addFromFileFlags(cmd)
+		s.Require().NoError(cmd.Flags().Set("from-file", path))
+
+		err := teamUsersAddCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, "1 of 1 rows failed")
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().Equal(`Row 1 failed: missing value for column "team"`, printer.GetErrorLines()[0])
+	})
+}
@@ -92,7 +92,7 @@ func integrityCmdF(c client.Client, command *cobra.Command, args []string) error
 	}
 	for _, result := range results {
 		if result.Err != nil {
-			printer.PrintError(result.Err.Error())
+			printItemError(result.Err.Error(), result.Err)
 			continue
 		}
 		printIntegrityCheckResult(result, verboseFlag)
@@ -6,6 +6,7 @@ package commands
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 
@@ -33,8 +34,8 @@ func init() {
 func logsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("format") {
 		return errors.New("the \"--format\" flag cannot be used with this command")
-	} else if viper.GetString("format") == printer.FormatJSON {
-		return errors.New("json formatting cannot be applied on this command. Please check the value of \"MMCTL_FORMAT\"")
+	} else if format := viper.GetString("format"); format != "" && format != printer.FormatPlain {
+		return fmt.Errorf("%s formatting cannot be applied on this command. Please check the value of \"MMCTL_FORMAT\"", format)
 	}
 
 	number, _ := cmd.Flags().GetInt("number")
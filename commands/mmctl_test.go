@@ -4,11 +4,15 @@
 package commands
 
 import (
+	"io/ioutil"
+	"os"
+
 	"github.com/mattermost/mmctl/client"
 	"github.com/mattermost/mmctl/mocks"
 	"github.com/mattermost/mmctl/printer"
 
 	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/mattermost/mattermost-server/v5/api4"
@@ -18,8 +22,23 @@ var EnableEnterpriseTests string
 
 type MmctlUnitTestSuite struct {
 	suite.Suite
-	mockCtrl *gomock.Controller
-	client   *mocks.MockClient
+	mockCtrl   *gomock.Controller
+	client     *mocks.MockClient
+	configPath string
+}
+
+// SetupSuite points the configuration directory to a temporary one, so
+// the files that the commands save don't end up in the user's one
+func (s *MmctlUnitTestSuite) SetupSuite() {
+	configPath, err := ioutil.TempDir("", "mmctl-unit-")
+	s.Require().NoError(err)
+	s.configPath = configPath
+	viper.Set("config-path", configPath)
+}
+
+func (s *MmctlUnitTestSuite) TearDownSuite() {
+	viper.Set("config-path", xdgConfigHomeVar)
+	os.RemoveAll(s.configPath)
 }
 
 func (s *MmctlUnitTestSuite) SetupTest() {
@@ -0,0 +1,112 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+// addParallelFlag registers the flag that sets the number of workers
+// used by the bulk commands
+func addParallelFlag(cmd *cobra.Command) {
+	cmd.Flags().Int("parallel", 1, "Number of requests to send to the server at the same time")
+}
+
+// getParallelWorkers returns the number of workers set through the
+// parallel flag, that is at least one
+func getParallelWorkers(cmd *cobra.Command) int {
+	workers, _ := cmd.Flags().GetInt("parallel")
+	if workers < 1 {
+		return 1
+	}
+	return workers
+}
+
+// runParallel calls work for each one of n items using up to workers
+// goroutines, and returns the error of each item. The report function,
+// if present, is called from the calling goroutine for every item in
+// order, as soon as the item and all the previous ones have finished,
+// so the output of the command doesn't depend on the scheduling. With
+// a single worker the items are processed one after another without
+// starting any goroutine
+func runParallel(workers, n int, work func(i int) error, report func(i int, err error)) []error {
+	errs := make([]error, n)
+
+	if workers <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			errs[i] = work(i)
+			if report != nil {
+				report(i, errs[i])
+			}
+		}
+		return errs
+	}
+
+	if workers > n {
+		workers = n
+	}
+
+	items := make(chan int)
+	done := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range items {
+				errs[i] = work(i)
+				done <- i
+			}
+		}()
+	}
+
+	go func() {
+		for i := 0; i < n; i++ {
+			items <- i
+		}
+		close(items)
+		wg.Wait()
+		close(done)
+	}()
+
+	finished := make([]bool, n)
+	next := 0
+	for i := range done {
+		finished[i] = true
+		for next < n && finished[next] {
+			if report != nil {
+				report(next, errs[next])
+			}
+			next++
+		}
+	}
+
+	return errs
+}
+
+// printParallelSummary prints how many items failed once a bulk
+// command finishes. It is only printed when the command runs with
+// several workers, as then the failures can be far apart in the output
+func printParallelSummary(workers, total, failed int) {
+	if workers <= 1 {
+		return
+	}
+	printer.PrintWarning(fmt.Sprintf("Finished processing %d items: %d succeeded, %d failed", total, total-failed, failed))
+}
+
+// countErrors returns the number of non nil errors
+func countErrors(errs []error) int {
+	count := 0
+	for _, err := range errs {
+		if err != nil {
+			count++
+		}
+	}
+	return count
+}
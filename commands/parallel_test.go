@@ -0,0 +1,69 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunParallel(t *testing.T) {
+	t.Run("should process all the items and report them in order", func(t *testing.T) {
+		var running, maxRunning int32
+		reported := []int{}
+
+		errs := runParallel(3, 20, func(i int) error {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+					break
+				}
+			}
+			// the first items take longer so they finish after the
+			// next ones
+			time.Sleep(time.Duration(20-i) * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+
+			if i%2 == 0 {
+				return errors.New("error")
+			}
+			return nil
+		}, func(i int, err error) {
+			reported = append(reported, i)
+			require.Equal(t, i%2 == 0, err != nil)
+		})
+
+		require.Len(t, errs, 20)
+		require.Equal(t, 10, countErrors(errs))
+		require.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(3))
+		for i := range reported {
+			require.Equal(t, i, reported[i])
+		}
+		require.Len(t, reported, 20)
+	})
+
+	t.Run("should process the items one after another with a single worker", func(t *testing.T) {
+		processed := []int{}
+		errs := runParallel(1, 5, func(i int) error {
+			processed = append(processed, i)
+			return nil
+		}, nil)
+
+		require.Equal(t, []int{0, 1, 2, 3, 4}, processed)
+		require.Equal(t, 0, countErrors(errs))
+	})
+
+	t.Run("should do nothing without items", func(t *testing.T) {
+		errs := runParallel(4, 0, func(i int) error {
+			t.Fatal("no item should be processed")
+			return nil
+		}, nil)
+		require.Empty(t, errs)
+	})
+}
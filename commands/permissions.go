@@ -76,17 +76,7 @@ func addPermissionsCmdF(c client.Client, cmd *cobra.Command, args []string) erro
 		return response.Error
 	}
 
-	newPermissions := role.Permissions
-
-	for _, permissionID := range args[1:] {
-		newPermissions = append(newPermissions, permissionID)
-
-		if ancillaryPermissions, ok := model.SysconsoleAncillaryPermissions[permissionID]; ok {
-			for _, ancillaryPermission := range ancillaryPermissions {
-				newPermissions = append(newPermissions, ancillaryPermission.Id)
-			}
-		}
-	}
+	newPermissions := addPermissions(role.Permissions, args[1:])
 
 	patchRole := model.RolePatch{
 		Permissions: &newPermissions,
@@ -105,27 +95,7 @@ func removePermissionsCmdF(c client.Client, cmd *cobra.Command, args []string) e
 		return response.Error
 	}
 
-	newPermissionSet := role.Permissions
-	for _, permissionID := range args[1:] {
-		newPermissionSet = removeFromStringSlice(newPermissionSet, permissionID)
-	}
-
-	var ancillaryPermissionsStillUsed []*model.Permission
-	for _, permissionID := range newPermissionSet {
-		if ancillaryPermissions, ok := model.SysconsoleAncillaryPermissions[permissionID]; ok {
-			ancillaryPermissionsStillUsed = append(ancillaryPermissionsStillUsed, ancillaryPermissions...)
-		}
-	}
-
-	for _, permissionID := range args[1:] {
-		if ancillaryPermissions, ok := model.SysconsoleAncillaryPermissions[permissionID]; ok {
-			for _, permission := range ancillaryPermissions {
-				if !permissionsSliceIncludes(ancillaryPermissionsStillUsed, permission) {
-					newPermissionSet = removeFromStringSlice(newPermissionSet, permission.Id)
-				}
-			}
-		}
-	}
+	newPermissionSet := removePermissions(role.Permissions, args[1:])
 
 	patchRole := model.RolePatch{
 		Permissions: &newPermissionSet,
@@ -163,6 +133,53 @@ func resetPermissionsCmdF(c client.Client, cmd *cobra.Command, args []string) er
 	return nil
 }
 
+// addPermissions returns the permissions with the given ones added,
+// along with the ancillary permissions that the sysconsole ones need
+func addPermissions(permissions []string, permissionIDs []string) []string {
+	newPermissions := permissions
+
+	for _, permissionID := range permissionIDs {
+		newPermissions = append(newPermissions, permissionID)
+
+		if ancillaryPermissions, ok := model.SysconsoleAncillaryPermissions[permissionID]; ok {
+			for _, ancillaryPermission := range ancillaryPermissions {
+				newPermissions = append(newPermissions, ancillaryPermission.Id)
+			}
+		}
+	}
+
+	return newPermissions
+}
+
+// removePermissions returns the permissions without the given ones,
+// removing as well the ancillary permissions of the sysconsole ones
+// if no other remaining permission needs them
+func removePermissions(permissions []string, permissionIDs []string) []string {
+	newPermissionSet := permissions
+	for _, permissionID := range permissionIDs {
+		newPermissionSet = removeFromStringSlice(newPermissionSet, permissionID)
+	}
+
+	var ancillaryPermissionsStillUsed []*model.Permission
+	for _, permissionID := range newPermissionSet {
+		if ancillaryPermissions, ok := model.SysconsoleAncillaryPermissions[permissionID]; ok {
+			ancillaryPermissionsStillUsed = append(ancillaryPermissionsStillUsed, ancillaryPermissions...)
+		}
+	}
+
+	for _, permissionID := range permissionIDs {
+		if ancillaryPermissions, ok := model.SysconsoleAncillaryPermissions[permissionID]; ok {
+			for _, permission := range ancillaryPermissions {
+				if !permissionsSliceIncludes(ancillaryPermissionsStillUsed, permission) {
+					newPermissionSet = removeFromStringSlice(newPermissionSet, permission.Id)
+				}
+			}
+		}
+	}
+
+	return newPermissionSet
+}
+
 func removeFromStringSlice(items []string, item string) []string {
 	newPermissions := []string{}
 	for _, x := range items {
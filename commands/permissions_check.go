@@ -0,0 +1,254 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/client"
+	"github.com/mattermost/mmctl/printer"
+)
+
+var CheckPermissionsCmd = &cobra.Command{
+	Use:   "check <user> <permission>",
+	Short: "Check if a user has a permission",
+	Long: `Check if a user has a permission, optionally in a team or a channel.
+The roles of the user are resolved from its system roles, its team and channel memberships and the schemes of the team and the channel, and each of them is shown along with whether it grants the permission.`,
+	Example: `  # check a system permission
+  $ mmctl permissions check john.doe create_team
+
+  # check a permission in a team
+  $ mmctl permissions check john.doe create_public_channel --team myteam
+
+  # check a permission in a channel
+  $ mmctl permissions check john.doe create_post --channel myteam:mychannel`,
+	Args: cobra.ExactArgs(2),
+	RunE: withClient(checkPermissionsCmdF),
+}
+
+func init() {
+	CheckPermissionsCmd.Flags().String("team", "", "Team to check the permission in")
+	CheckPermissionsCmd.Flags().String("channel", "", "Channel to check the permission in, referenced as team:channel")
+
+	PermissionsCmd.AddCommand(CheckPermissionsCmd)
+}
+
+// permissionCheckRole is one of the roles of the user and whether it
+// grants the checked permission
+type permissionCheckRole struct {
+	Role    string `json:"role"`
+	Scope   string `json:"scope"`
+	Source  string `json:"source"`
+	Granted bool   `json:"granted"`
+}
+
+// permissionCheck is the result of checking a permission for a user
+type permissionCheck struct {
+	User       string                 `json:"user"`
+	Permission string                 `json:"permission"`
+	Team       string                 `json:"team,omitempty"`
+	Channel    string                 `json:"channel,omitempty"`
+	Granted    bool                   `json:"granted"`
+	Roles      []*permissionCheckRole `json:"roles"`
+}
+
+const permissionCheckTemplate = `{{range .Roles}}{{.Scope}} role {{.Role}} ({{.Source}}): {{if .Granted}}grants{{else}}doesn't grant{{end}} {{$.Permission}}
+{{end}}User {{.User}} {{if .Granted}}has{{else}}doesn't have{{end}} the {{.Permission}} permission`
+
+// schemeRoles contains the roles that a team or a channel gives to its
+// guests, users and admins, and where they come from
+type schemeRoles struct {
+	source    string
+	guestRole string
+	userRole  string
+	adminRole string
+}
+
+// memberRoles returns the roles of a team or channel member, telling
+// apart the ones given by the scheme from the explicit ones
+func (sr *schemeRoles) memberRoles(scope, roles string, guest, user, admin bool) []*permissionCheckRole {
+	checkRoles := []*permissionCheckRole{}
+	fromScheme := map[string]bool{}
+	for _, scheme := range []struct {
+		enabled bool
+		role    string
+	}{{guest, sr.guestRole}, {user, sr.userRole}, {admin, sr.adminRole}} {
+		if scheme.enabled && scheme.role != "" {
+			fromScheme[scheme.role] = true
+			checkRoles = append(checkRoles, &permissionCheckRole{Role: scheme.role, Scope: scope, Source: sr.source})
+		}
+	}
+
+	// the roles of the member include the ones of the scheme, so only
+	// the remaining ones were explicitly assigned
+	for _, role := range strings.Fields(roles) {
+		if !fromScheme[role] {
+			checkRoles = append(checkRoles, &permissionCheckRole{Role: role, Scope: scope, Source: "explicit role"})
+		}
+	}
+
+	return checkRoles
+}
+
+// getSchemeRoles returns the roles of the scheme with the given ID for
+// the scope, or the fallback roles if there is no scheme
+func getSchemeRoles(c client.Client, schemeID *string, scope string, fallback *schemeRoles) (*schemeRoles, error) {
+	if schemeID == nil || *schemeID == "" {
+		return fallback, nil
+	}
+
+	scheme, response := c.GetScheme(*schemeID)
+	if response.Error != nil {
+		return nil, errors.Wrapf(response.Error, "failed to get scheme %s", *schemeID)
+	}
+
+	if scope == model.SCHEME_SCOPE_TEAM {
+		return &schemeRoles{
+			source:    scheme.Scope + " scheme " + scheme.Name,
+			guestRole: scheme.DefaultTeamGuestRole,
+			userRole:  scheme.DefaultTeamUserRole,
+			adminRole: scheme.DefaultTeamAdminRole,
+		}, nil
+	}
+	return &schemeRoles{
+		source:    scheme.Scope + " scheme " + scheme.Name,
+		guestRole: scheme.DefaultChannelGuestRole,
+		userRole:  scheme.DefaultChannelUserRole,
+		adminRole: scheme.DefaultChannelAdminRole,
+	}, nil
+}
+
+func checkPermissionsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	printer.SetSingle(true)
+
+	user := getUserFromUserArg(c, args[0])
+	if user == nil {
+		return fmt.Errorf("unable to find user %q", args[0])
+	}
+
+	permissionID := args[1]
+	validPermission := false
+	for _, permission := range model.AllPermissions {
+		if permission.Id == permissionID {
+			validPermission = true
+			break
+		}
+	}
+	if !validPermission {
+		return fmt.Errorf("unknown permission %q", permissionID)
+	}
+
+	result := &permissionCheck{User: user.Username, Permission: permissionID, Roles: []*permissionCheckRole{}}
+	for _, role := range strings.Fields(user.Roles) {
+		result.Roles = append(result.Roles, &permissionCheckRole{Role: role, Scope: "system", Source: "user roles"})
+	}
+
+	teamArg, _ := cmd.Flags().GetString("team")
+	channelArg, _ := cmd.Flags().GetString("channel")
+
+	var team *model.Team
+	if teamArg != "" {
+		if team = getTeamFromTeamArg(c, teamArg); team == nil {
+			return fmt.Errorf("unable to find team %q", teamArg)
+		}
+	}
+
+	var channel *model.Channel
+	if channelArg != "" {
+		if channel = getChannelFromChannelArg(c, channelArg); channel == nil {
+			return fmt.Errorf("unable to find channel %q", channelArg)
+		}
+
+		if team == nil {
+			var response *model.Response
+			if team, response = c.GetTeam(channel.TeamId, ""); response.Error != nil {
+				return errors.Wrap(response.Error, "failed to get the team of the channel")
+			}
+		} else if team.Id != channel.TeamId {
+			return fmt.Errorf("channel %q doesn't belong to team %q", channelArg, teamArg)
+		}
+	}
+
+	if team != nil {
+		result.Team = team.Name
+
+		member, response := c.GetTeamMember(team.Id, user.Id, "")
+		if response.Error != nil {
+			return fmt.Errorf("user %s is not a member of team %s", user.Username, team.Name)
+		}
+
+		teamRoles, err := getSchemeRoles(c, team.SchemeId, model.SCHEME_SCOPE_TEAM, &schemeRoles{
+			source:    "default roles",
+			guestRole: model.TEAM_GUEST_ROLE_ID,
+			userRole:  model.TEAM_USER_ROLE_ID,
+			adminRole: model.TEAM_ADMIN_ROLE_ID,
+		})
+		if err != nil {
+			return err
+		}
+		result.Roles = append(result.Roles, teamRoles.memberRoles("team", member.Roles, member.SchemeGuest, member.SchemeUser, member.SchemeAdmin)...)
+	}
+
+	if channel != nil {
+		result.Channel = channel.Name
+
+		member, response := c.GetChannelMember(channel.Id, user.Id, "")
+		if response.Error != nil {
+			return fmt.Errorf("user %s is not a member of channel %s", user.Username, channel.Name)
+		}
+
+		// the channel roles come from the channel scheme, then from the
+		// team scheme, and then from the default roles
+		channelRoles, err := getSchemeRoles(c, team.SchemeId, model.SCHEME_SCOPE_CHANNEL, &schemeRoles{
+			source:    "default roles",
+			guestRole: model.CHANNEL_GUEST_ROLE_ID,
+			userRole:  model.CHANNEL_USER_ROLE_ID,
+			adminRole: model.CHANNEL_ADMIN_ROLE_ID,
+		})
+		if err != nil {
+			return err
+		}
+		if channelRoles, err = getSchemeRoles(c, channel.SchemeId, model.SCHEME_SCOPE_CHANNEL, channelRoles); err != nil {
+			return err
+		}
+		result.Roles = append(result.Roles, channelRoles.memberRoles("channel", member.Roles, member.SchemeGuest, member.SchemeUser, member.SchemeAdmin)...)
+	}
+
+	roleNames := []string{}
+	for _, checkRole := range result.Roles {
+		roleNames = append(roleNames, checkRole.Role)
+	}
+	roles, response := c.GetRolesByNames(roleNames)
+	if response.Error != nil {
+		return errors.Wrap(response.Error, "failed to get roles")
+	}
+
+	rolesByName := map[string]*model.Role{}
+	for _, role := range roles {
+		rolesByName[role.Name] = role
+	}
+
+	for _, checkRole := range result.Roles {
+		role, ok := rolesByName[checkRole.Role]
+		if !ok {
+			printer.PrintWarning("Unable to find role '" + checkRole.Role + "'")
+			continue
+		}
+		for _, permission := range role.Permissions {
+			if permission == permissionID {
+				checkRole.Granted = true
+				result.Granted = true
+				break
+			}
+		}
+	}
+
+	printer.PrintT(permissionCheckTemplate, result)
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"net/http"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestCheckPermissionsCmd() {
+	notFound := &model.Response{Error: &model.AppError{StatusCode: http.StatusNotFound}}
+	user := &model.User{Id: model.NewId(), Username: "john.doe", Roles: "system_user"}
+
+	expectUser := func() {
+		s.client.
+			EXPECT().
+			GetUserByEmail(user.Username, "").
+			Return(nil, notFound).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetUserByUsername(user.Username, "").
+			Return(user, &model.Response{}).
+			Times(1)
+	}
+
+	newCmd := func(team, channel string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("team", team, "")
+		cmd.Flags().String("channel", channel, "")
+		return cmd
+	}
+
+	s.Run("should check a system permission", func() {
+		printer.Clean()
+		expectUser()
+		s.client.
+			EXPECT().
+			GetRolesByNames([]string{"system_user"}).
+			Return([]*model.Role{{Name: "system_user", Permissions: []string{"create_team"}}}, &model.Response{}).
+			Times(1)
+
+		err := checkPermissionsCmdF(s.client, newCmd("", ""), []string{user.Username, "create_team"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(&permissionCheck{
+			User:       user.Username,
+			Permission: "create_team",
+			Granted:    true,
+			Roles: []*permissionCheckRole{
+				{Role: "system_user", Scope: "system", Source: "user roles", Granted: true},
+			},
+		}, printer.GetLines()[0])
+	})
+
+	s.Run("should resolve the roles of a channel through the team scheme", func() {
+		printer.Clean()
+		scheme := &model.Scheme{
+			Id:                      model.NewId(),
+			Name:                    "support",
+			Scope:                   model.SCHEME_SCOPE_TEAM,
+			DefaultTeamUserRole:     "support_team_user",
+			DefaultTeamAdminRole:    "support_team_admin",
+			DefaultTeamGuestRole:    "support_team_guest",
+			DefaultChannelUserRole:  "support_channel_user",
+			DefaultChannelAdminRole: "support_channel_admin",
+			DefaultChannelGuestRole: "support_channel_guest",
+		}
+		team := &model.Team{Id: teamID, Name: "myteam", SchemeId: &scheme.Id}
+		channel := &model.Channel{Id: channelID, Name: channelName, TeamId: teamID}
+
+		expectUser()
+		s.client.
+			EXPECT().
+			GetChannel(channelID, "").
+			Return(channel, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetTeam(teamID, "").
+			Return(team, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetTeamMember(teamID, user.Id, "").
+			Return(&model.TeamMember{Roles: "support_team_user", SchemeUser: true}, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetChannelMember(channelID, user.Id, "").
+			Return(&model.ChannelMember{Roles: "support_channel_user custom_role", SchemeUser: true}, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetScheme(scheme.Id).
+			Return(scheme, &model.Response{}).
+			Times(2)
+		s.client.
+			EXPECT().
+			GetRolesByNames([]string{"system_user", "support_team_user", "support_channel_user", "custom_role"}).
+			Return([]*model.Role{
+				{Name: "system_user", Permissions: []string{"create_team"}},
+				{Name: "support_team_user", Permissions: []string{"view_team"}},
+				{Name: "support_channel_user", Permissions: []string{"read_channel"}},
+			}, &model.Response{}).
+			Times(1)
+
+		err := checkPermissionsCmdF(s.client, newCmd("", channelID), []string{user.Username, "create_post"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(&permissionCheck{
+			User:       user.Username,
+			Permission: "create_post",
+			Team:       "myteam",
+			Channel:    channelName,
+			Roles: []*permissionCheckRole{
+				{Role: "system_user", Scope: "system", Source: "user roles"},
+				{Role: "support_team_user", Scope: "team", Source: "team scheme support"},
+				{Role: "support_channel_user", Scope: "channel", Source: "team scheme support"},
+				{Role: "custom_role", Scope: "channel", Source: "explicit role"},
+			},
+		}, printer.GetLines()[0])
+		s.Require().Len(printer.GetWarningLines(), 1)
+		s.Require().Equal("Unable to find role 'custom_role'", printer.GetWarningLines()[0])
+	})
+
+	s.Run("should fail if the user is not a member of the team", func() {
+		printer.Clean()
+		expectUser()
+		s.client.
+			EXPECT().
+			GetTeam("myteam", "").
+			Return(nil, notFound).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetTeamByName("myteam", "").
+			Return(&model.Team{Id: teamID, Name: "myteam"}, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetTeamMember(teamID, user.Id, "").
+			Return(nil, notFound).
+			Times(1)
+
+		err := checkPermissionsCmdF(s.client, newCmd("myteam", ""), []string{user.Username, "create_public_channel"})
+		s.Require().EqualError(err, "user john.doe is not a member of team myteam")
+	})
+
+	s.Run("should fail with an unknown permission", func() {
+		printer.Clean()
+		expectUser()
+		s.client.
+			EXPECT().
+			GetRolesByNames(gomock.Any()).
+			Times(0)
+
+		err := checkPermissionsCmdF(s.client, newCmd("", ""), []string{user.Username, "fly"})
+		s.Require().EqualError(err, `unknown permission "fly"`)
+	})
+}
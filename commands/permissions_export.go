@@ -0,0 +1,462 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/client"
+	"github.com/mattermost/mmctl/printer"
+)
+
+var ExportPermissionsCmd = &cobra.Command{
+	Use:   "export <file>",
+	Short: "Export the permissions of every role (EE Only)",
+	Long: `Export the permissions of the default roles and of the roles of every scheme to a file, so they can be compared with or imported into another server.
+The roles of the schemes are identified by the name of the scheme and their slot in it, such as team_admin or channel_user, so they are found in the servers with schemes of the same name (Only works in Enterprise Edition).`,
+	Example: `  # export the roles to a file
+  $ mmctl permissions export roles.json
+
+  # export the roles to the standard output
+  $ mmctl permissions export -`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(exportPermissionsCmdF),
+}
+
+var DiffPermissionsCmd = &cobra.Command{
+	Use:     "diff <file>",
+	Short:   "Show the permission changes of an export file (EE Only)",
+	Long:    "Show the permissions that would be added to and removed from each role if the export file was imported (Only works in Enterprise Edition).",
+	Example: `  $ mmctl permissions diff roles.json`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(diffPermissionsCmdF),
+}
+
+var ImportPermissionsCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import the permissions of an export file (EE Only)",
+	Long: `Update the permissions of the roles to match the ones of an export file. The changes are shown and confirmed before being applied.
+Roles present in the server but missing from the file are not modified (Only works in Enterprise Edition).`,
+	Example: `  # import the roles asking for confirmation after showing the changes
+  $ mmctl permissions import roles.json
+
+  # import the roles read from the standard input without confirmation
+  $ cat roles.json | mmctl permissions import - --confirm`,
+	Args: cobra.ExactArgs(1),
+	RunE: withClient(importPermissionsCmdF),
+}
+
+func init() {
+	ImportPermissionsCmd.Flags().Bool("confirm", false, "Confirm you really want to import the permissions without being prompted")
+
+	PermissionsCmd.AddCommand(
+		ExportPermissionsCmd,
+		DiffPermissionsCmd,
+		ImportPermissionsCmd,
+	)
+}
+
+// PermissionsExport is the content of the file written by the
+// permissions export command
+type PermissionsExport struct {
+	Roles []*RolePermissions `json:"roles"`
+}
+
+// RolePermissions is the exported definition of a role. The names of
+// the roles of the schemes are generated by each server, so they are
+// identified by the name of their scheme and their slot in it instead,
+// such as team_admin or channel_user
+type RolePermissions struct {
+	Name        string   `json:"name"`
+	Scheme      string   `json:"scheme,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+// label returns the name of the role shown in the output
+func (r *RolePermissions) label() string {
+	if r.Scheme == "" {
+		return r.Name
+	}
+	return r.Name + " of scheme " + r.Scheme
+}
+
+// schemeRoleSlots are the slots of the roles of a scheme
+var schemeRoleSlots = []string{"team_admin", "team_user", "team_guest", "channel_admin", "channel_user", "channel_guest"}
+
+// getSchemeRolesBySlot returns the names of the roles of a scheme by
+// their slot. The channel schemes have no team roles
+func getSchemeRolesBySlot(scheme *model.Scheme) map[string]string {
+	roles := map[string]string{
+		"team_admin":    scheme.DefaultTeamAdminRole,
+		"team_user":     scheme.DefaultTeamUserRole,
+		"team_guest":    scheme.DefaultTeamGuestRole,
+		"channel_admin": scheme.DefaultChannelAdminRole,
+		"channel_user":  scheme.DefaultChannelUserRole,
+		"channel_guest": scheme.DefaultChannelGuestRole,
+	}
+	for slot, name := range roles {
+		if name == "" {
+			delete(roles, slot)
+		}
+	}
+	return roles
+}
+
+// rolePermissionsDiff contains the permissions that need to change for
+// a role of the server to match its exported definition
+type rolePermissionsDiff struct {
+	Role    string   `json:"role"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+
+	id          string
+	permissions []string
+}
+
+const rolePermissionsDiffTemplate = `{{.Role}}:{{range .Added}}
+  + {{.}}{{end}}{{range .Removed}}
+  - {{.}}{{end}}`
+
+func getAllSchemes(c client.Client) ([]*model.Scheme, error) {
+	all := []*model.Scheme{}
+	for page := 0; ; page++ {
+		schemes, response := c.GetSchemes("", page, APILimitMaximum)
+		if response.Error != nil {
+			return nil, errors.Wrap(response.Error, "failed to list schemes")
+		}
+		all = append(all, schemes...)
+
+		if len(schemes) < APILimitMaximum {
+			return all, nil
+		}
+	}
+}
+
+// getExportedRoles returns the definition of the default roles and of
+// the roles used by the schemes of the server, without permissions, by
+// the name of the role in the server
+func getExportedRoles(c client.Client) (map[string]*RolePermissions, error) {
+	roles := map[string]*RolePermissions{}
+	for name := range model.MakeDefaultRoles() {
+		roles[name] = &RolePermissions{Name: name}
+	}
+
+	schemes, err := getAllSchemes(c)
+	if err != nil {
+		return nil, err
+	}
+	for _, scheme := range schemes {
+		for slot, name := range getSchemeRolesBySlot(scheme) {
+			roles[name] = &RolePermissions{Name: slot, Scheme: scheme.Name}
+		}
+	}
+
+	return roles, nil
+}
+
+func readPermissionsExport(path string) (*PermissionsExport, error) {
+	var b []byte
+	var err error
+	if path == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read permissions file")
+	}
+
+	var export PermissionsExport
+	if err := json.Unmarshal(b, &export); err != nil {
+		return nil, errors.Wrap(err, "could not parse permissions file")
+	}
+
+	for i, role := range export.Roles {
+		if role.Name == "" {
+			return nil, errors.Errorf("role at position %d has no name", i)
+		}
+		if role.Scheme != "" && !isValidSchemeRoleSlot(role.Name) {
+			return nil, errors.Errorf("role at position %d of scheme %s has an invalid name %q, the valid ones are %s", i, role.Scheme, role.Name, strings.Join(schemeRoleSlots, ", "))
+		}
+	}
+
+	return &export, nil
+}
+
+func isValidSchemeRoleSlot(slot string) bool {
+	for _, valid := range schemeRoleSlots {
+		if slot == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveRoleNames returns the names in the server of the exported
+// roles, looking for the roles of the schemes by the name of their
+// scheme. The roles that can't be found are reported and left out
+func resolveRoleNames(c client.Client, export *PermissionsExport) (map[*RolePermissions]string, error) {
+	hasSchemeRoles := false
+	for _, exported := range export.Roles {
+		if exported.Scheme != "" {
+			hasSchemeRoles = true
+			break
+		}
+	}
+
+	schemesByName := map[string]*model.Scheme{}
+	if hasSchemeRoles {
+		schemes, err := getAllSchemes(c)
+		if err != nil {
+			return nil, err
+		}
+		for _, scheme := range schemes {
+			schemesByName[scheme.Name] = scheme
+		}
+	}
+
+	names := map[*RolePermissions]string{}
+	for _, exported := range export.Roles {
+		if exported.Scheme == "" {
+			names[exported] = exported.Name
+			continue
+		}
+
+		scheme, ok := schemesByName[exported.Scheme]
+		if !ok {
+			printItemError("Unable to find scheme '"+exported.Scheme+"'", ErrEntityNotFound{Type: "scheme", ID: exported.Scheme})
+			continue
+		}
+		name, ok := getSchemeRolesBySlot(scheme)[exported.Name]
+		if !ok {
+			printItemError("Unable to find role '"+exported.label()+"'", ErrEntityNotFound{Type: "role", ID: exported.label()})
+			continue
+		}
+		names[exported] = name
+	}
+	return names, nil
+}
+
+// uniquePermissions returns the sorted permissions without duplicates
+func uniquePermissions(permissions []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, permission := range permissions {
+		if !seen[permission] {
+			seen[permission] = true
+			unique = append(unique, permission)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// permissionsDifference returns the permissions of a that are not in b
+func permissionsDifference(a, b []string) []string {
+	inB := map[string]bool{}
+	for _, permission := range b {
+		inB[permission] = true
+	}
+
+	difference := []string{}
+	for _, permission := range a {
+		if !inB[permission] {
+			difference = append(difference, permission)
+		}
+	}
+	return uniquePermissions(difference)
+}
+
+// diffRolePermissions compares the exported roles with the ones of the
+// server and returns the changes needed for the roles that differ.
+// Permissions are added and removed the same way the add and remove
+// commands do, so the ancillary permissions of the sysconsole ones are
+// kept in sync
+func diffRolePermissions(c client.Client, export *PermissionsExport) ([]*rolePermissionsDiff, error) {
+	if len(export.Roles) == 0 {
+		return nil, nil
+	}
+
+	roleNames, err := resolveRoleNames(c, export)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, exported := range export.Roles {
+		if name, ok := roleNames[exported]; ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	roles, response := c.GetRolesByNames(names)
+	if response.Error != nil {
+		return nil, errors.Wrap(response.Error, "failed to get roles")
+	}
+
+	rolesByName := map[string]*model.Role{}
+	for _, role := range roles {
+		rolesByName[role.Name] = role
+	}
+
+	diffs := []*rolePermissionsDiff{}
+	for _, exported := range export.Roles {
+		name, ok := roleNames[exported]
+		if !ok {
+			continue
+		}
+		role, ok := rolesByName[name]
+		if !ok {
+			printItemError("Unable to find role '"+exported.label()+"'", ErrEntityNotFound{Type: "role", ID: exported.label()})
+			continue
+		}
+
+		newPermissions := removePermissions(role.Permissions, permissionsDifference(role.Permissions, exported.Permissions))
+		newPermissions = uniquePermissions(addPermissions(newPermissions, permissionsDifference(exported.Permissions, newPermissions)))
+
+		diff := &rolePermissionsDiff{
+			Role:        exported.label(),
+			Added:       permissionsDifference(newPermissions, role.Permissions),
+			Removed:     permissionsDifference(role.Permissions, newPermissions),
+			id:          role.Id,
+			permissions: newPermissions,
+		}
+		if len(diff.Added) > 0 || len(diff.Removed) > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	return diffs, nil
+}
+
+func exportPermissionsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	exportedRoles, err := getExportedRoles(c)
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	for name := range exportedRoles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	roles, response := c.GetRolesByNames(names)
+	if response.Error != nil {
+		return errors.Wrap(response.Error, "failed to get roles")
+	}
+
+	export := &PermissionsExport{Roles: []*RolePermissions{}}
+	for _, role := range roles {
+		exported, ok := exportedRoles[role.Name]
+		if !ok {
+			continue
+		}
+		exported.Permissions = uniquePermissions(role.Permissions)
+		export.Roles = append(export.Roles, exported)
+	}
+	// the default roles go first, followed by the roles of each scheme
+	sort.Slice(export.Roles, func(i, j int) bool {
+		if export.Roles[i].Scheme != export.Roles[j].Scheme {
+			return export.Roles[i].Scheme < export.Roles[j].Scheme
+		}
+		return export.Roles[i].Name < export.Roles[j].Name
+	})
+
+	b, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal roles")
+	}
+
+	if args[0] == "-" {
+		fmt.Println(string(b))
+		return nil
+	}
+
+	if err := ioutil.WriteFile(args[0], b, 0600); err != nil {
+		return errors.Wrap(err, "failed to write permissions file")
+	}
+
+	printer.Print(fmt.Sprintf("Exported %d roles to %s", len(export.Roles), args[0]))
+	return nil
+}
+
+func diffPermissionsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	export, err := readPermissionsExport(args[0])
+	if err != nil {
+		return err
+	}
+
+	diffs, err := diffRolePermissions(c, export)
+	if err != nil {
+		return err
+	}
+
+	if len(diffs) == 0 {
+		printer.Print("The permissions of the roles already match the file")
+		return nil
+	}
+
+	for _, diff := range diffs {
+		printer.PrintT(rolePermissionsDiffTemplate, diff)
+	}
+
+	return nil
+}
+
+func importPermissionsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+
+	export, err := readPermissionsExport(args[0])
+	if err != nil {
+		return err
+	}
+
+	diffs, err := diffRolePermissions(c, export)
+	if err != nil {
+		return err
+	}
+
+	if len(diffs) == 0 {
+		printer.Print("The permissions of the roles already match the file")
+		return nil
+	}
+
+	for _, diff := range diffs {
+		printer.PrintT(rolePermissionsDiffTemplate, diff)
+	}
+
+	if !confirmFlag {
+		var confirm string
+		fmt.Printf("Do you want to update the permissions of %d roles? (YES/NO): ", len(diffs))
+		fmt.Scanln(&confirm)
+		if confirm != "YES" {
+			return errors.New("aborted: You did not answer YES exactly, in all capitals")
+		}
+	}
+
+	for _, diff := range diffs {
+		permissions := diff.permissions
+		role, response := c.PatchRole(diff.id, &model.RolePatch{Permissions: &permissions})
+		if response.Error != nil {
+			printItemError("Unable to update role '"+diff.Role+"'. Error: "+response.Error.Error(), response.Error)
+			continue
+		}
+		printer.PrintT(prettyRole(role), nil)
+	}
+
+	return nil
+}
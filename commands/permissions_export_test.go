@@ -0,0 +1,243 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+func writePermissionsExport(s *MmctlUnitTestSuite, dir string, export *PermissionsExport) string {
+	b, err := json.Marshal(export)
+	s.Require().NoError(err)
+	path := filepath.Join(dir, "roles.json")
+	s.Require().NoError(ioutil.WriteFile(path, b, 0600))
+	return path
+}
+
+func (s *MmctlUnitTestSuite) TestExportPermissionsCmd() {
+	s.Run("should export the default and the scheme roles", func() {
+		printer.Clean()
+		dir, err := ioutil.TempDir("", "mmctl-permissions")
+		s.Require().NoError(err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "roles.json")
+
+		scheme := &model.Scheme{
+			Id:                      model.NewId(),
+			Name:                    "support",
+			Scope:                   model.SCHEME_SCOPE_CHANNEL,
+			DefaultChannelAdminRole: "scheme_channel_admin",
+			DefaultChannelUserRole:  "scheme_channel_user",
+			DefaultChannelGuestRole: "scheme_channel_guest",
+		}
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return([]*model.Scheme{scheme}, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetRolesByNames(gomock.Any()).
+			DoAndReturn(func(names []string) ([]*model.Role, *model.Response) {
+				s.Require().Len(names, len(model.MakeDefaultRoles())+3)
+				s.Require().Contains(names, "scheme_channel_user")
+				return []*model.Role{
+					{Name: "system_user", Permissions: []string{"list_open_teams", "create_team", "create_team"}},
+					{Name: "scheme_channel_user", Permissions: []string{"create_post"}, SchemeManaged: true},
+				}, &model.Response{}
+			}).
+			Times(1)
+
+		err = exportPermissionsCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal("Exported 2 roles to "+path, printer.GetLines()[0])
+
+		b, err := ioutil.ReadFile(path)
+		s.Require().NoError(err)
+		var export PermissionsExport
+		s.Require().NoError(json.Unmarshal(b, &export))
+		s.Require().Equal(&PermissionsExport{Roles: []*RolePermissions{
+			{Name: "system_user", Permissions: []string{"create_team", "list_open_teams"}},
+			{Name: "channel_user", Scheme: "support", Permissions: []string{"create_post"}},
+		}}, &export)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestDiffPermissionsCmd() {
+	dir, err := ioutil.TempDir("", "mmctl-permissions")
+	s.Require().NoError(err)
+	defer os.RemoveAll(dir)
+
+	s.Run("should show the added and removed permissions of each role", func() {
+		printer.Clean()
+		path := writePermissionsExport(s, dir, &PermissionsExport{Roles: []*RolePermissions{
+			{Name: "system_user", Permissions: []string{"create_team", "list_open_teams"}},
+			{Name: "system_manager", Permissions: []string{"sysconsole_read_user_management_channels"}},
+			{Name: "team_user", Permissions: []string{"view_team"}},
+			{Name: "unknown_role", Permissions: []string{}},
+		}})
+
+		s.client.
+			EXPECT().
+			GetRolesByNames([]string{"system_user", "system_manager", "team_user", "unknown_role"}).
+			Return([]*model.Role{
+				{Id: "system-user-id", Name: "system_user", Permissions: []string{"create_team", "create_emojis"}},
+				{Id: "system-manager-id", Name: "system_manager", Permissions: []string{}},
+				{Id: "team-user-id", Name: "team_user", Permissions: []string{"view_team"}},
+			}, &model.Response{}).
+			Times(1)
+
+		err := diffPermissionsCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(&rolePermissionsDiff{
+			Role:        "system_user",
+			Added:       []string{"list_open_teams"},
+			Removed:     []string{"create_emojis"},
+			id:          "system-user-id",
+			permissions: []string{"create_team", "list_open_teams"},
+		}, printer.GetLines()[0])
+
+		// the ancillary permissions of the sysconsole ones are added too
+		managerDiff := printer.GetLines()[1].(*rolePermissionsDiff)
+		s.Require().Equal([]string{
+			"read_channel",
+			"read_private_channel_groups",
+			"read_public_channel",
+			"read_public_channel_groups",
+			"sysconsole_read_user_management_channels",
+		}, managerDiff.Added)
+		s.Require().Empty(managerDiff.Removed)
+
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().Equal("Unable to find role 'unknown_role'", printer.GetErrorLines()[0])
+	})
+
+	s.Run("should find the roles of the schemes by the name of their scheme", func() {
+		printer.Clean()
+		path := writePermissionsExport(s, dir, &PermissionsExport{Roles: []*RolePermissions{
+			{Name: "channel_user", Scheme: "support", Permissions: []string{"create_post"}},
+			{Name: "team_admin", Scheme: "support", Permissions: []string{}},
+			{Name: "channel_user", Scheme: "unknown_scheme", Permissions: []string{}},
+		}})
+
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return([]*model.Scheme{{
+				Id:                      model.NewId(),
+				Name:                    "support",
+				Scope:                   model.SCHEME_SCOPE_CHANNEL,
+				DefaultChannelAdminRole: "generated_channel_admin",
+				DefaultChannelUserRole:  "generated_channel_user",
+				DefaultChannelGuestRole: "generated_channel_guest",
+			}}, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetRolesByNames([]string{"generated_channel_user"}).
+			Return([]*model.Role{
+				{Id: "channel-user-id", Name: "generated_channel_user", Permissions: []string{"read_channel"}},
+			}, &model.Response{}).
+			Times(1)
+
+		err := diffPermissionsCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().NoError(err)
+		s.Require().Equal([]interface{}{&rolePermissionsDiff{
+			Role:        "channel_user of scheme support",
+			Added:       []string{"create_post"},
+			Removed:     []string{"read_channel"},
+			id:          "channel-user-id",
+			permissions: []string{"create_post"},
+		}}, printer.GetLines())
+		s.Require().Equal([]interface{}{
+			"Unable to find role 'team_admin of scheme support'",
+			"Unable to find scheme 'unknown_scheme'",
+		}, printer.GetErrorLines())
+	})
+
+	s.Run("should report when the roles already match", func() {
+		printer.Clean()
+		path := writePermissionsExport(s, dir, &PermissionsExport{Roles: []*RolePermissions{
+			{Name: "team_user", Permissions: []string{"view_team"}},
+		}})
+
+		s.client.
+			EXPECT().
+			GetRolesByNames([]string{"team_user"}).
+			Return([]*model.Role{{Id: "team-user-id", Name: "team_user", Permissions: []string{"view_team"}}}, &model.Response{}).
+			Times(1)
+
+		err := diffPermissionsCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal("The permissions of the roles already match the file", printer.GetLines()[0])
+	})
+
+	s.Run("should fail with an invalid file", func() {
+		printer.Clean()
+		path := filepath.Join(dir, "invalid.json")
+		s.Require().NoError(ioutil.WriteFile(path, []byte(`{"roles": [{"permissions": []}]}`), 0600))
+
+		err := diffPermissionsCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().EqualError(err, "role at position 0 has no name")
+	})
+
+	s.Run("should fail with an invalid role of a scheme", func() {
+		printer.Clean()
+		path := writePermissionsExport(s, dir, &PermissionsExport{Roles: []*RolePermissions{
+			{Name: "scheme_channel_user", Scheme: "support", Permissions: []string{}},
+		}})
+
+		err := diffPermissionsCmdF(s.client, &cobra.Command{}, []string{path})
+		s.Require().EqualError(err, `role at position 0 of scheme support has an invalid name "scheme_channel_user", the valid ones are team_admin, team_user, team_guest, channel_admin, channel_user, channel_guest`)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestImportPermissionsCmd() {
+	s.Run("should patch the roles that differ", func() {
+		printer.Clean()
+		dir, err := ioutil.TempDir("", "mmctl-permissions")
+		s.Require().NoError(err)
+		defer os.RemoveAll(dir)
+		path := writePermissionsExport(s, dir, &PermissionsExport{Roles: []*RolePermissions{
+			{Name: "system_user", Permissions: []string{"create_team", "list_open_teams"}},
+			{Name: "team_user", Permissions: []string{"view_team"}},
+		}})
+
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("confirm", true, "")
+
+		s.client.
+			EXPECT().
+			GetRolesByNames([]string{"system_user", "team_user"}).
+			Return([]*model.Role{
+				{Id: "system-user-id", Name: "system_user", Permissions: []string{"create_team", "create_emojis"}},
+				{Id: "team-user-id", Name: "team_user", Permissions: []string{"view_team"}},
+			}, &model.Response{}).
+			Times(1)
+
+		expectedPermissions := []string{"create_team", "list_open_teams"}
+		s.client.
+			EXPECT().
+			PatchRole("system-user-id", &model.RolePatch{Permissions: &expectedPermissions}).
+			Return(&model.Role{Id: "system-user-id", Name: "system_user", Permissions: expectedPermissions}, &model.Response{}).
+			Times(1)
+
+		err = importPermissionsCmdF(s.client, cmd, []string{path})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Empty(printer.GetErrorLines())
+	})
+}
@@ -164,7 +164,7 @@ func assignUsersCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 
 	for i, user := range users {
 		if user == nil {
-			printer.PrintError("Couldn't find user '" + args[i+1] + "'.")
+			printItemError("Couldn't find user '"+args[i+1]+"'.", ErrEntityNotFound{Type: "user", ID: args[i+1]})
 			continue
 		}
 
@@ -196,7 +196,7 @@ func unassignUsersCmdF(c client.Client, cmd *cobra.Command, args []string) error
 
 	for i, user := range users {
 		if user == nil {
-			printer.PrintError("Couldn't find user '" + args[i+1] + "'.")
+			printItemError("Couldn't find user '"+args[i+1]+"'.", ErrEntityNotFound{Type: "user", ID: args[i+1]})
 			continue
 		}
 
@@ -97,7 +97,7 @@ func pluginAddCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 		}
 
 		if _, response := c.UploadPlugin(fileReader); response.Error != nil {
-			printer.PrintError("Unable to add plugin: " + args[i] + ". Error: " + response.Error.Error())
+			printItemError("Unable to add plugin: "+args[i]+". Error: "+response.Error.Error(), response.Error)
 		} else {
 			printer.Print("Added plugin: " + plugin)
 		}
@@ -113,7 +113,7 @@ func pluginInstallURLCmdF(c client.Client, cmd *cobra.Command, args []string) er
 	for _, plugin := range args {
 		manifest, resp := c.InstallPluginFromUrl(plugin, force)
 		if resp.Error != nil {
-			printer.PrintError("Unable to install plugin from URL \"" + plugin + "\". Error: " + resp.Error.Error())
+			printItemError("Unable to install plugin from URL \""+plugin+"\". Error: "+resp.Error.Error(), resp.Error)
 		} else {
 			printer.PrintT("Plugin {{.Name}} successfully installed", manifest)
 		}
@@ -125,7 +125,7 @@ func pluginInstallURLCmdF(c client.Client, cmd *cobra.Command, args []string) er
 func pluginDeleteCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	for _, plugin := range args {
 		if _, response := c.RemovePlugin(plugin); response.Error != nil {
-			printer.PrintError("Unable to delete plugin: " + plugin + ". Error: " + response.Error.Error())
+			printItemError("Unable to delete plugin: "+plugin+". Error: "+response.Error.Error(), response.Error)
 		} else {
 			printer.Print("Deleted plugin: " + plugin)
 		}
@@ -137,7 +137,7 @@ func pluginDeleteCmdF(c client.Client, cmd *cobra.Command, args []string) error
 func pluginEnableCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	for _, plugin := range args {
 		if _, response := c.EnablePlugin(plugin); response.Error != nil {
-			printer.PrintError("Unable to enable plugin: " + plugin + ". Error: " + response.Error.Error())
+			printItemError("Unable to enable plugin: "+plugin+". Error: "+response.Error.Error(), response.Error)
 		} else {
 			printer.Print("Enabled plugin: " + plugin)
 		}
@@ -149,7 +149,7 @@ func pluginEnableCmdF(c client.Client, cmd *cobra.Command, args []string) error
 func pluginDisableCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	for _, plugin := range args {
 		if _, response := c.DisablePlugin(plugin); response.Error != nil {
-			printer.PrintError("Unable to disable plugin: " + plugin + ". Error: " + response.Error.Error())
+			printItemError("Unable to disable plugin: "+plugin+". Error: "+response.Error.Error(), response.Error)
 		} else {
 			printer.Print("Disabled plugin: " + plugin)
 		}
@@ -165,7 +165,7 @@ func pluginListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	}
 
 	format, _ := cmd.Flags().GetString("format")
-	if format == printer.FormatJSON {
+	if format == printer.FormatJSON || format == printer.FormatNDJSON || format == printer.FormatYAML {
 		printer.Print(pluginsResp)
 	} else {
 		printer.Print("Listing enabled plugins")
@@ -157,11 +157,6 @@ func postListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		appErr := ws.Connect()
-		if appErr != nil {
-			return errors.New(appErr.Error())
-		}
-
 		ws.Listen()
 		for {
 			event := <-ws.EventChannel
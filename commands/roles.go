@@ -28,9 +28,12 @@ var RolesSystemAdminCmd = &cobra.Command{
   $ mmctl roles system_admin john_doe
 
   # Or promote multiple users at the same time
-  $ mmctl roles system_admin john_doe jane_doe`,
+  $ mmctl roles system_admin john_doe jane_doe
+
+  # Or promote the users listed in the "email" or "username" column of a CSV file
+  $ mmctl roles system_admin --from-file admins.csv`,
 	RunE: withClient(rolesSystemAdminCmdF),
-	Args: cobra.MinimumNArgs(1),
+	Args: argsFromFile(cobra.MinimumNArgs(1)),
 }
 
 var RolesMemberCmd = &cobra.Command{
@@ -47,6 +50,8 @@ var RolesMemberCmd = &cobra.Command{
 }
 
 func init() {
+	addFromFileFlags(RolesSystemAdminCmd)
+
 	RolesCmd.AddCommand(
 		RolesSystemAdminCmd,
 		RolesMemberCmd,
@@ -55,11 +60,15 @@ func init() {
 	RootCmd.AddCommand(RolesCmd)
 }
 
-func rolesSystemAdminCmdF(c client.Client, _ *cobra.Command, args []string) error {
+func rolesSystemAdminCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	if path, _ := cmd.Flags().GetString("from-file"); path != "" {
+		return runFromFile(c, cmd, args, [][]string{userColumns}, rolesSystemAdminCmdF)
+	}
+
 	users := getUsersFromUserArgs(c, args)
 	for i, user := range users {
 		if user == nil {
-			printer.PrintError(fmt.Sprintf("unable to find user %q", args[i]))
+			printItemError(fmt.Sprintf("unable to find user %q", args[i]), ErrEntityNotFound{Type: "user", ID: args[i]})
 			continue
 		}
 
@@ -74,7 +83,7 @@ func rolesSystemAdminCmdF(c client.Client, _ *cobra.Command, args []string) erro
 		if !systemAdmin {
 			roles = append(roles, model.SYSTEM_ADMIN_ROLE_ID)
 			if _, resp := c.UpdateUserRoles(user.Id, strings.Join(roles, " ")); resp.Error != nil {
-				printer.PrintError(fmt.Sprintf("can't update roles for user %q: %s", args[i], resp.Error))
+				printItemError(fmt.Sprintf("can't update roles for user %q: %s", args[i], resp.Error), resp.Error)
 				continue
 			}
 
@@ -89,7 +98,7 @@ func rolesMemberCmdF(c client.Client, _ *cobra.Command, args []string) error {
 	users := getUsersFromUserArgs(c, args)
 	for i, user := range users {
 		if user == nil {
-			printer.PrintError(fmt.Sprintf("unable to find user %q", args[i]))
+			printItemError(fmt.Sprintf("unable to find user %q", args[i]), ErrEntityNotFound{Type: "user", ID: args[i]})
 			continue
 		}
 
@@ -108,7 +117,7 @@ func rolesMemberCmdF(c client.Client, _ *cobra.Command, args []string) error {
 
 		if shouldRemoveSysadmin {
 			if _, resp := c.UpdateUserRoles(user.Id, strings.Join(newRoles, " ")); resp.Error != nil {
-				printer.PrintError(fmt.Sprintf("can't update roles for user %q: %s", args[i], resp.Error))
+				printItemError(fmt.Sprintf("can't update roles for user %q: %s", args[i], resp.Error), resp.Error)
 				continue
 			}
 
@@ -10,33 +10,107 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/mattermost/mattermost-server/v5/model"
-
 	"github.com/mattermost/mmctl/printer"
 )
 
 func Run(args []string) error {
 	viper.SetEnvPrefix("mmctl")
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
-	viper.SetDefault("local-socket-path", model.LOCAL_MODE_SOCKET_PATH)
+	for key, value := range baseDefaults {
+		viper.SetDefault(key, value)
+	}
 	viper.AutomaticEnv()
 
 	RootCmd.PersistentFlags().String("config-path", xdgConfigHomeVar, fmt.Sprintf("path to the configuration directory. If \"%s/.%s\" exists it will take precedence over the default value", userHomeVar, configFileName))
 	_ = viper.BindPFlag("config-path", RootCmd.PersistentFlags().Lookup("config-path"))
-	RootCmd.PersistentFlags().String("format", "plain", "the format of the command output [plain, json]")
+	RootCmd.PersistentFlags().String("credentials-store", "", fmt.Sprintf("the store of the credentials [%s]. If not set, the store that contains credentials is used, and the file one if none of them does", strings.Join(credentialsStores, ", ")))
+	_ = viper.BindPFlag("credentials-store", RootCmd.PersistentFlags().Lookup("credentials-store"))
+	RootCmd.PersistentFlags().String("credentials-key-file", "", "the file whose contents are used as the key of the encrypted-file credentials store. If not set, the passphrase is read from the MMCTL_CREDENTIALS_PASSPHRASE environment variable or asked for")
+	_ = viper.BindPFlag("credentials-key-file", RootCmd.PersistentFlags().Lookup("credentials-key-file"))
+	RootCmd.PersistentFlags().String("format", "plain", "the format of the command output [plain, json, ndjson, table, csv, tsv, yaml]")
 	_ = viper.BindPFlag("format", RootCmd.PersistentFlags().Lookup("format"))
+	RootCmd.PersistentFlags().StringSlice("columns", []string{}, "the fields to show for each element when using the table, csv, tsv or yaml formats. Nested fields can be selected with dots, e.g. \"notify_props.email\"")
+	_ = viper.BindPFlag("columns", RootCmd.PersistentFlags().Lookup("columns"))
+	RootCmd.PersistentFlags().String("query", "", "a JSONPath expression, e.g. \"$.id\", to select the values to print from each element of the command output")
+	_ = viper.BindPFlag("query", RootCmd.PersistentFlags().Lookup("query"))
+	RootCmd.PersistentFlags().String("template", "", "a Go template, e.g. \"{{.Username}}\", to render each element of the command output")
+	_ = viper.BindPFlag("template", RootCmd.PersistentFlags().Lookup("template"))
 	RootCmd.PersistentFlags().Bool("strict", false, "will only run commands if the mmctl version matches the server one")
 	_ = viper.BindPFlag("strict", RootCmd.PersistentFlags().Lookup("strict"))
+	RootCmd.PersistentFlags().Bool("dry-run", false, "prints the requests that would modify the server instead of sending them")
+	_ = viper.BindPFlag("dry-run", RootCmd.PersistentFlags().Lookup("dry-run"))
 	RootCmd.PersistentFlags().Bool("insecure-sha1-intermediate", false, "allows to use insecure TLS protocols, such as SHA-1")
 	_ = viper.BindPFlag("insecure-sha1-intermediate", RootCmd.PersistentFlags().Lookup("insecure-sha1-intermediate"))
 	RootCmd.PersistentFlags().Bool("insecure-tls-version", false, "allows to use TLS versions 1.0 and 1.1")
 	_ = viper.BindPFlag("insecure-tls-version", RootCmd.PersistentFlags().Lookup("insecure-tls-version"))
+	RootCmd.PersistentFlags().String("ca-cert", "", "the file with the PEM encoded CA certificates to trust, along with the ones of the system, when connecting to the server")
+	_ = viper.BindPFlag("ca-cert", RootCmd.PersistentFlags().Lookup("ca-cert"))
+	RootCmd.PersistentFlags().String("client-cert", "", "the file with the PEM encoded client certificate to authenticate with in the TLS connections. Requires --client-key")
+	_ = viper.BindPFlag("client-cert", RootCmd.PersistentFlags().Lookup("client-cert"))
+	RootCmd.PersistentFlags().String("client-key", "", "the file with the PEM encoded private key of the client certificate")
+	_ = viper.BindPFlag("client-key", RootCmd.PersistentFlags().Lookup("client-key"))
+	RootCmd.PersistentFlags().String("proxy", "", "the URL of the proxy to connect to the server through, e.g. \"http://proxy.example.com:3128\". If not set, the HTTP_PROXY and HTTPS_PROXY environment variables are used")
+	_ = viper.BindPFlag("proxy", RootCmd.PersistentFlags().Lookup("proxy"))
 	RootCmd.PersistentFlags().Bool("local", false, "allows communicating with the server through a unix socket")
 	_ = viper.BindPFlag("local", RootCmd.PersistentFlags().Lookup("local"))
+	RootCmd.PersistentFlags().StringSlice("contexts", []string{}, "the names of the saved credentials to run the command against, one after another, instead of the current ones")
+	_ = viper.BindPFlag("contexts", RootCmd.PersistentFlags().Lookup("contexts"))
+	RootCmd.PersistentFlags().Bool("all-contexts", false, "runs the command against every saved credentials, one after another")
+	_ = viper.BindPFlag("all-contexts", RootCmd.PersistentFlags().Lookup("all-contexts"))
+	RootCmd.PersistentFlags().Int("max-retries", 3, "the number of times a request is retried if the server is rate limiting or temporarily unavailable")
+	_ = viper.BindPFlag("max-retries", RootCmd.PersistentFlags().Lookup("max-retries"))
+	RootCmd.PersistentFlags().Duration("timeout", 0, "the time limit for each attempt of a request to get a response from the server, e.g. \"30s\". Reading the response, like a download, is not limited. A value of zero means no timeout")
+	_ = viper.BindPFlag("timeout", RootCmd.PersistentFlags().Lookup("timeout"))
+	RootCmd.PersistentFlags().Bool("trace", false, "prints the requests sent to the server and their responses to stderr, with the credentials and the secrets redacted")
+	_ = viper.BindPFlag("trace", RootCmd.PersistentFlags().Lookup("trace"))
+	RootCmd.PersistentFlags().String("trace-file", "", "writes the trace of the requests to a file instead of stderr. If the file name ends in .har, the trace is written in HAR format")
+	_ = viper.BindPFlag("trace-file", RootCmd.PersistentFlags().Lookup("trace-file"))
+	RootCmd.PersistentFlags().Bool("verbose-requests", false, "prints additional information about the requests sent to the server, like the retries")
+	_ = viper.BindPFlag("verbose-requests", RootCmd.PersistentFlags().Lookup("verbose-requests"))
 
 	RootCmd.SetArgs(args)
+	RootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &UsageError{Msg: err.Error()}
+	})
+
+	err := RootCmd.Execute()
+	// the trace is written even if the command fails, as that's when
+	// it is most needed
+	if traceErr := stopTrace(); err == nil {
+		err = traceErr
+	}
+	if err != nil {
+		if isJSONFormat(printer.GetFormat()) {
+			e, _ := classifyError(err)
+			printer.PrintErrorObject(err.Error(), e)
+		}
+		return err
+	}
+
+	// most commands print the errors of the items that failed and
+	// continue with the rest, so the command succeeding doesn't mean
+	// that every item was processed
+	if failed := len(printer.GetErrorLines()); failed > 0 {
+		return &PartialFailureError{Failed: failed}
+	}
+
+	return nil
+}
+
+// applyFormat sets the format of the command output
+func applyFormat(cmd *cobra.Command) {
+	format := viper.GetString("format")
+	printer.SetFormat(format)
+	if isJSONFormat(format) {
+		// the error is printed as an object once the command
+		// finishes
+		cmd.Root().SilenceErrors = true
+		cmd.Root().SilenceUsage = true
+	}
+}
 
-	return RootCmd.Execute()
+func isJSONFormat(format string) bool {
+	return format == printer.FormatJSON || format == printer.FormatNDJSON
 }
 
 var RootCmd = &cobra.Command{
@@ -44,9 +118,19 @@ var RootCmd = &cobra.Command{
 	Short:             "Remote client for the Open Source, self-hosted Slack-alternative",
 	Long:              `Mattermost offers workplace messaging across web, PC and phones with archiving, search and integration with your existing systems. Documentation available at https://docs.mattermost.com`,
 	DisableAutoGenTag: true,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		format := viper.GetString("format")
-		printer.SetFormat(format)
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		applyFormat(cmd)
+		if _, err := applyDefaultTeam(cmd); err != nil {
+			return err
+		}
+		if err := startTrace(); err != nil {
+			return err
+		}
+		printer.SetColumns(viper.GetStringSlice("columns"))
+		if err := printer.SetQuery(viper.GetString("query")); err != nil {
+			return err
+		}
+		return printer.SetTemplate(viper.GetString("template"))
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
 		printer.Flush()
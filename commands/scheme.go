@@ -0,0 +1,399 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/client"
+	"github.com/mattermost/mmctl/printer"
+)
+
+var SchemeCmd = &cobra.Command{
+	Use:   "scheme",
+	Short: "Management of permission schemes",
+	Long:  "Management of the team and channel permission schemes, that override the default permissions of the teams and channels they are assigned to (Only works in Enterprise Edition).",
+}
+
+var SchemeCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a scheme",
+	Long:  "Create a team or channel permission scheme.",
+	Example: `  scheme create --name support --display_name "Support Teams" --scope team
+  scheme create --name read-only --display_name "Read Only Channels" --scope channel --description "Channels where only admins can post"`,
+	RunE: withClient(schemeCreateCmdF),
+}
+
+var SchemeListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List schemes",
+	Long:    "List the permission schemes, optionally filtered by scope.",
+	Example: "  scheme list --scope team",
+	Args:    cobra.NoArgs,
+	RunE:    withClient(schemeListCmdF),
+}
+
+var SchemeShowCmd = &cobra.Command{
+	Use:     "show [scheme]",
+	Short:   "Show a scheme",
+	Long:    "Show the information of a scheme, including the roles it uses. The scheme can be referenced by its ID or its name.",
+	Example: "  scheme show support",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(schemeShowCmdF),
+}
+
+var SchemePatchCmd = &cobra.Command{
+	Use:     "patch [scheme]",
+	Short:   "Patch a scheme",
+	Long:    "Change the name, display name or description of a scheme. Only the fields whose flags are set are modified.",
+	Example: `  scheme patch support --display_name "Customer Support Teams"`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(schemePatchCmdF),
+}
+
+var SchemeDeleteCmd = &cobra.Command{
+	Use:     "delete [schemes]",
+	Short:   "Delete schemes",
+	Long:    "Delete schemes. The teams and channels that used them go back to the default permissions.",
+	Example: "  scheme delete support",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    withClient(schemeDeleteCmdF),
+}
+
+var SchemeListTeamsCmd = &cobra.Command{
+	Use:     "list-teams [scheme]",
+	Short:   "List the teams of a scheme",
+	Long:    "List the teams that use a team scheme.",
+	Example: "  scheme list-teams support",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(schemeListTeamsCmdF),
+}
+
+var SchemeListChannelsCmd = &cobra.Command{
+	Use:     "list-channels [scheme]",
+	Short:   "List the channels of a scheme",
+	Long:    "List the channels that use a channel scheme.",
+	Example: "  scheme list-channels read-only",
+	Args:    cobra.ExactArgs(1),
+	RunE:    withClient(schemeListChannelsCmdF),
+}
+
+var SchemeAssignTeamCmd = &cobra.Command{
+	Use:     "assign-team [scheme] [teams]",
+	Short:   "Assign a scheme to teams",
+	Long:    "Assign a team scheme to one or more teams.",
+	Example: "  scheme assign-team support myteam otherteam",
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(schemeAssignTeamCmdF),
+}
+
+var SchemeAssignChannelCmd = &cobra.Command{
+	Use:     "assign-channel [scheme] [channels]",
+	Short:   "Assign a scheme to channels",
+	Long:    "Assign a channel scheme to one or more channels, referenced as team:channel.",
+	Example: "  scheme assign-channel read-only myteam:announcements myteam:news",
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    withClient(schemeAssignChannelCmdF),
+}
+
+func init() {
+	SchemeCreateCmd.Flags().String("name", "", "Scheme Name")
+	SchemeCreateCmd.Flags().String("display_name", "", "Scheme Display Name")
+	SchemeCreateCmd.Flags().String("description", "", "Scheme Description")
+	SchemeCreateCmd.Flags().String("scope", "", "Scope of the scheme, either \"team\" or \"channel\"")
+	_ = SchemeCreateCmd.MarkFlagRequired("name")
+	_ = SchemeCreateCmd.MarkFlagRequired("display_name")
+	_ = SchemeCreateCmd.MarkFlagRequired("scope")
+
+	SchemeListCmd.Flags().String("scope", "", "Only list the schemes of this scope, either \"team\" or \"channel\"")
+
+	SchemePatchCmd.Flags().String("name", "", "Scheme Name")
+	SchemePatchCmd.Flags().String("display_name", "", "Scheme Display Name")
+	SchemePatchCmd.Flags().String("description", "", "Scheme Description")
+
+	SchemeDeleteCmd.Flags().Bool("confirm", false, "Confirm you really want to delete the scheme.")
+
+	SchemeCmd.AddCommand(
+		SchemeCreateCmd,
+		SchemeListCmd,
+		SchemeShowCmd,
+		SchemePatchCmd,
+		SchemeDeleteCmd,
+		SchemeListTeamsCmd,
+		SchemeListChannelsCmd,
+		SchemeAssignTeamCmd,
+		SchemeAssignChannelCmd,
+	)
+
+	RootCmd.AddCommand(SchemeCmd)
+}
+
+const schemeTemplate = `id: {{.Id}}
+name: {{.Name}}
+display_name: {{.DisplayName}}
+description: {{.Description}}
+scope: {{.Scope}}
+{{- if eq .Scope "team"}}
+default_team_admin_role: {{.DefaultTeamAdminRole}}
+default_team_user_role: {{.DefaultTeamUserRole}}
+default_team_guest_role: {{.DefaultTeamGuestRole}}
+{{- end}}
+default_channel_admin_role: {{.DefaultChannelAdminRole}}
+default_channel_user_role: {{.DefaultChannelUserRole}}
+default_channel_guest_role: {{.DefaultChannelGuestRole}}`
+
+func validateSchemeScope(scope string) error {
+	if scope != model.SCHEME_SCOPE_TEAM && scope != model.SCHEME_SCOPE_CHANNEL {
+		return fmt.Errorf("invalid scope %q, it must be either %q or %q", scope, model.SCHEME_SCOPE_TEAM, model.SCHEME_SCOPE_CHANNEL)
+	}
+	return nil
+}
+
+func schemeCreateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	printer.SetSingle(true)
+
+	name, _ := cmd.Flags().GetString("name")
+	if name == "" {
+		return errors.New("name is required")
+	}
+	displayName, _ := cmd.Flags().GetString("display_name")
+	if displayName == "" {
+		return errors.New("display name is required")
+	}
+	description, _ := cmd.Flags().GetString("description")
+	scope, _ := cmd.Flags().GetString("scope")
+	if err := validateSchemeScope(scope); err != nil {
+		return err
+	}
+
+	scheme, response := c.CreateScheme(&model.Scheme{
+		Name:        name,
+		DisplayName: displayName,
+		Description: description,
+		Scope:       scope,
+	})
+	if response.Error != nil {
+		return errors.Wrap(response.Error, "failed to create scheme")
+	}
+
+	printer.PrintT("Scheme {{.Name}} successfully created with ID {{.Id}}", scheme)
+	return nil
+}
+
+func schemeListCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	scope, _ := cmd.Flags().GetString("scope")
+	if scope != "" {
+		if err := validateSchemeScope(scope); err != nil {
+			return err
+		}
+	}
+
+	for page := 0; ; page++ {
+		schemes, response := c.GetSchemes(scope, page, APILimitMaximum)
+		if response.Error != nil {
+			return errors.Wrap(response.Error, "failed to list schemes")
+		}
+
+		for _, scheme := range schemes {
+			printer.PrintT("{{.Name}}: {{.DisplayName}} ({{.Scope}}, {{.Id}})", scheme)
+		}
+
+		if len(schemes) < APILimitMaximum {
+			break
+		}
+	}
+
+	return nil
+}
+
+func schemeShowCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	printer.SetSingle(true)
+
+	scheme, err := getSchemeFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	printer.PrintT(schemeTemplate, scheme)
+	return nil
+}
+
+func schemePatchCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	printer.SetSingle(true)
+
+	scheme, err := getSchemeFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+
+	patch := &model.SchemePatch{}
+	if cmd.Flags().Changed("name") {
+		name, _ := cmd.Flags().GetString("name")
+		patch.Name = &name
+	}
+	if cmd.Flags().Changed("display_name") {
+		displayName, _ := cmd.Flags().GetString("display_name")
+		patch.DisplayName = &displayName
+	}
+	if cmd.Flags().Changed("description") {
+		description, _ := cmd.Flags().GetString("description")
+		patch.Description = &description
+	}
+	if patch.Name == nil && patch.DisplayName == nil && patch.Description == nil {
+		return errors.New("at least one of the --name, --display_name or --description flags must be set")
+	}
+
+	patched, response := c.PatchScheme(scheme.Id, patch)
+	if response.Error != nil {
+		return errors.Wrapf(response.Error, "failed to patch scheme %s", args[0])
+	}
+
+	printer.PrintT("Scheme {{.Name}} successfully updated", patched)
+	return nil
+}
+
+func getSchemeDeleteConfirmation() error {
+	var confirm string
+	fmt.Println("Are you sure you want to delete the schemes specified? The teams and channels using them will go back to the default permissions (YES/NO): ")
+	fmt.Scanln(&confirm)
+	if confirm != "YES" {
+		return errors.New("aborted: You did not answer YES exactly, in all capitals")
+	}
+	return nil
+}
+
+func schemeDeleteCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	confirmFlag, _ := cmd.Flags().GetBool("confirm")
+	if !confirmFlag {
+		if err := getSchemeDeleteConfirmation(); err != nil {
+			return err
+		}
+	}
+
+	for _, arg := range args {
+		scheme, err := getSchemeFromArg(c, arg)
+		if err != nil {
+			printItemError(err.Error(), err)
+			continue
+		}
+
+		if _, response := c.DeleteScheme(scheme.Id); response.Error != nil {
+			printItemError("Unable to delete scheme '"+scheme.Name+"'. Error: "+response.Error.Error(), response.Error)
+			continue
+		}
+		printer.PrintT("Deleted scheme '{{.Name}}'", scheme)
+	}
+
+	return nil
+}
+
+func schemeListTeamsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	scheme, err := getSchemeFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+	if scheme.Scope != model.SCHEME_SCOPE_TEAM {
+		return fmt.Errorf("scheme %s is not a team scheme", scheme.Name)
+	}
+
+	for page := 0; ; page++ {
+		teams, response := c.GetTeamsForScheme(scheme.Id, page, APILimitMaximum)
+		if response.Error != nil {
+			return errors.Wrap(response.Error, "failed to list the teams of the scheme")
+		}
+
+		for _, team := range teams {
+			printer.PrintT("{{.Name}}", team)
+		}
+
+		if len(teams) < APILimitMaximum {
+			break
+		}
+	}
+
+	return nil
+}
+
+func schemeListChannelsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	scheme, err := getSchemeFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+	if scheme.Scope != model.SCHEME_SCOPE_CHANNEL {
+		return fmt.Errorf("scheme %s is not a channel scheme", scheme.Name)
+	}
+
+	for page := 0; ; page++ {
+		channels, response := c.GetChannelsForScheme(scheme.Id, page, APILimitMaximum)
+		if response.Error != nil {
+			return errors.Wrap(response.Error, "failed to list the channels of the scheme")
+		}
+
+		for _, channel := range channels {
+			printer.PrintT("{{.Name}}", channel)
+		}
+
+		if len(channels) < APILimitMaximum {
+			break
+		}
+	}
+
+	return nil
+}
+
+func schemeAssignTeamCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	scheme, err := getSchemeFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+	if scheme.Scope != model.SCHEME_SCOPE_TEAM {
+		return fmt.Errorf("scheme %s is not a team scheme", scheme.Name)
+	}
+
+	teams := getTeamsFromTeamArgs(c, args[1:])
+	for i, team := range teams {
+		if team == nil {
+			printItemError("Unable to find team '"+args[i+1]+"'", ErrEntityNotFound{Type: "team", ID: args[i+1]})
+			continue
+		}
+
+		if _, response := c.UpdateTeamScheme(team.Id, scheme.Id); response.Error != nil {
+			printItemError("Unable to assign scheme to team '"+team.Name+"'. Error: "+response.Error.Error(), response.Error)
+			continue
+		}
+		printer.PrintT("Scheme "+scheme.Name+" assigned to team '{{.Name}}'", team)
+	}
+
+	return nil
+}
+
+func schemeAssignChannelCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	scheme, err := getSchemeFromArg(c, args[0])
+	if err != nil {
+		return err
+	}
+	if scheme.Scope != model.SCHEME_SCOPE_CHANNEL {
+		return fmt.Errorf("scheme %s is not a channel scheme", scheme.Name)
+	}
+
+	channels := getChannelsFromChannelArgs(c, args[1:])
+	for i, channel := range channels {
+		if channel == nil {
+			printItemError("Unable to find channel '"+args[i+1]+"'", ErrEntityNotFound{Type: "channel", ID: args[i+1]})
+			continue
+		}
+
+		if _, response := c.UpdateChannelScheme(channel.Id, scheme.Id); response.Error != nil {
+			printItemError("Unable to assign scheme to channel '"+channel.Name+"'. Error: "+response.Error.Error(), response.Error)
+			continue
+		}
+		printer.PrintT("Scheme "+scheme.Name+" assigned to channel '{{.Name}}'", channel)
+	}
+
+	return nil
+}
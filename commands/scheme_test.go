@@ -0,0 +1,254 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/spf13/cobra"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestSchemeCreateCmd() {
+	s.Run("should create a team scheme", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("name", "support", "")
+		cmd.Flags().String("display_name", "Support", "")
+		cmd.Flags().String("scope", model.SCHEME_SCOPE_TEAM, "")
+
+		mockScheme := &model.Scheme{Name: "support", DisplayName: "Support", Scope: model.SCHEME_SCOPE_TEAM}
+		createdScheme := &model.Scheme{Id: model.NewId(), Name: "support", DisplayName: "Support", Scope: model.SCHEME_SCOPE_TEAM}
+		s.client.
+			EXPECT().
+			CreateScheme(mockScheme).
+			Return(createdScheme, &model.Response{}).
+			Times(1)
+
+		err := schemeCreateCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(createdScheme, printer.GetLines()[0])
+	})
+
+	s.Run("should fail with an invalid scope", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("name", "support", "")
+		cmd.Flags().String("display_name", "Support", "")
+		cmd.Flags().String("scope", "system", "")
+
+		err := schemeCreateCmdF(s.client, cmd, []string{})
+		s.Require().EqualError(err, `invalid scope "system", it must be either "team" or "channel"`)
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSchemeListCmd() {
+	s.Run("should list the schemes of a scope", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("scope", model.SCHEME_SCOPE_CHANNEL, "")
+
+		schemes := []*model.Scheme{
+			{Id: model.NewId(), Name: "read-only", Scope: model.SCHEME_SCOPE_CHANNEL},
+			{Id: model.NewId(), Name: "moderated", Scope: model.SCHEME_SCOPE_CHANNEL},
+		}
+		s.client.
+			EXPECT().
+			GetSchemes(model.SCHEME_SCOPE_CHANNEL, 0, APILimitMaximum).
+			Return(schemes, &model.Response{}).
+			Times(1)
+
+		err := schemeListCmdF(s.client, cmd, []string{})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 2)
+		s.Require().Equal(schemes[1], printer.GetLines()[1])
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSchemeShowCmd() {
+	s.Run("should show a scheme by its name", func() {
+		printer.Clean()
+		scheme := &model.Scheme{Id: model.NewId(), Name: "support", Scope: model.SCHEME_SCOPE_TEAM}
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return([]*model.Scheme{scheme}, &model.Response{}).
+			Times(1)
+
+		err := schemeShowCmdF(s.client, &cobra.Command{}, []string{"support"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(scheme, printer.GetLines()[0])
+	})
+
+	s.Run("should show a scheme by its ID", func() {
+		printer.Clean()
+		scheme := &model.Scheme{Id: model.NewId(), Name: "support", Scope: model.SCHEME_SCOPE_TEAM}
+		s.client.
+			EXPECT().
+			GetScheme(scheme.Id).
+			Return(scheme, &model.Response{}).
+			Times(1)
+
+		err := schemeShowCmdF(s.client, &cobra.Command{}, []string{scheme.Id})
+		s.Require().NoError(err)
+		s.Require().Equal(scheme, printer.GetLines()[0])
+	})
+
+	s.Run("should fail if the scheme doesn't exist", func() {
+		printer.Clean()
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return([]*model.Scheme{}, &model.Response{}).
+			Times(1)
+
+		err := schemeShowCmdF(s.client, &cobra.Command{}, []string{"unknown"})
+		s.Require().EqualError(err, "scheme unknown not found")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSchemePatchCmd() {
+	scheme := &model.Scheme{Id: model.NewId(), Name: "support", DisplayName: "Support", Scope: model.SCHEME_SCOPE_TEAM}
+
+	s.Run("should only patch the fields that are set", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("name", "", "")
+		cmd.Flags().String("display_name", "", "")
+		cmd.Flags().String("description", "", "")
+		s.Require().NoError(cmd.Flags().Set("display_name", "Customer Support"))
+
+		displayName := "Customer Support"
+		patched := &model.Scheme{Id: scheme.Id, Name: "support", DisplayName: displayName, Scope: model.SCHEME_SCOPE_TEAM}
+		s.client.
+			EXPECT().
+			GetScheme(scheme.Id).
+			Return(scheme, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			PatchScheme(scheme.Id, &model.SchemePatch{DisplayName: &displayName}).
+			Return(patched, &model.Response{}).
+			Times(1)
+
+		err := schemePatchCmdF(s.client, cmd, []string{scheme.Id})
+		s.Require().NoError(err)
+		s.Require().Equal(patched, printer.GetLines()[0])
+	})
+
+	s.Run("should fail if no field is set", func() {
+		printer.Clean()
+		s.client.
+			EXPECT().
+			GetScheme(scheme.Id).
+			Return(scheme, &model.Response{}).
+			Times(1)
+
+		err := schemePatchCmdF(s.client, &cobra.Command{}, []string{scheme.Id})
+		s.Require().EqualError(err, "at least one of the --name, --display_name or --description flags must be set")
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSchemeDeleteCmd() {
+	s.Run("should delete the schemes and report the failures", func() {
+		printer.Clean()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("confirm", true, "")
+
+		scheme := &model.Scheme{Id: model.NewId(), Name: "support", Scope: model.SCHEME_SCOPE_TEAM}
+		s.client.
+			EXPECT().
+			GetSchemes("", 0, APILimitMaximum).
+			Return([]*model.Scheme{scheme}, &model.Response{}).
+			Times(2)
+		s.client.
+			EXPECT().
+			DeleteScheme(scheme.Id).
+			Return(true, &model.Response{}).
+			Times(1)
+
+		err := schemeDeleteCmdF(s.client, cmd, []string{"support", "unknown"})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Equal(scheme, printer.GetLines()[0])
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().Equal("scheme unknown not found", printer.GetErrorLines()[0])
+	})
+}
+
+func (s *MmctlUnitTestSuite) TestSchemeAssignCmds() {
+	teamScheme := &model.Scheme{Id: model.NewId(), Name: "support", Scope: model.SCHEME_SCOPE_TEAM}
+	channelScheme := &model.Scheme{Id: model.NewId(), Name: "read-only", Scope: model.SCHEME_SCOPE_CHANNEL}
+
+	s.Run("should assign a team scheme to teams", func() {
+		printer.Clean()
+		team := &model.Team{Id: teamID, Name: "myteam"}
+		s.client.
+			EXPECT().
+			GetScheme(teamScheme.Id).
+			Return(teamScheme, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetTeam(teamID, "").
+			Return(team, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			UpdateTeamScheme(teamID, teamScheme.Id).
+			Return(true, &model.Response{}).
+			Times(1)
+
+		err := schemeAssignTeamCmdF(s.client, &cobra.Command{}, []string{teamScheme.Id, teamID})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetLines(), 1)
+		s.Require().Empty(printer.GetErrorLines())
+	})
+
+	s.Run("should not assign a team scheme to channels", func() {
+		printer.Clean()
+		s.client.
+			EXPECT().
+			GetScheme(teamScheme.Id).
+			Return(teamScheme, &model.Response{}).
+			Times(1)
+
+		err := schemeAssignChannelCmdF(s.client, &cobra.Command{}, []string{teamScheme.Id, "myteam:channel"})
+		s.Require().EqualError(err, "scheme support is not a channel scheme")
+	})
+
+	s.Run("should report the channels that fail", func() {
+		printer.Clean()
+		channel := &model.Channel{Id: channelID, Name: channelName}
+		s.client.
+			EXPECT().
+			GetScheme(channelScheme.Id).
+			Return(channelScheme, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetTeam(teamID, "").
+			Return(&model.Team{Id: teamID}, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			GetChannelByNameIncludeDeleted(channelName, teamID, "").
+			Return(channel, &model.Response{}).
+			Times(1)
+		s.client.
+			EXPECT().
+			UpdateChannelScheme(channelID, channelScheme.Id).
+			Return(false, &model.Response{Error: &model.AppError{Message: "forbidden", StatusCode: http.StatusForbidden}}).
+			Times(1)
+
+		err := schemeAssignChannelCmdF(s.client, &cobra.Command{}, []string{channelScheme.Id, teamID + ":" + channelName})
+		s.Require().NoError(err)
+		s.Require().Len(printer.GetErrorLines(), 1)
+		s.Require().Equal("Unable to assign scheme to channel '"+channelName+"'. Error: : forbidden, ", printer.GetErrorLines()[0])
+	})
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+
+	"github.com/mattermost/mmctl/client"
+)
+
+// getSchemeFromArg obtains a scheme by its ID or, as the API has no
+// endpoint to get them by name, looking for its name in the list of
+// schemes
+func getSchemeFromArg(c client.Client, schemeArg string) (*model.Scheme, error) {
+	if model.IsValidId(schemeArg) {
+		scheme, response := c.GetScheme(schemeArg)
+		if response != nil && response.Error != nil {
+			err := ExtractErrorFromResponse(response)
+			var nfErr *NotFoundError
+			var badRequestErr *BadRequestError
+			if !errors.As(err, &nfErr) && !errors.As(err, &badRequestErr) {
+				return nil, err
+			}
+		}
+		if scheme != nil {
+			return scheme, nil
+		}
+	}
+
+	for page := 0; ; page++ {
+		schemes, response := c.GetSchemes("", page, APILimitMaximum)
+		if response.Error != nil {
+			return nil, ExtractErrorFromResponse(response)
+		}
+
+		for _, scheme := range schemes {
+			if scheme.Name == schemeArg {
+				return scheme, nil
+			}
+		}
+
+		if len(schemes) < APILimitMaximum {
+			break
+		}
+	}
+
+	return nil, ErrEntityNotFound{Type: "scheme", ID: schemeArg}
+}
@@ -187,11 +187,11 @@ func archiveTeamsCmdF(c client.Client, cmd *cobra.Command, args []string) error
 	teams := getTeamsFromTeamArgs(c, args)
 	for i, team := range teams {
 		if team == nil {
-			printer.PrintError("Unable to find team '" + args[i] + "'")
+			printItemError("Unable to find team '"+args[i]+"'", ErrEntityNotFound{Type: "team", ID: args[i]})
 			continue
 		}
 		if _, response := c.SoftDeleteTeam(team.Id); response.Error != nil {
-			printer.PrintError("Unable to archive team '" + team.Name + "' error: " + response.Error.Error())
+			printItemError("Unable to archive team '"+team.Name+"' error: "+response.Error.Error(), response.Error)
 		} else {
 			printer.PrintT("Archived team '{{.Name}}'", team)
 		}
@@ -236,7 +236,7 @@ func searchTeamCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 		}
 
 		if len(foundTeams) == 0 {
-			printer.PrintError("Unable to find team '" + searchTerm + "'")
+			printItemError("Unable to find team '"+searchTerm+"'", ErrEntityNotFound{Type: "team", ID: searchTerm})
 			continue
 		}
 
@@ -309,11 +309,11 @@ func deleteTeamsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	teams := getTeamsFromTeamArgs(c, args)
 	for i, team := range teams {
 		if team == nil {
-			printer.PrintError("Unable to find team '" + args[i] + "'")
+			printItemError("Unable to find team '"+args[i]+"'", ErrEntityNotFound{Type: "team", ID: args[i]})
 			continue
 		}
 		if _, response := deleteTeam(c, team); response.Error != nil {
-			printer.PrintError("Unable to delete team '" + team.Name + "' error: " + response.Error.Error())
+			printItemError("Unable to delete team '"+team.Name+"' error: "+response.Error.Error(), response.Error)
 		} else {
 			printer.PrintT("Deleted team '{{.Name}}'", team)
 		}
@@ -340,11 +340,11 @@ func modifyTeamsCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 	teams := getTeamsFromTeamArgs(c, args)
 	for i, team := range teams {
 		if team == nil {
-			printer.PrintError("Unable to find team '" + args[i] + "'")
+			printItemError("Unable to find team '"+args[i]+"'", ErrEntityNotFound{Type: "team", ID: args[i]})
 			continue
 		}
 		if updatedTeam, response := c.UpdateTeamPrivacy(team.Id, privacy); response.Error != nil {
-			printer.PrintError("Unable to modify team '" + team.Name + "' error: " + response.Error.Error())
+			printItemError("Unable to modify team '"+team.Name+"' error: "+response.Error.Error(), response.Error)
 		} else {
 			printer.PrintT("Modified team '{{.Name}}'", updatedTeam)
 		}
@@ -357,11 +357,11 @@ func restoreTeamsCmdF(c client.Client, cmd *cobra.Command, args []string) error
 	teams := getTeamsFromTeamArgs(c, args)
 	for i, team := range teams {
 		if team == nil {
-			printer.PrintError("Unable to find team '" + args[i] + "'")
+			printItemError("Unable to find team '"+args[i]+"'", ErrEntityNotFound{Type: "team", ID: args[i]})
 			continue
 		}
 		if rteam, response := c.RestoreTeam(team.Id); response.Error != nil {
-			printer.PrintError("Unable to restore team '" + team.Name + "' error: " + response.Error.Error())
+			printItemError("Unable to restore team '"+team.Name+"' error: "+response.Error.Error(), response.Error)
 		} else {
 			printer.PrintT("Restored team '{{.Name}}'", rteam)
 		}
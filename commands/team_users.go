@@ -9,7 +9,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/mattermost/mmctl/client"
-	"github.com/mattermost/mmctl/printer"
 )
 
 var TeamUsersCmd = &cobra.Command{
@@ -27,15 +26,24 @@ var TeamUsersRemoveCmd = &cobra.Command{
 }
 
 var TeamUsersAddCmd = &cobra.Command{
-	Use:     "add [team] [users]",
-	Short:   "Add users to team",
-	Long:    "Add some users to team",
-	Example: "  team add myteam user@example.com username",
-	Args:    cobra.MinimumNArgs(2),
-	RunE:    withClient(teamUsersAddCmdF),
+	Use:   "add [team] [users]",
+	Short: "Add users to team",
+	Long:  "Add some users to team",
+	Example: `  team users add myteam user@example.com username
+
+  # Add the users listed in a CSV file with "team" and "email" or "username" columns
+  team users add --from-file users.csv
+
+  # The team can also be passed as an argument
+  team users add myteam --from-file users.csv`,
+	Args: argsFromFile(cobra.MinimumNArgs(2)),
+	RunE: withClient(teamUsersAddCmdF),
 }
 
 func init() {
+	addParallelFlag(TeamUsersAddCmd)
+	addFromFileFlags(TeamUsersAddCmd)
+
 	TeamUsersCmd.AddCommand(
 		TeamUsersRemoveCmd,
 		TeamUsersAddCmd,
@@ -60,35 +68,45 @@ func teamUsersRemoveCmdF(c client.Client, cmd *cobra.Command, args []string) err
 
 func removeUserFromTeam(c client.Client, team *model.Team, user *model.User, userArg string) {
 	if user == nil {
-		printer.PrintError("Can't find user '" + userArg + "'")
+		printItemError("Can't find user '"+userArg+"'", ErrEntityNotFound{Type: "user", ID: userArg})
 		return
 	}
 	if _, response := c.RemoveTeamMember(team.Id, user.Id); response.Error != nil {
-		printer.PrintError("Unable to remove '" + userArg + "' from " + team.Name + ". Error: " + response.Error.Error())
+		printItemError("Unable to remove '"+userArg+"' from "+team.Name+". Error: "+response.Error.Error(), response.Error)
 	}
 }
 
 func teamUsersAddCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	if path, _ := cmd.Flags().GetString("from-file"); path != "" {
+		return runFromFile(c, cmd, args, [][]string{{"team"}, userColumns}, teamUsersAddCmdF)
+	}
+
 	team := getTeamFromTeamArg(c, args[0])
 	if team == nil {
 		return errors.New("Unable to find team '" + args[0] + "'")
 	}
 
-	users := getUsersFromUserArgs(c, args[1:])
-	for i, user := range users {
-		addUserToTeam(c, team, user, args[i+1])
-	}
+	workers := getParallelWorkers(cmd)
+	users := getUsersFromUserArgsInParallel(c, args[1:], workers)
+	errs := runParallel(workers, len(users), func(i int) error {
+		return addUserToTeam(c, team, users[i], args[i+1])
+	}, func(i int, err error) {
+		if err != nil {
+			printItemError(err.Error(), err)
+		}
+	})
+	printParallelSummary(workers, len(users), countErrors(errs))
 
 	return nil
 }
 
-func addUserToTeam(c client.Client, team *model.Team, user *model.User, userArg string) {
+func addUserToTeam(c client.Client, team *model.Team, user *model.User, userArg string) error {
 	if user == nil {
-		printer.PrintError("Can't find user '" + userArg + "'")
-		return
+		return errors.New("Can't find user '" + userArg + "'")
 	}
 
 	if _, response := c.AddTeamMember(team.Id, user.Id); response.Error != nil {
-		printer.PrintError("Unable to add '" + userArg + "' to " + team.Name + ". Error: " + response.Error.Error())
+		return errors.New("Unable to add '" + userArg + "' to " + team.Name + ". Error: " + response.Error.Error())
 	}
+	return nil
 }
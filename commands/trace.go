@@ -0,0 +1,395 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	traceRedacted = "[REDACTED]"
+	// traceMaxBodySize is the size limit of the bodies included in the
+	// trace. Bigger bodies, like file uploads, are only described
+	traceMaxBodySize = 64 * 1024
+	traceHARSuffix   = ".har"
+)
+
+// traceSensitiveHeaders are the headers whose values are redacted
+var traceSensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"Token":               true,
+	"X-Csrf-Token":        true,
+}
+
+// traceSensitiveFields are the fields of the JSON bodies whose values
+// are redacted, like the passwords of the users or the secrets of the
+// config. The names are lowercased and without underscores, so they
+// match both the fields of the API and the settings of the config
+var traceSensitiveFields = map[string]bool{
+	"password":                 true,
+	"currentpassword":          true,
+	"newpassword":              true,
+	"token":                    true,
+	"accesstoken":              true,
+	"refreshtoken":             true,
+	"mfatoken":                 true,
+	"mfasecret":                true,
+	"secret":                   true,
+	"clientsecret":             true,
+	"publiclinksalt":           true,
+	"invitesalt":               true,
+	"atrestencryptkey":         true,
+	"datasource":               true,
+	"datasourcereplicas":       true,
+	"datasourcesearchreplicas": true,
+	"smtppassword":             true,
+	"bindpassword":             true,
+	"amazons3secretaccesskey":  true,
+	"gfycatapisecret":          true,
+}
+
+// traceSensitiveParams are the query parameters of the URLs whose
+// values are redacted, lowercased
+var traceSensitiveParams = map[string]bool{
+	"token":        true,
+	"access_token": true,
+	"mmauthtoken":  true,
+}
+
+// activeTracer records the requests to the server when the --trace
+// flag is set
+var activeTracer *tracer
+
+// tracer writes each request to the server and its response, or
+// collects them to write them as a HAR file once the command finishes
+type tracer struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	har    bool
+	// entries are the requests collected for the HAR file
+	entries []*harEntry
+}
+
+// traceEntry is a request and its response, with the headers and the
+// bodies already redacted
+type traceEntry struct {
+	started         time.Time
+	duration        time.Duration
+	method          string
+	url             string
+	proto           string
+	requestHeaders  http.Header
+	requestBody     traceBody
+	status          int
+	statusText      string
+	responseProto   string
+	responseHeaders http.Header
+	responseBody    traceBody
+	err             error
+}
+
+// traceBody is a body of a request or a response as it is shown in
+// the trace
+type traceBody struct {
+	mimeType string
+	size     int64
+	text     string
+}
+
+// startTrace creates the tracer if the trace is enabled through the
+// flags
+func startTrace() error {
+	traceFile := viper.GetString("trace-file")
+	if !viper.GetBool("trace") && traceFile == "" {
+		return nil
+	}
+
+	t := &tracer{w: os.Stderr}
+	if traceFile != "" {
+		f, err := os.OpenFile(traceFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return errors.Wrap(err, "cannot create the trace file")
+		}
+		t.w = f
+		t.closer = f
+		t.har = strings.HasSuffix(strings.ToLower(traceFile), traceHARSuffix)
+	}
+	activeTracer = t
+	return nil
+}
+
+// stopTrace writes the HAR file, if the trace is exported in that
+// format, and closes the trace file
+func stopTrace() error {
+	t := activeTracer
+	if t == nil {
+		return nil
+	}
+	activeTracer = nil
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var err error
+	if t.har {
+		err = writeHAR(t.w, t.entries)
+	}
+	if t.closer != nil {
+		if closeErr := t.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return errors.WithMessage(err, "cannot write the trace file")
+}
+
+// wrapTrace adds the tracing of the requests to a transport, if the
+// trace is enabled
+func wrapTrace(base http.RoundTripper) http.RoundTripper {
+	if activeTracer == nil {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &traceTransport{base: base, tracer: activeTracer}
+}
+
+// traceTransport records each request sent through it and its
+// response. The entry is recorded once the response body is closed,
+// so the body can be included without reading it beforehand
+type traceTransport struct {
+	base   http.RoundTripper
+	tracer *tracer
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry := &traceEntry{
+		started:        time.Now(),
+		method:         req.Method,
+		url:            redactURL(req.URL),
+		proto:          req.Proto,
+		requestHeaders: redactHeaders(req.Header),
+	}
+
+	var requestBody *bodyRecorder
+	if req.Body != nil && req.Body != http.NoBody {
+		requestBody = &bodyRecorder{ReadCloser: req.Body}
+		tracedReq := new(http.Request)
+		*tracedReq = *req
+		tracedReq.Body = requestBody
+		req = tracedReq
+	}
+
+	res, err := t.base.RoundTrip(req)
+	entry.duration = time.Since(entry.started)
+	if requestBody != nil {
+		entry.requestBody = requestBody.traceBody(req.Header.Get("Content-Type"))
+	}
+	if err != nil {
+		entry.err = err
+		t.tracer.record(entry)
+		return nil, err
+	}
+
+	entry.status = res.StatusCode
+	entry.statusText = http.StatusText(res.StatusCode)
+	entry.responseProto = res.Proto
+	entry.responseHeaders = redactHeaders(res.Header)
+	responseBody := &bodyRecorder{ReadCloser: res.Body}
+	responseBody.onClose = func() {
+		entry.responseBody = responseBody.traceBody(res.Header.Get("Content-Type"))
+		t.tracer.record(entry)
+	}
+	res.Body = responseBody
+	return res, nil
+}
+
+// bodyRecorder keeps the beginning of a body as it is read, up to the
+// size limit of the trace. The request bodies may still be read by the
+// transport when the response arrives, so the access is synchronized
+type bodyRecorder struct {
+	io.ReadCloser
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	size      int64
+	onClose   func()
+	closeOnce sync.Once
+}
+
+func (r *bodyRecorder) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.size += int64(n)
+	if remaining := traceMaxBodySize - r.buf.Len(); remaining > 0 {
+		if n < remaining {
+			remaining = n
+		}
+		r.buf.Write(p[:remaining])
+	}
+	return n, err
+}
+
+func (r *bodyRecorder) Close() error {
+	err := r.ReadCloser.Close()
+	r.closeOnce.Do(func() {
+		if r.onClose != nil {
+			r.onClose()
+		}
+	})
+	return err
+}
+
+// traceBody returns the recorded body as it is shown in the trace.
+// Only the JSON bodies are shown, with their sensitive fields redacted,
+// as the rest can't be redacted
+func (r *bodyRecorder) traceBody(contentType string) traceBody {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body := traceBody{mimeType: contentType, size: r.size}
+	if r.size == 0 {
+		return body
+	}
+	if r.size > traceMaxBodySize {
+		body.text = fmt.Sprintf("<%d bytes omitted>", r.size)
+		return body
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(r.buf.Bytes(), &value); err != nil {
+		body.text = fmt.Sprintf("<%d bytes of %s omitted>", r.size, contentType)
+		return body
+	}
+	b, err := json.Marshal(redactJSON(value))
+	if err != nil {
+		body.text = fmt.Sprintf("<%d bytes omitted>", r.size)
+		return body
+	}
+	body.text = string(b)
+	return body
+}
+
+func redactHeaders(header http.Header) http.Header {
+	redacted := http.Header{}
+	for name, values := range header {
+		if traceSensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			redacted[name] = []string{traceRedacted}
+			continue
+		}
+		redacted[name] = values
+	}
+	return redacted
+}
+
+// redactURL returns the URL with the values of its sensitive query
+// parameters replaced. The query is rewritten as is, so the rest of the
+// parameters keep their order and encoding
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		name := strings.SplitN(param, "=", 2)[0]
+		unescaped, err := url.QueryUnescape(name)
+		if err != nil {
+			unescaped = name
+		}
+		if traceSensitiveParams[strings.ToLower(unescaped)] {
+			params[i] = name + "=" + traceRedacted
+		}
+	}
+
+	redacted := *u
+	redacted.RawQuery = strings.Join(params, "&")
+	return redacted.String()
+}
+
+// redactJSON replaces the values of the sensitive fields of a decoded
+// JSON value
+func redactJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			switch field.(type) {
+			case string, []interface{}:
+				if traceSensitiveFields[strings.ToLower(strings.ReplaceAll(key, "_", ""))] {
+					v[key] = traceRedacted
+					continue
+				}
+			}
+			v[key] = redactJSON(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSON(item)
+		}
+	}
+	return value
+}
+
+func (t *tracer) record(entry *traceEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.har {
+		t.entries = append(t.entries, newHAREntry(entry))
+		return
+	}
+	writeTraceEntry(t.w, entry)
+}
+
+func writeTraceHeaders(w io.Writer, prefix string, header http.Header) {
+	names := []string{}
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(w, "%s %s: %s\n", prefix, name, value)
+		}
+	}
+}
+
+// writeTraceEntry writes an entry in the text format of the trace,
+// with the lines of the request starting with > and the ones of the
+// response with <
+func writeTraceEntry(w io.Writer, entry *traceEntry) {
+	fmt.Fprintf(w, "> %s %s\n", entry.method, entry.url)
+	writeTraceHeaders(w, ">", entry.requestHeaders)
+	if entry.requestBody.text != "" {
+		fmt.Fprintf(w, "> %s\n", entry.requestBody.text)
+	}
+
+	if entry.err != nil {
+		fmt.Fprintf(w, "< error: %s (%s)\n\n", entry.err, entry.duration)
+		return
+	}
+	fmt.Fprintf(w, "< %d %s (%s)\n", entry.status, entry.statusText, entry.duration)
+	writeTraceHeaders(w, "<", entry.responseHeaders)
+	if entry.responseBody.text != "" {
+		fmt.Fprintf(w, "< %s\n", entry.responseBody.text)
+	}
+	fmt.Fprintln(w, "")
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"sort"
+	"time"
+)
+
+const harVersion = "1.2"
+
+// harLog is the root of a HAR file, as defined in
+// http://www.softwareishard.com/blog/har-12-spec/
+type harLog struct {
+	Log struct {
+		Version string      `json:"version"`
+		Creator harCreator  `json:"creator"`
+		Entries []*harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	// Error is the error of the requests that got no response, as a
+	// custom field of the entry
+	Error string `json:"_error,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func newHARNameValues(values map[string][]string) []harNameValue {
+	names := []string{}
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nameValues := []harNameValue{}
+	for _, name := range names {
+		for _, value := range values[name] {
+			nameValues = append(nameValues, harNameValue{Name: name, Value: value})
+		}
+	}
+	return nameValues
+}
+
+// newHAREntry converts a trace entry to a HAR one. The sizes of the
+// headers are not known, and the whole duration of the request is
+// reported as waiting time
+func newHAREntry(entry *traceEntry) *harEntry {
+	milliseconds := float64(entry.duration) / float64(time.Millisecond)
+	harEntry := &harEntry{
+		StartedDateTime: entry.started.Format(time.RFC3339Nano),
+		Time:            milliseconds,
+		Request: harRequest{
+			Method:      entry.method,
+			URL:         entry.url,
+			HTTPVersion: entry.proto,
+			Cookies:     []harNameValue{},
+			Headers:     newHARNameValues(entry.requestHeaders),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    entry.requestBody.size,
+		},
+		Response: harResponse{
+			Status:      entry.status,
+			StatusText:  entry.statusText,
+			HTTPVersion: entry.responseProto,
+			Cookies:     []harNameValue{},
+			Headers:     newHARNameValues(entry.responseHeaders),
+			Content: harContent{
+				Size:     entry.responseBody.size,
+				MimeType: entry.responseBody.mimeType,
+				Text:     entry.responseBody.text,
+			},
+			HeadersSize: -1,
+			BodySize:    entry.responseBody.size,
+		},
+		Timings: harTimings{Wait: milliseconds},
+	}
+
+	if u, err := url.Parse(entry.url); err == nil {
+		harEntry.Request.QueryString = newHARNameValues(u.Query())
+	}
+	if entry.requestBody.size > 0 {
+		harEntry.Request.PostData = &harPostData{MimeType: entry.requestBody.mimeType, Text: entry.requestBody.text}
+	}
+	if entry.err != nil {
+		harEntry.Error = entry.err.Error()
+	}
+	return harEntry
+}
+
+func writeHAR(w io.Writer, entries []*harEntry) error {
+	var har harLog
+	har.Log.Version = harVersion
+	har.Log.Creator = harCreator{Name: "mmctl", Version: Version}
+	har.Log.Entries = entries
+	if har.Log.Entries == nil {
+		har.Log.Entries = []*harEntry{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(har)
+}
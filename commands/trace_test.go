@@ -0,0 +1,151 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func (s *MmctlUnitTestSuite) TestTrace() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Token", "sessiontoken")
+		_, _ = w.Write([]byte(`{"id": "userid", "username": "sysadmin", "notify_props": {"mention_keys": "sysadmin"}}`))
+	}))
+	defer server.Close()
+
+	s.Run("should write the requests with the secrets redacted", func() {
+		var buf bytes.Buffer
+		activeTracer = &tracer{w: &buf}
+		defer func() { activeTracer = nil }()
+
+		c, err := NewAPIv4Client(server.URL, false, false)
+		s.Require().NoError(err)
+		_, response := c.Login("sysadmin", "Sup3rSecret")
+		s.Require().Nil(response.Error)
+		s.Require().Equal("sessiontoken", c.AuthToken)
+
+		trace := buf.String()
+		s.Require().Contains(trace, "> POST "+server.URL+"/api/v4/users/login\n")
+		s.Require().Contains(trace, `> {"login_id":"sysadmin","password":"[REDACTED]"}`)
+		s.Require().Contains(trace, "< 200 OK (")
+		s.Require().Contains(trace, "< Token: [REDACTED]\n")
+		s.Require().Contains(trace, `< {"id":"userid","notify_props":{"mention_keys":"sysadmin"},"username":"sysadmin"}`)
+		s.Require().NotContains(trace, "Sup3rSecret")
+		s.Require().NotContains(trace, "sessiontoken")
+	})
+
+	s.Run("should redact the tokens in the query of the URLs", func() {
+		var buf bytes.Buffer
+		activeTracer = &tracer{w: &buf}
+		defer func() { activeTracer = nil }()
+
+		client := &http.Client{Transport: wrapTrace(http.DefaultTransport)}
+		res, err := client.Get(server.URL + "/api/v4/users/me?access_token=accesstoken&page=0&MMAUTHTOKEN=authtoken&per_page=10&token=querytoken")
+		s.Require().NoError(err)
+		res.Body.Close()
+
+		trace := buf.String()
+		s.Require().Contains(trace, "> GET "+server.URL+"/api/v4/users/me?access_token=[REDACTED]&page=0&MMAUTHTOKEN=[REDACTED]&per_page=10&token=[REDACTED]\n")
+		s.Require().NotContains(trace, "accesstoken")
+		s.Require().NotContains(trace, "authtoken")
+		s.Require().NotContains(trace, "querytoken")
+	})
+
+	s.Run("should only redact the sensitive fields", func() {
+		value := map[string]interface{}{
+			"password":      "secret1",
+			"notify_props":  map[string]interface{}{"mention_keys": "sysadmin"},
+			"SqlSettings":   map[string]interface{}{"DataSource": "secret2", "DataSourceReplicas": []interface{}{"secret3"}, "DriverName": "postgres"},
+			"FileSettings":  map[string]interface{}{"PublicLinkSalt": "secret4", "AmazonS3SecretAccessKey": "secret5", "AmazonS3AccessKeyId": "keyid"},
+			"client_secret": "secret6",
+			"token_id":      "tokenid",
+		}
+		s.Require().Equal(map[string]interface{}{
+			"password":      traceRedacted,
+			"notify_props":  map[string]interface{}{"mention_keys": "sysadmin"},
+			"SqlSettings":   map[string]interface{}{"DataSource": traceRedacted, "DataSourceReplicas": traceRedacted, "DriverName": "postgres"},
+			"FileSettings":  map[string]interface{}{"PublicLinkSalt": traceRedacted, "AmazonS3SecretAccessKey": traceRedacted, "AmazonS3AccessKeyId": "keyid"},
+			"client_secret": traceRedacted,
+			"token_id":      "tokenid",
+		}, redactJSON(value))
+	})
+
+	s.Run("should omit the bodies that are not JSON", func() {
+		var buf bytes.Buffer
+		activeTracer = &tracer{w: &buf}
+		defer func() { activeTracer = nil }()
+
+		client := &http.Client{Transport: wrapTrace(http.DefaultTransport)}
+		res, err := client.Post(server.URL+"/api/v4/files", "text/plain", strings.NewReader("file contents"))
+		s.Require().NoError(err)
+		res.Body.Close()
+
+		s.Require().Contains(buf.String(), "> <13 bytes of text/plain omitted>\n")
+		s.Require().NotContains(buf.String(), "file contents")
+	})
+
+	s.Run("should export the requests in HAR format", func() {
+		dir, err := ioutil.TempDir("", "mmctl-trace")
+		s.Require().NoError(err)
+		defer os.RemoveAll(dir)
+
+		traceFile := filepath.Join(dir, "trace.har")
+		cmd := &cobra.Command{}
+		cmd.Flags().String("trace-file", traceFile, "")
+		_ = viper.BindPFlag("trace-file", cmd.Flags().Lookup("trace-file"))
+		defer func() {
+			resetCmd := &cobra.Command{}
+			resetCmd.Flags().String("trace-file", "", "")
+			_ = viper.BindPFlag("trace-file", resetCmd.Flags().Lookup("trace-file"))
+		}()
+
+		s.Require().NoError(startTrace())
+		c, err := NewAPIv4Client(server.URL, false, false)
+		s.Require().NoError(err)
+		c.SetToken("accesstoken")
+		_, response := c.GetMe("")
+		s.Require().Nil(response.Error)
+		res, appErr := c.DoApiGet("/users/me?token=querytoken&page=0", "")
+		s.Require().Nil(appErr)
+		res.Body.Close()
+		s.Require().NoError(stopTrace())
+		s.Require().Nil(activeTracer)
+
+		b, err := ioutil.ReadFile(traceFile)
+		s.Require().NoError(err)
+		s.Require().NotContains(string(b), "accesstoken")
+		s.Require().NotContains(string(b), "querytoken")
+
+		var har harLog
+		s.Require().NoError(json.Unmarshal(b, &har))
+		s.Require().Equal("1.2", har.Log.Version)
+		s.Require().Equal("mmctl", har.Log.Creator.Name)
+		s.Require().Len(har.Log.Entries, 2)
+
+		entry := har.Log.Entries[0]
+		s.Require().Equal(http.MethodGet, entry.Request.Method)
+		s.Require().Equal(server.URL+"/api/v4/users/me", entry.Request.URL)
+		s.Require().Contains(entry.Request.Headers, harNameValue{Name: "Authorization", Value: traceRedacted})
+		s.Require().Nil(entry.Request.PostData)
+		s.Require().Equal(http.StatusOK, entry.Response.Status)
+		s.Require().Equal("application/json", entry.Response.Content.MimeType)
+		s.Require().Contains(entry.Response.Content.Text, `"username":"sysadmin"`)
+
+		entry = har.Log.Entries[1]
+		s.Require().Equal(server.URL+"/api/v4/users/me?token=[REDACTED]&page=0", entry.Request.URL)
+		s.Require().Equal([]harNameValue{{Name: "page", Value: "0"}, {Name: "token", Value: traceRedacted}}, entry.Request.QueryString)
+	})
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = time.Minute
+)
+
+// retryTransport retries the requests that fail because of the rate
+// limit or of transient server errors. Requests that were rate
+// limited are retried for any method as the server didn't process
+// them, while the rest of the failures are only retried for
+// idempotent methods
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	// timeout is the time limit for each attempt to get the response
+	// of the server, or zero for no limit
+	timeout time.Duration
+	verbose bool
+
+	// sleep waits for the given duration or until the request is
+	// cancelled, and can be replaced in tests
+	sleep func(req *http.Request, d time.Duration) error
+}
+
+func newRetryTransport(base http.RoundTripper, maxRetries int, timeout time.Duration, verbose bool) *retryTransport {
+	return &retryTransport{
+		base:       base,
+		maxRetries: maxRetries,
+		timeout:    timeout,
+		verbose:    verbose,
+		sleep:      sleepWithContext,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq, err := rewindRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := t.roundTripAttempt(attemptReq)
+		if attempt >= t.maxRetries || !shouldRetry(req, res, err) {
+			return res, err
+		}
+
+		delay := retryDelay(res, attempt)
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = res.Status
+			// the body needs to be consumed for the connection to
+			// be reused
+			_, _ = io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		if t.verbose {
+			printer.PrintWarning(fmt.Sprintf("Request %s %s failed: %s. Retrying in %s (%d/%d)", req.Method, req.URL.Path, reason, delay, attempt+1, t.maxRetries))
+		}
+
+		if err := t.sleep(req, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// roundTripAttempt sends an attempt of the request, cancelling it if
+// the response doesn't arrive within the timeout. Reading the body of
+// the response is not limited, so long downloads are not interrupted
+func (t *retryTransport) roundTripAttempt(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(t.timeout, cancel)
+	res, err := t.base.RoundTrip(req.WithContext(ctx))
+	if !timer.Stop() {
+		if err == nil {
+			res.Body.Close()
+		}
+		cancel()
+		return nil, fmt.Errorf("no response from the server after %s", t.timeout)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	res.Body = &cancelOnCloseBody{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// cancelOnCloseBody releases the context of an attempt once the body
+// of its response is closed
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// rewindRequest returns the request to send for an attempt. The first
+// attempt uses the original request and the next ones a copy with a
+// new body, as the previous one has been consumed
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	newReq := req.Clone(req.Context())
+	newReq.Body = body
+	return newReq, nil
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	// a body that can't be read again makes the request impossible to
+	// repeat
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return isIdempotent(req.Method) && req.Context().Err() == nil
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+// retryDelay returns the time to wait before the next attempt. If the
+// server sent a Retry-After or X-Ratelimit-Reset header it is used,
+// otherwise the delay grows exponentially with each attempt
+func retryDelay(res *http.Response, attempt int) time.Duration {
+	if res != nil {
+		if d, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return d
+		}
+		if seconds, err := strconv.Atoi(res.Header.Get("X-Ratelimit-Reset")); err == nil && seconds >= 0 {
+			return minDuration(time.Duration(seconds)*time.Second, retryMaxDelay)
+		}
+	}
+
+	delay := retryBaseDelay
+	for i := 0; i < attempt && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return minDuration(delay, retryMaxDelay)
+}
+
+// parseRetryAfter parses the value of a Retry-After header, that can
+// be either a number of seconds or a date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return minDuration(time.Duration(seconds)*time.Second, retryMaxDelay), true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		d := time.Until(date)
+		if d < 0 {
+			d = 0
+		}
+		return minDuration(d, retryMaxDelay), true
+	}
+
+	return 0, false
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func sleepWithContext(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
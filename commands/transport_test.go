@@ -0,0 +1,165 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package commands
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mmctl/printer"
+)
+
+func (s *MmctlUnitTestSuite) TestRetryTransport() {
+	newTestTransport := func(maxRetries int, delays *[]time.Duration) *retryTransport {
+		transport := newRetryTransport(http.DefaultTransport, maxRetries, 0, true)
+		transport.sleep = func(_ *http.Request, d time.Duration) error {
+			*delays = append(*delays, d)
+			return nil
+		}
+		return transport
+	}
+
+	s.Run("should retry rate limited requests honouring the headers", func() {
+		printer.Clean()
+		bodies := []string{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			switch len(bodies) {
+			case 1:
+				w.Header().Set("Retry-After", "2")
+				w.WriteHeader(http.StatusTooManyRequests)
+			case 2:
+				w.Header().Set("X-Ratelimit-Reset", "5")
+				w.WriteHeader(http.StatusTooManyRequests)
+			default:
+				w.WriteHeader(http.StatusOK)
+			}
+		}))
+		defer server.Close()
+
+		delays := []time.Duration{}
+		client := &http.Client{Transport: newTestTransport(3, &delays)}
+		res, err := client.Post(server.URL+"/api/v4/users", "application/json", strings.NewReader("body"))
+		s.Require().NoError(err)
+		res.Body.Close()
+
+		s.Require().Equal(http.StatusOK, res.StatusCode)
+		s.Require().Equal([]string{"body", "body", "body"}, bodies)
+		s.Require().Equal([]time.Duration{2 * time.Second, 5 * time.Second}, delays)
+		s.Require().Len(printer.GetWarningLines(), 2)
+		s.Require().Equal("Request POST /api/v4/users failed: 429 Too Many Requests. Retrying in 2s (1/3)", printer.GetWarningLines()[0])
+	})
+
+	s.Run("should retry server errors of idempotent requests with exponential backoff", func() {
+		printer.Clean()
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		delays := []time.Duration{}
+		client := &http.Client{Transport: newTestTransport(3, &delays)}
+		res, err := client.Get(server.URL)
+		s.Require().NoError(err)
+		res.Body.Close()
+
+		s.Require().Equal(http.StatusServiceUnavailable, res.StatusCode)
+		s.Require().Equal(4, calls)
+		s.Require().Equal([]time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second}, delays)
+	})
+
+	s.Run("should not retry server errors of non idempotent requests", func() {
+		printer.Clean()
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		delays := []time.Duration{}
+		client := &http.Client{Transport: newTestTransport(3, &delays)}
+		res, err := client.Post(server.URL, "application/json", strings.NewReader("body"))
+		s.Require().NoError(err)
+		res.Body.Close()
+
+		s.Require().Equal(http.StatusBadGateway, res.StatusCode)
+		s.Require().Equal(1, calls)
+		s.Require().Empty(delays)
+		s.Require().Empty(printer.GetWarningLines())
+	})
+
+	s.Run("should not retry if retries are disabled", func() {
+		printer.Clean()
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		delays := []time.Duration{}
+		client := &http.Client{Transport: newTestTransport(0, &delays)}
+		res, err := client.Get(server.URL)
+		s.Require().NoError(err)
+		res.Body.Close()
+
+		s.Require().Equal(http.StatusTooManyRequests, res.StatusCode)
+		s.Require().Equal(1, calls)
+	})
+
+	s.Run("should retry the attempts that time out", func() {
+		printer.Clean()
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				time.Sleep(200 * time.Millisecond)
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		delays := []time.Duration{}
+		transport := newTestTransport(3, &delays)
+		transport.timeout = 50 * time.Millisecond
+		client := &http.Client{Transport: transport}
+		res, err := client.Get(server.URL + "/api/v4/system/ping")
+		s.Require().NoError(err)
+		res.Body.Close()
+
+		s.Require().Equal(http.StatusOK, res.StatusCode)
+		s.Require().Equal(2, calls)
+		s.Require().Equal([]time.Duration{500 * time.Millisecond}, delays)
+		s.Require().Equal("Request GET /api/v4/system/ping failed: no response from the server after 50ms. Retrying in 500ms (1/3)", printer.GetWarningLines()[0])
+	})
+
+	s.Run("should not limit the time spent reading the response", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			time.Sleep(200 * time.Millisecond)
+			_, _ = w.Write([]byte("contents"))
+		}))
+		defer server.Close()
+
+		delays := []time.Duration{}
+		transport := newTestTransport(0, &delays)
+		transport.timeout = 50 * time.Millisecond
+		client := &http.Client{Transport: transport}
+		res, err := client.Get(server.URL)
+		s.Require().NoError(err)
+		defer res.Body.Close()
+
+		b, err := ioutil.ReadAll(res.Body)
+		s.Require().NoError(err)
+		s.Require().Equal("contents", string(b))
+	})
+}
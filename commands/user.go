@@ -37,9 +37,12 @@ var UserDeactivateCmd = &cobra.Command{
 	Short: "Deactivate users",
 	Long:  "Deactivate users. Deactivated users are immediately logged out of all sessions and are unable to log back in.",
 	Example: `  user deactivate user@example.com
-  user deactivate username`,
+  user deactivate username
+
+  # Deactivate the users listed in the "email" or "username" column of a CSV file
+  user deactivate --from-file users.csv --failures-file failed.csv`,
 	RunE: withClient(userDeactivateCmdF),
-	Args: cobra.MinimumNArgs(1),
+	Args: argsFromFile(cobra.MinimumNArgs(1)),
 }
 
 var UserCreateCmd = &cobra.Command{
@@ -56,7 +59,10 @@ var UserCreateCmd = &cobra.Command{
   $ mmctl user create --email user@example.com --username userexample --password Password1 --system_admin
 
   # Finally you can verify user on creation if you have enough permissions
-  $ mmctl user create --email user@example.com --username userexample --password Password1 --system_admin --email_verified`,
+  $ mmctl user create --email user@example.com --username userexample --password Password1 --system_admin --email_verified
+
+  # Several users can be created from a CSV or JSON file with columns named after the flags
+  $ mmctl user create --from-file users.csv --failures-file failed.csv`,
 	RunE: withClient(userCreateCmdF),
 }
 
@@ -247,6 +253,11 @@ var MigrateAuthCmd = &cobra.Command{
 }
 
 func init() {
+	addParallelFlag(UserActivateCmd)
+	addParallelFlag(UserDeactivateCmd)
+	addFromFileFlags(UserDeactivateCmd)
+	addFromFileFlags(UserCreateCmd)
+
 	UserCreateCmd.Flags().String("username", "", "Required. Username for the new user account")
 	_ = UserCreateCmd.MarkFlagRequired("username")
 	UserCreateCmd.Flags().String("email", "", "Required. The email address for the new user account")
@@ -263,6 +274,7 @@ func init() {
 	UserCreateCmd.Flags().Bool("disable-welcome-email", false, "Optional. If supplied, the new user will not receive a welcome email. Defaults to false")
 
 	DeleteUsersCmd.Flags().Bool("confirm", false, "Confirm you really want to delete the user and a DB backup has been performed")
+	addParallelFlag(DeleteUsersCmd)
 	DeleteAllUsersCmd.Flags().Bool("confirm", false, "Confirm you really want to delete the user and a DB backup has been performed")
 
 	ListUsersCmd.Flags().Int("page", 0, "Page number to fetch for the list of users")
@@ -350,27 +362,30 @@ Global Flags:
 }
 
 func userActivateCmdF(c client.Client, command *cobra.Command, args []string) error {
-	changeUsersActiveStatus(c, args, true)
+	changeUsersActiveStatus(c, args, true, getParallelWorkers(command))
 
 	return nil
 }
 
-func changeUsersActiveStatus(c client.Client, userArgs []string, active bool) {
-	users, err := getUsersFromArgs(c, userArgs)
+func changeUsersActiveStatus(c client.Client, userArgs []string, active bool, workers int) {
+	users, err := getUsersFromArgsInParallel(c, userArgs, workers)
 	if err != nil {
-		printer.PrintError(err.Error())
+		printItemError(err.Error(), err)
 	}
-	for _, user := range users {
-		if err := changeUserActiveStatus(c, user, active); err != nil {
-			printer.PrintError(err.Error())
+	errs := runParallel(workers, len(users), func(i int) error {
+		return changeUserActiveStatus(c, users[i], active)
+	}, func(i int, err error) {
+		if !active && users[i].IsSSOUser() {
+			printer.Print("You must also deactivate user " + users[i].Id + " in the SSO provider or they will be reactivated on next login or sync.")
 		}
-	}
+		if err != nil {
+			printItemError(err.Error(), err)
+		}
+	})
+	printParallelSummary(workers, len(userArgs), len(userArgs)-len(users)+countErrors(errs))
 }
 
 func changeUserActiveStatus(c client.Client, user *model.User, activate bool) error {
-	if !activate && user.IsSSOUser() {
-		printer.Print("You must also deactivate user " + user.Id + " in the SSO provider or they will be reactivated on next login or sync.")
-	}
 	if _, response := c.UpdateUserActive(user.Id, activate); response.Error != nil {
 		return fmt.Errorf("unable to change activation status of user: %v", user.Id)
 	}
@@ -379,12 +394,20 @@ func changeUserActiveStatus(c client.Client, user *model.User, activate bool) er
 }
 
 func userDeactivateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
-	changeUsersActiveStatus(c, args, false)
+	if path, _ := cmd.Flags().GetString("from-file"); path != "" {
+		return runFromFile(c, cmd, args, [][]string{userColumns}, userDeactivateCmdF)
+	}
+
+	changeUsersActiveStatus(c, args, false, getParallelWorkers(cmd))
 
 	return nil
 }
 
 func userCreateCmdF(c client.Client, cmd *cobra.Command, args []string) error {
+	if path, _ := cmd.Flags().GetString("from-file"); path != "" {
+		return runFromFile(c, cmd, args, nil, userCreateCmdF)
+	}
+
 	printer.SetSingle(true)
 
 	username, erru := cmd.Flags().GetString("username")
@@ -456,7 +479,7 @@ func userInviteCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 		err := inviteUser(c, email, team, args[i+1])
 
 		if err != nil {
-			printer.PrintError(err.Error())
+			printItemError(err.Error(), err)
 		}
 	}
 
@@ -485,11 +508,11 @@ func sendPasswordResetEmailCmdF(c client.Client, cmd *cobra.Command, args []stri
 
 	for _, email := range args {
 		if !model.IsValidEmail(email) {
-			printer.PrintError("Invalid email '" + email + "'")
+			printItemError("Invalid email '"+email+"'", &UsageError{Msg: "invalid email " + email})
 			continue
 		}
 		if _, response := c.SendPasswordResetEmail(email); response.Error != nil {
-			printer.PrintError("Unable send reset password email to email " + email + ". Error: " + response.Error.Error())
+			printItemError("Unable send reset password email to email "+email+". Error: "+response.Error.Error(), response.Error)
 		}
 	}
 
@@ -604,12 +627,12 @@ func resetUserMfaCmdF(c client.Client, cmd *cobra.Command, args []string) error
 
 	users, err := getUsersFromArgs(c, args)
 	if err != nil {
-		printer.PrintError(err.Error())
+		printItemError(err.Error(), err)
 	}
 
 	for _, user := range users {
 		if _, response := c.UpdateUserMfa(user.Id, "", false); response.Error != nil {
-			printer.PrintError("Unable to reset user '" + user.Id + "' MFA. Error: " + response.Error.Error())
+			printItemError("Unable to reset user '"+user.Id+"' MFA. Error: "+response.Error.Error(), response.Error)
 		}
 	}
 
@@ -644,21 +667,27 @@ func deleteUsersCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	users, err := getUsersFromArgs(c, args)
+	workers := getParallelWorkers(cmd)
+	users, err := getUsersFromArgsInParallel(c, args, workers)
 	if err != nil {
-		printer.PrintError(err.Error())
+		printItemError(err.Error(), err)
 	}
-	for i, user := range users {
-		if user == nil {
-			printer.PrintError("Unable to find user '" + args[i] + "'")
-			continue
+	errs := runParallel(workers, len(users), func(i int) error {
+		if users[i] == nil {
+			return errors.New("Unable to find user '" + args[i] + "'")
 		}
-		if _, response := deleteUser(c, user); response.Error != nil {
-			printer.PrintError("Unable to delete user '" + user.Username + "' error: " + response.Error.Error())
-		} else {
-			printer.PrintT("Deleted user '{{.Username}}'", user)
+		if _, response := deleteUser(c, users[i]); response.Error != nil {
+			return errors.New("Unable to delete user '" + users[i].Username + "' error: " + response.Error.Error())
 		}
-	}
+		return nil
+	}, func(i int, err error) {
+		if err != nil {
+			printItemError(err.Error(), err)
+			return
+		}
+		printer.PrintT("Deleted user '{{.Username}}'", users[i])
+	})
+	printParallelSummary(workers, len(args), len(args)-len(users)+countErrors(errs))
 	return nil
 }
 
@@ -697,7 +726,7 @@ func searchUserCmdF(c client.Client, cmd *cobra.Command, args []string) error {
 
 	users, err := getUsersFromArgs(c, args)
 	if err != nil {
-		printer.PrintError(err.Error())
+		printItemError(err.Error(), err)
 	}
 
 	for i, user := range users {
@@ -786,12 +815,12 @@ func listUsersCmdF(c client.Client, command *cobra.Command, args []string) error
 func verifyUserEmailWithoutTokenCmdF(c client.Client, cmd *cobra.Command, userArgs []string) error {
 	users, err := getUsersFromArgs(c, userArgs)
 	if err != nil {
-		printer.PrintError(err.Error())
+		printItemError(err.Error(), err)
 	}
 
 	for _, user := range users {
 		if newUser, resp := c.VerifyUserEmailWithoutToken(user.Id); resp.Error != nil {
-			printer.PrintError(fmt.Sprintf("unable to verify user %s email: %s", user.Id, resp.Error))
+			printItemError(fmt.Sprintf("unable to verify user %s email: %s", user.Id, resp.Error), resp.Error)
 		} else {
 			printer.PrintT("User {{.Username}} verified", newUser)
 		}
@@ -817,12 +846,12 @@ func userConvertCmdF(c client.Client, cmd *cobra.Command, userArgs []string) err
 func convertUserToBot(c client.Client, _ *cobra.Command, userArgs []string) error {
 	users, err := getUsersFromArgs(c, userArgs)
 	if err != nil {
-		printer.PrintError(err.Error())
+		printItemError(err.Error(), err)
 	}
 	for _, user := range users {
 		bot, resp := c.ConvertUserToBot(user.Id)
 		if resp.Error != nil {
-			printer.PrintError(resp.Error.Error())
+			printItemError(resp.Error.Error(), resp.Error)
 			continue
 		}
 
@@ -968,12 +997,12 @@ func migrateAuthToLdapCmdF(c client.Client, cmd *cobra.Command, userArgs []strin
 func promoteGuestToUserCmdF(c client.Client, _ *cobra.Command, userArgs []string) error {
 	for i, user := range getUsersFromUserArgs(c, userArgs) {
 		if user == nil {
-			printer.PrintError(fmt.Sprintf("can't find guest '%v'", userArgs[i]))
+			printItemError(fmt.Sprintf("can't find guest '%v'", userArgs[i]), ErrEntityNotFound{Type: "user", ID: userArgs[i]})
 			continue
 		}
 
 		if _, resp := c.PromoteGuestToUser(user.Id); resp.Error != nil {
-			printer.PrintError(fmt.Sprintf("unable to promote guest %s: %s", userArgs[i], resp.Error))
+			printItemError(fmt.Sprintf("unable to promote guest %s: %s", userArgs[i], resp.Error), resp.Error)
 			continue
 		}
 
@@ -986,12 +1015,12 @@ func promoteGuestToUserCmdF(c client.Client, _ *cobra.Command, userArgs []string
 func demoteUserToGuestCmdF(c client.Client, _ *cobra.Command, userArgs []string) error {
 	for i, user := range getUsersFromUserArgs(c, userArgs) {
 		if user == nil {
-			printer.PrintError(fmt.Sprintf("can't find user '%v'", userArgs[i]))
+			printItemError(fmt.Sprintf("can't find user '%v'", userArgs[i]), ErrEntityNotFound{Type: "user", ID: userArgs[i]})
 			continue
 		}
 
 		if _, resp := c.DemoteUserToGuest(user.Id); resp.Error != nil {
-			printer.PrintError(fmt.Sprintf("unable to demote user %s: %s", userArgs[i], resp.Error))
+			printItemError(fmt.Sprintf("unable to demote user %s: %s", userArgs[i], resp.Error), resp.Error)
 			continue
 		}
 
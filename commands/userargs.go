@@ -15,11 +15,17 @@ import (
 )
 
 func getUsersFromUserArgs(c client.Client, userArgs []string) []*model.User {
-	users := make([]*model.User, 0, len(userArgs))
-	for _, userArg := range userArgs {
-		user := getUserFromUserArg(c, userArg)
-		users = append(users, user)
-	}
+	return getUsersFromUserArgsInParallel(c, userArgs, 1)
+}
+
+// getUsersFromUserArgsInParallel works as getUsersFromUserArgs but
+// fetches up to `workers` users at the same time
+func getUsersFromUserArgsInParallel(c client.Client, userArgs []string, workers int) []*model.User {
+	users := make([]*model.User, len(userArgs))
+	runParallel(workers, len(userArgs), func(i int) error {
+		users[i] = getUserFromUserArg(c, userArgs[i])
+		return nil
+	}, nil)
 	return users
 }
 
@@ -57,15 +63,27 @@ func checkDots(arg string) bool {
 // getUsersFromArgs obtains all the users passed by `userArgs` parameter.
 // It can return users and errors at the same time
 func getUsersFromArgs(c client.Client, userArgs []string) ([]*model.User, error) {
+	return getUsersFromArgsInParallel(c, userArgs, 1)
+}
+
+// getUsersFromArgsInParallel works as getUsersFromArgs but fetches up
+// to `workers` users at the same time
+func getUsersFromArgsInParallel(c client.Client, userArgs []string, workers int) ([]*model.User, error) {
+	found := make([]*model.User, len(userArgs))
+	errs := runParallel(workers, len(userArgs), func(i int) error {
+		user, err := getUserFromArg(c, userArgs[i])
+		found[i] = user
+		return err
+	}, nil)
+
 	users := make([]*model.User, 0, len(userArgs))
 	var result *multierror.Error
-	for _, userArg := range userArgs {
-		user, err := getUserFromArg(c, userArg)
+	for i, err := range errs {
 		if err != nil {
 			result = multierror.Append(result, err)
 			continue
 		}
-		users = append(users, user)
+		users = append(users, found[i])
 	}
 	return users, result.ErrorOrNil()
 }
@@ -100,7 +100,7 @@ func listWebhookCmdF(c client.Client, command *cobra.Command, args []string) err
 
 	for i, team := range teams {
 		if team == nil {
-			printer.PrintError("Unable to find team '" + args[i] + "'")
+			printItemError("Unable to find team '"+args[i]+"'", ErrEntityNotFound{Type: "team", ID: args[i]})
 			continue
 		}
 
@@ -124,7 +124,7 @@ func listWebhookCmdF(c client.Client, command *cobra.Command, args []string) err
 				printer.PrintT("Incoming:\t{{.DisplayName}} ({{.Id}}", hook)
 			}
 		} else {
-			printer.PrintError("Unable to list incoming webhooks for '" + team.Id + "'")
+			printItemError("Unable to list incoming webhooks for '"+team.Id+"'", result.Err)
 		}
 
 		if result := <-outgoingResult; result.Err == nil {
@@ -133,7 +133,7 @@ func listWebhookCmdF(c client.Client, command *cobra.Command, args []string) err
 				printer.PrintT("Outgoing:\t {{.DisplayName}} ({{.Id}})", hook)
 			}
 		} else {
-			printer.PrintError("Unable to list outgoing webhooks for '" + team.Id + "'")
+			printItemError("Unable to list outgoing webhooks for '"+team.Id+"'", result.Err)
 		}
 	}
 
@@ -171,7 +171,7 @@ func createIncomingWebhookCmdF(c client.Client, command *cobra.Command, args []s
 
 	createdIncoming, respIncomingWebhook := c.CreateIncomingWebhook(incomingWebhook)
 	if respIncomingWebhook.Error != nil {
-		printer.PrintError("Unable to create webhook")
+		printItemError("Unable to create webhook", respIncomingWebhook.Error)
 		return respIncomingWebhook.Error
 	}
 
@@ -219,7 +219,7 @@ func modifyIncomingWebhookCmdF(c client.Client, command *cobra.Command, args []s
 
 	var newHook *model.IncomingWebhook
 	if newHook, response = c.UpdateIncomingWebhook(updatedHook); response.Error != nil {
-		printer.PrintError("Unable to modify incoming webhook")
+		printItemError("Unable to modify incoming webhook", response.Error)
 		return response.Error
 	}
 
@@ -284,7 +284,7 @@ func createOutgoingWebhookCmdF(c client.Client, command *cobra.Command, args []s
 
 	createdOutgoing, respWebhookOutgoing := c.CreateOutgoingWebhook(outgoingWebhook)
 	if respWebhookOutgoing.Error != nil {
-		printer.PrintError("Unable to create outgoing webhook")
+		printItemError("Unable to create outgoing webhook", respWebhookOutgoing.Error)
 		return respWebhookOutgoing.Error
 	}
 
@@ -368,7 +368,7 @@ func modifyOutgoingWebhookCmdF(c client.Client, command *cobra.Command, args []s
 	var newHook *model.OutgoingWebhook
 	var response *model.Response
 	if newHook, response = c.UpdateOutgoingWebhook(updatedHook); response.Error != nil {
-		printer.PrintError("Unable to modify outgoing webhook")
+		printItemError("Unable to modify outgoing webhook", response.Error)
 		return response.Error
 	}
 
@@ -383,7 +383,7 @@ func deleteWebhookCmdF(c client.Client, command *cobra.Command, args []string) e
 	if incomingWebhook, response := c.GetIncomingWebhook(webhookID, ""); response.Error == nil {
 		_, respIncomingWebhook := c.DeleteIncomingWebhook(webhookID)
 		if respIncomingWebhook.Error != nil {
-			printer.PrintError("Unable to delete webhook '" + webhookID + "'")
+			printItemError("Unable to delete webhook '"+webhookID+"'", respIncomingWebhook.Error)
 			return respIncomingWebhook.Error
 		}
 		printer.PrintT("Webhook {{.Id}} successfully deleted", incomingWebhook)
@@ -393,7 +393,7 @@ func deleteWebhookCmdF(c client.Client, command *cobra.Command, args []string) e
 	if outgoingWebhook, response := c.GetOutgoingWebhook(webhookID); response.Error == nil {
 		_, respOutgoingWebhook := c.DeleteOutgoingWebhook(webhookID)
 		if respOutgoingWebhook.Error != nil {
-			printer.PrintError("Unable to delete webhook '" + webhookID + "'")
+			printItemError("Unable to delete webhook '"+webhookID+"'", respOutgoingWebhook.Error)
 			return respOutgoingWebhook.Error
 		}
 
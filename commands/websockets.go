@@ -6,7 +6,6 @@ package commands
 import (
 	"fmt"
 
-	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -25,10 +24,6 @@ func websocketCmdF(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	appErr := c.Connect()
-	if appErr != nil {
-		return errors.New(appErr.Error())
-	}
 
 	c.Listen()
 	fmt.Println("Press CTRL+C to exit")
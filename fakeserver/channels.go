@@ -0,0 +1,329 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package fakeserver
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+func (s *Server) registerChannelRoutes() {
+	s.handle(http.MethodPost, "/channels", s.createChannel)
+	s.handle(http.MethodGet, "/channels/{channel_id}", s.getChannel)
+	s.handle(http.MethodDelete, "/channels/{channel_id}", s.deleteChannel)
+	s.handle(http.MethodPut, "/channels/{channel_id}/patch", s.patchChannel)
+	s.handle(http.MethodPut, "/channels/{channel_id}/privacy", s.updateChannelPrivacy)
+	s.handle(http.MethodPost, "/channels/{channel_id}/restore", s.restoreChannel)
+	s.handle(http.MethodPost, "/channels/{channel_id}/move", s.moveChannel)
+	s.handle(http.MethodGet, "/channels/{channel_id}/members", s.getChannelMembers)
+	s.handle(http.MethodPost, "/channels/{channel_id}/members", s.addChannelMember)
+	s.handle(http.MethodGet, "/channels/{channel_id}/members/{user_id}", s.getChannelMember)
+	s.handle(http.MethodDelete, "/channels/{channel_id}/members/{user_id}", s.removeChannelMember)
+	s.handle(http.MethodGet, "/teams/{team_id}/channels", s.getPublicChannelsForTeam)
+	s.handle(http.MethodGet, "/teams/{team_id}/channels/deleted", s.getDeletedChannelsForTeam)
+	s.handle(http.MethodGet, "/teams/{team_id}/channels/private", s.getPrivateChannelsForTeam)
+	s.handle(http.MethodGet, "/teams/{team_id}/channels/name/{channel_name}", s.getChannelByName)
+	s.handle(http.MethodGet, "/users/{user_id}/teams/{team_id}/channels", s.getChannelsForTeamForUser)
+}
+
+// channelOrNotFound returns the channel of the route, writing a not
+// found error if it doesn't exist
+func (s *Server) channelOrNotFound(c *context) *model.Channel {
+	channel := s.store.channel(c.param("channel_id"))
+	if channel == nil {
+		c.notFound("channel")
+	}
+	return channel
+}
+
+func (s *Server) createChannel(c *context) {
+	var channel model.Channel
+	if !c.decode(&channel) {
+		return
+	}
+
+	channel.CreatorId = c.userID
+	created, err := s.store.createChannel(&channel)
+	if err != nil {
+		c.appErr(err)
+		return
+	}
+	if c.userID != "" {
+		member := s.store.addChannelMember(created.Id, c.userID)
+		member.SchemeAdmin = true
+	}
+	c.json(http.StatusCreated, created)
+}
+
+func (s *Server) getChannel(c *context) {
+	if channel := s.channelOrNotFound(c); channel != nil {
+		c.json(http.StatusOK, channel)
+	}
+}
+
+func (s *Server) getChannelByName(c *context) {
+	channel := s.store.channelByName(c.param("team_id"), c.param("channel_name"))
+	if channel == nil || (channel.DeleteAt != 0 && !c.queryBool("include_deleted")) {
+		c.notFound("channel")
+		return
+	}
+	c.json(http.StatusOK, channel)
+}
+
+func (s *Server) deleteChannel(c *context) {
+	channel := s.channelOrNotFound(c)
+	if channel == nil {
+		return
+	}
+
+	if c.queryBool("permanent") {
+		s.store.deleteChannel(channel.Id)
+		c.ok()
+		return
+	}
+
+	if channel.DeleteAt != 0 {
+		c.err(http.StatusBadRequest, "api.channel.delete_channel.deleted.app_error", "The channel has been archived or deleted.")
+		return
+	}
+	channel.DeleteAt = model.GetMillis()
+	c.ok()
+}
+
+func (s *Server) patchChannel(c *context) {
+	channel := s.channelOrNotFound(c)
+	if channel == nil {
+		return
+	}
+
+	var patch model.ChannelPatch
+	if !c.decode(&patch) {
+		return
+	}
+
+	patched := *channel
+	patched.Patch(&patch)
+	if err := patched.IsValid(); err != nil {
+		c.appErr(err)
+		return
+	}
+	if other := s.store.channelByName(patched.TeamId, patched.Name); other != nil && other.Id != channel.Id {
+		c.err(http.StatusBadRequest, "store.sql_channel.update.exists.app_error", "A channel with that name already exists on the same team.")
+		return
+	}
+
+	patched.UpdateAt = model.GetMillis()
+	*channel = patched
+	c.json(http.StatusOK, channel)
+}
+
+func (s *Server) updateChannelPrivacy(c *context) {
+	channel := s.channelOrNotFound(c)
+	if channel == nil {
+		return
+	}
+
+	props := map[string]string{}
+	if !c.decode(&props) {
+		return
+	}
+	if props["privacy"] != model.CHANNEL_OPEN && props["privacy"] != model.CHANNEL_PRIVATE {
+		c.err(http.StatusBadRequest, "api.channel.update_channel_privacy.invalid_privacy.app_error", "Invalid privacy: "+props["privacy"])
+		return
+	}
+	if channel.Name == model.DEFAULT_CHANNEL {
+		c.err(http.StatusBadRequest, "api.channel.update_channel_privacy.default_channel_error", "The default channel cannot be made private.")
+		return
+	}
+
+	channel.Type = props["privacy"]
+	channel.UpdateAt = model.GetMillis()
+	c.json(http.StatusOK, channel)
+}
+
+func (s *Server) restoreChannel(c *context) {
+	channel := s.channelOrNotFound(c)
+	if channel == nil {
+		return
+	}
+
+	if channel.DeleteAt == 0 {
+		c.err(http.StatusBadRequest, "api.channel.restore_channel.restored.app_error", "The channel is not archived.")
+		return
+	}
+	channel.DeleteAt = 0
+	channel.UpdateAt = model.GetMillis()
+	c.json(http.StatusOK, channel)
+}
+
+func (s *Server) moveChannel(c *context) {
+	channel := s.channelOrNotFound(c)
+	if channel == nil {
+		return
+	}
+
+	props := map[string]interface{}{}
+	if !c.decode(&props) {
+		return
+	}
+	teamID, _ := props["team_id"].(string)
+	force, _ := props["force"].(bool)
+
+	if s.store.team(teamID) == nil {
+		c.notFound("team")
+		return
+	}
+	if s.store.channelByName(teamID, channel.Name) != nil {
+		c.err(http.StatusBadRequest, "store.sql_channel.save_channel.exists.app_error", "A channel with that name already exists on the same team.")
+		return
+	}
+
+	// the members of the channel need to be in the new team, unless
+	// the move is forced and they are removed from the channel
+	for _, member := range append([]*model.ChannelMember{}, s.store.channelMembers...) {
+		if member.ChannelId != channel.Id || s.store.teamMember(teamID, member.UserId) != nil {
+			continue
+		}
+		if !force {
+			c.err(http.StatusBadRequest, "app.channel.move_channel.members_do_not_match.error", "Cannot move a channel unless all its members are already members of the destination team.")
+			return
+		}
+		s.store.removeChannelMember(channel.Id, member.UserId)
+	}
+
+	channel.TeamId = teamID
+	channel.UpdateAt = model.GetMillis()
+	c.json(http.StatusOK, channel)
+}
+
+func (s *Server) getChannelMembers(c *context) {
+	channel := s.channelOrNotFound(c)
+	if channel == nil {
+		return
+	}
+
+	members := model.ChannelMembers{}
+	for _, member := range s.store.channelMembers {
+		if member.ChannelId == channel.Id {
+			members = append(members, *member)
+		}
+	}
+
+	page, perPage := c.page()
+	start, end := paginate(len(members), page, perPage)
+	c.json(http.StatusOK, members[start:end])
+}
+
+func (s *Server) addChannelMember(c *context) {
+	channel := s.channelOrNotFound(c)
+	if channel == nil {
+		return
+	}
+
+	props := map[string]string{}
+	if !c.decode(&props) {
+		return
+	}
+	if s.store.user(props["user_id"]) == nil {
+		c.notFound("user")
+		return
+	}
+	if channel.DeleteAt != 0 {
+		c.err(http.StatusBadRequest, "api.channel.add_user_to_channel.deleted.app_error", "The channel has been archived or deleted.")
+		return
+	}
+	if s.store.teamMember(channel.TeamId, props["user_id"]) == nil {
+		c.err(http.StatusForbidden, "api.channel.add_user.to.channel.failed.app_error", "Failed to add user to channel, the user is not a member of the team.")
+		return
+	}
+
+	c.json(http.StatusCreated, s.store.addChannelMember(channel.Id, props["user_id"]))
+}
+
+func (s *Server) getChannelMember(c *context) {
+	channel := s.channelOrNotFound(c)
+	if channel == nil {
+		return
+	}
+
+	member := s.store.channelMember(channel.Id, c.userParam())
+	if member == nil {
+		c.notFound("channel_member")
+		return
+	}
+	c.json(http.StatusOK, member)
+}
+
+func (s *Server) removeChannelMember(c *context) {
+	channel := s.channelOrNotFound(c)
+	if channel == nil {
+		return
+	}
+
+	if !s.store.removeChannelMember(channel.Id, c.userParam()) {
+		c.notFound("channel_member")
+		return
+	}
+	c.ok()
+}
+
+// teamChannels writes the channels of the team of the route that
+// match the filter
+func (s *Server) teamChannels(c *context, filter func(channel *model.Channel) bool) {
+	team := s.teamOrNotFound(c)
+	if team == nil {
+		return
+	}
+
+	channels := []*model.Channel{}
+	for _, channel := range s.store.channels {
+		if channel.TeamId == team.Id && filter(channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	page, perPage := c.page()
+	start, end := paginate(len(channels), page, perPage)
+	c.json(http.StatusOK, channels[start:end])
+}
+
+func (s *Server) getPublicChannelsForTeam(c *context) {
+	s.teamChannels(c, func(channel *model.Channel) bool {
+		return channel.Type == model.CHANNEL_OPEN && channel.DeleteAt == 0
+	})
+}
+
+func (s *Server) getDeletedChannelsForTeam(c *context) {
+	s.teamChannels(c, func(channel *model.Channel) bool {
+		return channel.DeleteAt != 0
+	})
+}
+
+func (s *Server) getPrivateChannelsForTeam(c *context) {
+	s.teamChannels(c, func(channel *model.Channel) bool {
+		return channel.Type == model.CHANNEL_PRIVATE && channel.DeleteAt == 0
+	})
+}
+
+func (s *Server) getChannelsForTeamForUser(c *context) {
+	userID := c.userParam()
+	includeDeleted := c.queryBool("include_deleted")
+
+	team := s.teamOrNotFound(c)
+	if team == nil {
+		return
+	}
+
+	channels := []*model.Channel{}
+	for _, channel := range s.store.channels {
+		if channel.TeamId != team.Id || (channel.DeleteAt != 0 && !includeDeleted) {
+			continue
+		}
+		if s.store.channelMember(channel.Id, userID) != nil {
+			channels = append(channels, channel)
+		}
+	}
+	c.json(http.StatusOK, channels)
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package fakeserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+func (s *Server) registerConfigRoutes() {
+	s.handle(http.MethodGet, "/config", s.getConfig)
+	s.handle(http.MethodPut, "/config", s.updateConfig)
+	s.handle(http.MethodPut, "/config/patch", s.patchConfig)
+	s.handle(http.MethodPost, "/config/reload", s.reloadConfig)
+}
+
+func (s *Server) getConfig(c *context) {
+	c.json(http.StatusOK, s.store.config)
+}
+
+func (s *Server) updateConfig(c *context) {
+	var config model.Config
+	if !c.decode(&config) {
+		return
+	}
+
+	config.SetDefaults()
+	if err := config.IsValid(); err != nil {
+		c.appErr(err)
+		return
+	}
+
+	s.store.config = &config
+	c.json(http.StatusOK, s.store.config)
+}
+
+// patchConfig applies the fields of the patch that aren't null onto a
+// copy of the current configuration
+func (s *Server) patchConfig(c *context) {
+	patch := map[string]interface{}{}
+	if !c.decode(&patch) {
+		return
+	}
+
+	b, err := json.Marshal(removeNulls(patch))
+	if err != nil {
+		c.err(http.StatusBadRequest, "api.context.invalid_body_param.app_error", err.Error())
+		return
+	}
+
+	config := s.store.config.Clone()
+	if err := json.Unmarshal(b, config); err != nil {
+		c.err(http.StatusBadRequest, "api.context.invalid_body_param.app_error", err.Error())
+		return
+	}
+	config.SetDefaults()
+	if appErr := config.IsValid(); appErr != nil {
+		c.appErr(appErr)
+		return
+	}
+
+	s.store.config = config
+	c.json(http.StatusOK, s.store.config)
+}
+
+func (s *Server) reloadConfig(c *context) {
+	c.ok()
+}
+
+// removeNulls returns a copy of a JSON object without the null values,
+// so they don't override the current values when it is unmarshalled
+func removeNulls(object map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range object {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			result[key] = removeNulls(v)
+		default:
+			result[key] = v
+		}
+	}
+	return result
+}
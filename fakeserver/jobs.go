@@ -0,0 +1,86 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package fakeserver
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+func (s *Server) registerJobRoutes() {
+	s.handle(http.MethodGet, "/jobs", s.getJobs)
+	s.handle(http.MethodPost, "/jobs", s.createJob)
+	s.handle(http.MethodGet, "/jobs/type/{job_type}", s.getJobsByType)
+	s.handle(http.MethodGet, "/jobs/{job_id}", s.getJob)
+	s.handle(http.MethodPost, "/jobs/{job_id}/cancel", s.cancelJob)
+}
+
+// pageJobs writes a page of the jobs that match the type, if any, from
+// the newest to the oldest
+func (s *Server) pageJobs(c *context, jobType string) {
+	jobs := []*model.Job{}
+	for i := len(s.store.jobs) - 1; i >= 0; i-- {
+		if jobType == "" || s.store.jobs[i].Type == jobType {
+			jobs = append(jobs, s.store.jobs[i])
+		}
+	}
+
+	page, perPage := c.page()
+	start, end := paginate(len(jobs), page, perPage)
+	c.json(http.StatusOK, jobs[start:end])
+}
+
+func (s *Server) getJobs(c *context) {
+	s.pageJobs(c, "")
+}
+
+func (s *Server) getJobsByType(c *context) {
+	s.pageJobs(c, c.param("job_type"))
+}
+
+func (s *Server) getJob(c *context) {
+	job := s.store.job(c.param("job_id"))
+	if job == nil {
+		c.notFound("job")
+		return
+	}
+	c.json(http.StatusOK, job)
+}
+
+// createJob stores the job as pending. The fake server doesn't run
+// jobs, so they stay pending until they are cancelled
+func (s *Server) createJob(c *context) {
+	var job model.Job
+	if !c.decode(&job) {
+		return
+	}
+
+	job.Id = model.NewId()
+	job.CreateAt = model.GetMillis()
+	job.Status = model.JOB_STATUS_PENDING
+	if err := job.IsValid(); err != nil {
+		c.appErr(err)
+		return
+	}
+
+	s.store.jobs = append(s.store.jobs, &job)
+	c.json(http.StatusCreated, &job)
+}
+
+func (s *Server) cancelJob(c *context) {
+	job := s.store.job(c.param("job_id"))
+	if job == nil {
+		c.notFound("job")
+		return
+	}
+
+	if job.Status != model.JOB_STATUS_PENDING && job.Status != model.JOB_STATUS_IN_PROGRESS {
+		c.err(http.StatusBadRequest, "app.job.update.app_error", "The job can't be cancelled as it has already finished.")
+		return
+	}
+	job.Status = model.JOB_STATUS_CANCELED
+	job.LastActivityAt = model.GetMillis()
+	c.ok()
+}
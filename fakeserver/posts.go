@@ -0,0 +1,83 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package fakeserver
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+func (s *Server) registerPostRoutes() {
+	s.handle(http.MethodPost, "/posts", s.createPost)
+	s.handle(http.MethodGet, "/posts/{post_id}", s.getPost)
+	s.handle(http.MethodGet, "/channels/{channel_id}/posts", s.getPostsForChannel)
+}
+
+func (s *Server) createPost(c *context) {
+	var post model.Post
+	if !c.decode(&post) {
+		return
+	}
+
+	channel := s.store.channel(post.ChannelId)
+	if channel == nil {
+		c.notFound("channel")
+		return
+	}
+	if channel.DeleteAt != 0 {
+		c.err(http.StatusForbidden, "api.post.create_post.can_not_post_to_deleted.error", "Can not post to deleted channel.")
+		return
+	}
+
+	post.Id = ""
+	if c.userID != "" {
+		post.UserId = c.userID
+	}
+	post.PreSave()
+	if err := post.IsValid(model.POST_MESSAGE_MAX_RUNES_V2); err != nil {
+		c.appErr(err)
+		return
+	}
+
+	channel.LastPostAt = post.CreateAt
+	channel.TotalMsgCount++
+	s.store.posts = append(s.store.posts, &post)
+	c.json(http.StatusCreated, &post)
+}
+
+func (s *Server) getPost(c *context) {
+	for _, post := range s.store.posts {
+		if post.Id == c.param("post_id") {
+			c.json(http.StatusOK, post)
+			return
+		}
+	}
+	c.notFound("post")
+}
+
+func (s *Server) getPostsForChannel(c *context) {
+	channel := s.channelOrNotFound(c)
+	if channel == nil {
+		return
+	}
+
+	// the posts are returned from the newest to the oldest
+	posts := []*model.Post{}
+	for i := len(s.store.posts) - 1; i >= 0; i-- {
+		if s.store.posts[i].ChannelId == channel.Id {
+			posts = append(posts, s.store.posts[i])
+		}
+	}
+
+	page, perPage := c.page()
+	start, end := paginate(len(posts), page, perPage)
+
+	list := model.NewPostList()
+	for _, post := range posts[start:end] {
+		list.AddPost(post)
+		list.AddOrder(post.Id)
+	}
+	c.json(http.StatusOK, list)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package fakeserver
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+func (s *Server) registerRoleRoutes() {
+	s.handle(http.MethodPost, "/roles/names", s.getRolesByNames)
+	s.handle(http.MethodGet, "/roles/name/{role_name}", s.getRoleByName)
+	s.handle(http.MethodGet, "/roles/{role_id}", s.getRole)
+	s.handle(http.MethodPut, "/roles/{role_id}/patch", s.patchRole)
+}
+
+func (s *Server) getRolesByNames(c *context) {
+	var names []string
+	if !c.decode(&names) {
+		return
+	}
+
+	roles := []*model.Role{}
+	for _, name := range names {
+		if role := s.store.roleByName(name); role != nil {
+			roles = append(roles, role)
+		}
+	}
+	c.json(http.StatusOK, roles)
+}
+
+func (s *Server) getRoleByName(c *context) {
+	role := s.store.roleByName(c.param("role_name"))
+	if role == nil {
+		c.notFound("role")
+		return
+	}
+	c.json(http.StatusOK, role)
+}
+
+func (s *Server) getRole(c *context) {
+	role := s.store.role(c.param("role_id"))
+	if role == nil {
+		c.notFound("role")
+		return
+	}
+	c.json(http.StatusOK, role)
+}
+
+func (s *Server) patchRole(c *context) {
+	role := s.store.role(c.param("role_id"))
+	if role == nil {
+		c.notFound("role")
+		return
+	}
+
+	var patch model.RolePatch
+	if !c.decode(&patch) {
+		return
+	}
+
+	patched := *role
+	patched.Patch(&patch)
+	if !patched.IsValid() {
+		c.err(http.StatusBadRequest, "api.context.invalid_body_param.app_error", "Invalid permissions in the role patch.")
+		return
+	}
+
+	patched.UpdateAt = model.GetMillis()
+	*role = patched
+	c.json(http.StatusOK, role)
+}
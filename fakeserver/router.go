@@ -0,0 +1,156 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package fakeserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+// handler is the function that serves a route. Public handlers don't
+// need the request to be authenticated
+type handler struct {
+	fn     func(c *context)
+	public bool
+}
+
+// route is an endpoint of the API. Its pattern is split in segments,
+// and the segments between braces match any value and are passed to
+// the handler as parameters
+type route struct {
+	method   string
+	segments []string
+	handler  handler
+}
+
+// context holds the request being served and its parameters
+type context struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	params map[string]string
+	userID string
+	local  bool
+}
+
+func (s *Server) handle(method, pattern string, fn func(c *context)) {
+	s.routes = append(s.routes, route{method, splitPath(pattern), handler{fn: fn}})
+}
+
+func (s *Server) handlePublic(method, pattern string, fn func(c *context)) {
+	s.routes = append(s.routes, route{method, splitPath(pattern), handler{fn: fn, public: true}})
+}
+
+// match returns the handler of the first route that matches the
+// request, so the routes with fixed segments need to be registered
+// before the ones with parameters in the same position
+func (s *Server) match(method, path string) (handler, map[string]string, bool) {
+	segments := splitPath(path)
+
+	for _, rt := range s.routes {
+		if rt.method != method || len(rt.segments) != len(segments) {
+			continue
+		}
+
+		params := map[string]string{}
+		matched := true
+		for i, segment := range rt.segments {
+			if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+				params[segment[1:len(segment)-1]] = segments[i]
+				continue
+			}
+			if segment != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return rt.handler, params, true
+		}
+	}
+
+	return handler{}, nil, false
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// param returns a parameter of the route
+func (c *context) param(name string) string {
+	return c.params[name]
+}
+
+// userParam returns the user ID parameter of the route, resolving
+// "me" to the user of the session
+func (c *context) userParam() string {
+	if userID := c.param("user_id"); userID != model.ME {
+		return userID
+	}
+	if c.local {
+		return model.UploadNoUserID
+	}
+	return c.userID
+}
+
+// page returns the page and per_page query parameters
+func (c *context) page() (int, int) {
+	page, err := strconv.Atoi(c.r.URL.Query().Get("page"))
+	if err != nil || page < 0 {
+		page = 0
+	}
+	perPage, err := strconv.Atoi(c.r.URL.Query().Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = 60
+	}
+	return page, perPage
+}
+
+func (c *context) queryBool(name string) bool {
+	value, _ := strconv.ParseBool(c.r.URL.Query().Get(name))
+	return value
+}
+
+// decode reads the JSON body of the request into v, writing a bad
+// request error if it can't be parsed
+func (c *context) decode(v interface{}) bool {
+	if err := json.NewDecoder(c.r.Body).Decode(v); err != nil {
+		c.err(http.StatusBadRequest, "api.context.invalid_body_param.app_error", "Invalid or missing body in request: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func (c *context) json(status int, v interface{}) {
+	c.w.Header().Set("Content-Type", "application/json")
+	c.w.WriteHeader(status)
+	_ = json.NewEncoder(c.w).Encode(v)
+}
+
+func (c *context) ok() {
+	c.json(http.StatusOK, map[string]string{model.STATUS: model.STATUS_OK})
+}
+
+// err writes an error in the format the client expects
+func (c *context) err(status int, id, message string) {
+	c.json(status, &model.AppError{
+		Id:         id,
+		Message:    message,
+		Where:      c.r.URL.Path,
+		StatusCode: status,
+		RequestId:  c.w.Header().Get(model.HEADER_REQUEST_ID),
+	})
+}
+
+// appErr writes an error returned by the validation of the model
+func (c *context) appErr(err *model.AppError) {
+	c.err(err.StatusCode, err.Id, err.Id)
+}
+
+func (c *context) notFound(entity string) {
+	c.err(http.StatusNotFound, "app."+entity+".missing.app_error", "Unable to find the "+entity+".")
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package fakeserver provides an in-memory implementation of the
+// subset of the Mattermost API v4 that mmctl uses, so the commands can
+// be exercised without a running server or database.
+//
+// The server keeps its state in memory and doesn't check permissions:
+// any authenticated request, and any request received through the unix
+// socket, can perform every action.
+package fakeserver
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+const (
+	apiPrefix = "/api/v4"
+
+	// AdminUsername and AdminPassword are the credentials of the
+	// system admin that the server creates when it starts
+	AdminUsername = "sysadmin"
+	AdminPassword = "Sys@dmin-sample1"
+)
+
+// Server is an in-memory fake of the Mattermost API v4
+type Server struct {
+	// URL is the base URL of the server, set after calling Start
+	URL string
+	// SocketPath is the path of the unix socket, set after calling
+	// StartUnix
+	SocketPath string
+	// Version is sent in the version header of every response
+	Version string
+	// AdminUser is the system admin created with the server
+	AdminUser *model.User
+	// AdminToken is a session token of the system admin
+	AdminToken string
+
+	mu     sync.Mutex
+	store  *store
+	routes []route
+
+	servers []*http.Server
+}
+
+// NewServer creates a server with a system admin, the default roles
+// and the default configuration. The server doesn't listen for
+// requests until Start or StartUnix are called, but it can be used as
+// a http.Handler
+func NewServer() *Server {
+	s := &Server{
+		Version: fmt.Sprintf("%s.%s", model.CurrentVersion, model.BuildNumber),
+		store:   newStore(),
+	}
+	s.registerRoutes()
+
+	admin := &model.User{
+		Username: AdminUsername,
+		Email:    AdminUsername + "@example.com",
+		Password: AdminPassword,
+		Roles:    model.SYSTEM_ADMIN_ROLE_ID + " " + model.SYSTEM_USER_ROLE_ID,
+	}
+	s.AdminUser, _ = s.store.createUser(admin)
+	s.AdminToken = s.store.createSession(s.AdminUser.Id)
+
+	return s
+}
+
+// Start starts listening for HTTP requests on a random local port
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	s.URL = "http://" + listener.Addr().String()
+	s.serve(listener, false)
+	return nil
+}
+
+// StartUnix starts listening for requests on a unix socket, as the
+// server does in local mode. The requests received through the socket
+// don't need to be authenticated
+func (s *Server) StartUnix(path string) error {
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	// mmctl only connects to sockets that can't be accessed by other
+	// users
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return err
+	}
+
+	s.SocketPath = path
+	s.serve(listener, true)
+	return nil
+}
+
+func (s *Server) serve(listener net.Listener, local bool) {
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.serveHTTP(w, r, local)
+	})}
+	s.servers = append(s.servers, server)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+}
+
+// ExpireSession makes a session expire, so the requests authenticated
+// with its token are rejected
+func (s *Server) ExpireSession(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session, ok := s.store.sessions[token]; ok {
+		session.ExpiresAt = model.GetMillis() - 1
+	}
+}
+
+// Close stops the listeners of the server
+func (s *Server) Close() {
+	for _, server := range s.servers {
+		server.Close()
+	}
+	s.servers = nil
+	if s.SocketPath != "" {
+		os.Remove(s.SocketPath)
+	}
+}
+
+// ServeHTTP handles a request as if it was received through the
+// network, so it needs to be authenticated
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.serveHTTP(w, r, false)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request, local bool) {
+	w.Header().Set(model.HEADER_VERSION_ID, s.Version)
+	w.Header().Set(model.HEADER_REQUEST_ID, model.NewId())
+
+	c := &context{w: w, r: r, local: local}
+
+	if !strings.HasPrefix(r.URL.Path, apiPrefix+"/") {
+		c.err(http.StatusNotFound, "api.context.404.app_error", "Sorry, we could not find the page.")
+		return
+	}
+
+	// the requests are handled one at a time, so the handlers can use
+	// the store without further locking
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, params, ok := s.match(r.Method, strings.TrimPrefix(r.URL.Path, apiPrefix))
+	if !ok {
+		c.err(http.StatusNotFound, "api.context.404.app_error", "Sorry, we could not find the page.")
+		return
+	}
+	c.params = params
+
+	if !h.public && !local {
+		userID, ok := s.store.sessionUser(bearerToken(r))
+		if !ok {
+			c.err(http.StatusUnauthorized, "api.context.session_expired.app_error", "Invalid or expired session, please login again.")
+			return
+		}
+		c.userID = userID
+	}
+
+	h.fn(c)
+}
+
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get(model.HEADER_AUTH)
+	if len(header) > len(model.HEADER_BEARER) && strings.EqualFold(header[:len(model.HEADER_BEARER)], model.HEADER_BEARER) {
+		return strings.TrimSpace(header[len(model.HEADER_BEARER):])
+	}
+	if len(header) > len(model.HEADER_TOKEN) && strings.EqualFold(header[:len(model.HEADER_TOKEN)], model.HEADER_TOKEN) {
+		return strings.TrimSpace(header[len(model.HEADER_TOKEN):])
+	}
+	return r.URL.Query().Get("access_token")
+}
@@ -0,0 +1,466 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package fakeserver
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+	"github.com/stretchr/testify/require"
+)
+
+func startServer(t *testing.T) (*Server, *model.Client4) {
+	s := NewServer()
+	require.NoError(t, s.Start())
+
+	client := model.NewAPIv4Client(s.URL)
+	client.SetToken(s.AdminToken)
+	return s, client
+}
+
+func TestServer(t *testing.T) {
+	t.Run("should require a valid session", func(t *testing.T) {
+		s, _ := startServer(t)
+		defer s.Close()
+		client := model.NewAPIv4Client(s.URL)
+
+		_, resp := client.GetMe("")
+		require.NotNil(t, resp.Error)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		status, resp := client.GetPing()
+		require.Nil(t, resp.Error)
+		require.Equal(t, model.STATUS_OK, status)
+
+		user, resp := client.Login(AdminUsername, AdminPassword)
+		require.Nil(t, resp.Error)
+		require.Equal(t, s.AdminUser.Id, user.Id)
+		require.Empty(t, user.Password)
+		require.Equal(t, s.Version, resp.ServerVersion)
+
+		me, resp := client.GetMe("")
+		require.Nil(t, resp.Error)
+		require.Equal(t, s.AdminUser.Id, me.Id)
+	})
+
+	t.Run("should list and expire the sessions", func(t *testing.T) {
+		s, client := startServer(t)
+		defer s.Close()
+
+		sessions, resp := client.GetSessions(s.AdminUser.Id, "")
+		require.Nil(t, resp.Error)
+		require.Len(t, sessions, 1)
+		require.Empty(t, sessions[0].Token)
+		require.Greater(t, sessions[0].ExpiresAt, sessions[0].CreateAt)
+
+		s.ExpireSession(s.AdminToken)
+		_, resp = client.GetMe("")
+		require.NotNil(t, resp.Error)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("should manage users and access tokens", func(t *testing.T) {
+		s, client := startServer(t)
+		defer s.Close()
+
+		user, resp := client.CreateUser(&model.User{Username: "john.doe", Email: "john@example.com", Password: "Password1"})
+		require.Nil(t, resp.Error)
+		require.Equal(t, model.SYSTEM_USER_ROLE_ID, user.Roles)
+
+		_, resp = client.CreateUser(&model.User{Username: "john.doe", Email: "other@example.com", Password: "Password1"})
+		require.NotNil(t, resp.Error)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		byEmail, resp := client.GetUserByEmail("john@example.com", "")
+		require.Nil(t, resp.Error)
+		require.Equal(t, user.Id, byEmail.Id)
+
+		_, resp = client.GetUserByUsername("unknown", "")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		_, resp = client.UpdateUserRoles(user.Id, "system_user system_admin")
+		require.Nil(t, resp.Error)
+		_, resp = client.UpdateUserActive(user.Id, false)
+		require.Nil(t, resp.Error)
+
+		updated, resp := client.GetUser(user.Id, "")
+		require.Nil(t, resp.Error)
+		require.Equal(t, "system_user system_admin", updated.Roles)
+		require.NotZero(t, updated.DeleteAt)
+
+		token, resp := client.CreateUserAccessToken(s.AdminUser.Id, "test token")
+		require.Nil(t, resp.Error)
+		tokenClient := model.NewAPIv4Client(s.URL)
+		tokenClient.SetToken(token.Token)
+		_, resp = tokenClient.GetMe("")
+		require.Nil(t, resp.Error)
+
+		_, resp = client.RevokeUserAccessToken(token.Id)
+		require.Nil(t, resp.Error)
+		_, resp = tokenClient.GetMe("")
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		users, resp := client.GetUsers(0, 10, "")
+		require.Nil(t, resp.Error)
+		require.Len(t, users, 2)
+	})
+
+	t.Run("should manage teams, channels and posts", func(t *testing.T) {
+		s, client := startServer(t)
+		defer s.Close()
+
+		team, resp := client.CreateTeam(&model.Team{Name: "team", DisplayName: "Team", Type: model.TEAM_OPEN})
+		require.Nil(t, resp.Error)
+
+		_, resp = client.GetTeamMember(team.Id, s.AdminUser.Id, "")
+		require.Nil(t, resp.Error)
+
+		townSquare, resp := client.GetChannelByName(model.DEFAULT_CHANNEL, team.Id, "")
+		require.Nil(t, resp.Error)
+		require.Equal(t, team.Id, townSquare.TeamId)
+
+		channel, resp := client.CreateChannel(&model.Channel{TeamId: team.Id, Name: "private", DisplayName: "Private", Type: model.CHANNEL_PRIVATE})
+		require.Nil(t, resp.Error)
+
+		user, _ := client.CreateUser(&model.User{Username: "john.doe", Email: "john@example.com", Password: "Password1"})
+		_, resp = client.AddChannelMember(channel.Id, user.Id)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		_, resp = client.AddTeamMember(team.Id, user.Id)
+		require.Nil(t, resp.Error)
+		_, resp = client.AddChannelMember(channel.Id, user.Id)
+		require.Nil(t, resp.Error)
+
+		members, resp := client.GetChannelMembers(channel.Id, 0, 10, "")
+		require.Nil(t, resp.Error)
+		require.Len(t, *members, 2)
+
+		for _, message := range []string{"first", "second"} {
+			_, resp = client.CreatePost(&model.Post{ChannelId: channel.Id, Message: message})
+			require.Nil(t, resp.Error)
+		}
+		post, resp := client.CreatePost(&model.Post{ChannelId: channel.Id, Message: "third"})
+		require.Nil(t, resp.Error)
+		gotPost, resp := client.GetPost(post.Id, "")
+		require.Nil(t, resp.Error)
+		require.Equal(t, "third", gotPost.Message)
+		require.Equal(t, s.AdminUser.Id, gotPost.UserId)
+
+		posts, resp := client.GetPostsForChannel(channel.Id, 0, 10, "", false)
+		require.Nil(t, resp.Error)
+		require.Len(t, posts.Order, 3)
+		require.Equal(t, "third", posts.Posts[posts.Order[0]].Message)
+
+		_, resp = client.DeleteChannel(channel.Id)
+		require.Nil(t, resp.Error)
+		_, resp = client.GetChannelByName("private", team.Id, "")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		deleted, resp := client.GetChannelByNameIncludeDeleted("private", team.Id, "")
+		require.Nil(t, resp.Error)
+		require.NotZero(t, deleted.DeleteAt)
+
+		publicChannels, resp := client.GetPublicChannelsForTeam(team.Id, 0, 10, "")
+		require.Nil(t, resp.Error)
+		require.Len(t, publicChannels, 2)
+
+		patched, resp := client.PatchTeam(team.Id, &model.TeamPatch{DisplayName: model.NewString("New Name")})
+		require.Nil(t, resp.Error)
+		require.Equal(t, "New Name", patched.DisplayName)
+	})
+
+	t.Run("should update and delete users", func(t *testing.T) {
+		s, client := startServer(t)
+		defer s.Close()
+
+		user, resp := client.CreateUser(&model.User{Username: "john.doe", Email: "john@example.com", Password: "Password1"})
+		require.Nil(t, resp.Error)
+
+		user.Nickname = "Johnny"
+		updated, resp := client.UpdateUser(user)
+		require.Nil(t, resp.Error)
+		require.Equal(t, "Johnny", updated.Nickname)
+		require.Equal(t, model.SYSTEM_USER_ROLE_ID, updated.Roles)
+
+		users, resp := client.GetUsersByIds([]string{user.Id, "unknown"})
+		require.Nil(t, resp.Error)
+		require.Len(t, users, 1)
+		require.Equal(t, "Johnny", users[0].Nickname)
+
+		_, resp = client.UpdateUserPassword(user.Id, "wrong", "Password2")
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		_, resp = client.UpdateUserPassword(user.Id, "Password1", "Password2")
+		require.Nil(t, resp.Error)
+		userClient := model.NewAPIv4Client(s.URL)
+		_, resp = userClient.Login("john.doe", "Password2")
+		require.Nil(t, resp.Error)
+		_, resp = userClient.Logout()
+		require.Nil(t, resp.Error)
+		_, resp = userClient.GetMe("")
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		verified, resp := client.VerifyUserEmailWithoutToken(user.Id)
+		require.Nil(t, resp.Error)
+		require.True(t, verified.EmailVerified)
+
+		_, resp = client.DemoteUserToGuest(user.Id)
+		require.Nil(t, resp.Error)
+		guest, _ := client.GetUser(user.Id, "")
+		require.Equal(t, model.SYSTEM_GUEST_ROLE_ID, guest.Roles)
+		_, resp = client.PromoteGuestToUser(user.Id)
+		require.Nil(t, resp.Error)
+		promoted, _ := client.GetUser(user.Id, "")
+		require.Equal(t, model.SYSTEM_USER_ROLE_ID, promoted.Roles)
+
+		_, resp = client.CreateUserAccessToken(user.Id, "test token")
+		require.Nil(t, resp.Error)
+		tokens, resp := client.GetUserAccessTokensForUser(user.Id, 0, 10)
+		require.Nil(t, resp.Error)
+		require.Len(t, tokens, 1)
+		require.Empty(t, tokens[0].Token)
+
+		_, resp = client.DeleteUser(user.Id)
+		require.Nil(t, resp.Error)
+		deleted, _ := client.GetUser(user.Id, "")
+		require.NotZero(t, deleted.DeleteAt)
+		_, resp = client.PermanentDeleteUser(user.Id)
+		require.Nil(t, resp.Error)
+		_, resp = client.GetUser(user.Id, "")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("should update, archive and delete teams", func(t *testing.T) {
+		s, client := startServer(t)
+		defer s.Close()
+
+		team, resp := client.CreateTeam(&model.Team{Name: "team", DisplayName: "Team", Type: model.TEAM_OPEN})
+		require.Nil(t, resp.Error)
+
+		byID, resp := client.GetTeam(team.Id, "")
+		require.Nil(t, resp.Error)
+		require.Equal(t, "team", byID.Name)
+		byName, resp := client.GetTeamByName("team", "")
+		require.Nil(t, resp.Error)
+		require.Equal(t, team.Id, byName.Id)
+		found, resp := client.SearchTeams(&model.TeamSearch{Term: "TEA"})
+		require.Nil(t, resp.Error)
+		require.Len(t, found, 1)
+
+		team.DisplayName = "Updated"
+		team.Name = "renamed"
+		updated, resp := client.UpdateTeam(team)
+		require.Nil(t, resp.Error)
+		require.Equal(t, "Updated", updated.DisplayName)
+		require.Equal(t, "team", updated.Name)
+
+		private, resp := client.UpdateTeamPrivacy(team.Id, model.TEAM_INVITE)
+		require.Nil(t, resp.Error)
+		require.Equal(t, model.TEAM_INVITE, private.Type)
+		require.False(t, private.AllowOpenInvite)
+
+		user, _ := client.CreateUser(&model.User{Username: "john.doe", Email: "john@example.com", Password: "Password1"})
+		_, resp = client.AddTeamMember(team.Id, user.Id)
+		require.Nil(t, resp.Error)
+		_, resp = client.RemoveTeamMember(team.Id, user.Id)
+		require.Nil(t, resp.Error)
+		_, resp = client.GetTeamMember(team.Id, user.Id, "")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		_, resp = client.SoftDeleteTeam(team.Id)
+		require.Nil(t, resp.Error)
+		teams, resp := client.GetAllTeams("", 0, 10)
+		require.Nil(t, resp.Error)
+		require.Empty(t, teams)
+
+		restored, resp := client.RestoreTeam(team.Id)
+		require.Nil(t, resp.Error)
+		require.Zero(t, restored.DeleteAt)
+		teams, resp = client.GetAllTeams("", 0, 10)
+		require.Nil(t, resp.Error)
+		require.Len(t, teams, 1)
+
+		_, resp = client.PermanentDeleteTeam(team.Id)
+		require.Nil(t, resp.Error)
+		_, resp = client.GetTeam(team.Id, "")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("should update, archive and move channels", func(t *testing.T) {
+		s, client := startServer(t)
+		defer s.Close()
+
+		team, resp := client.CreateTeam(&model.Team{Name: "team", DisplayName: "Team", Type: model.TEAM_OPEN})
+		require.Nil(t, resp.Error)
+		otherTeam, resp := client.CreateTeam(&model.Team{Name: "other", DisplayName: "Other", Type: model.TEAM_OPEN})
+		require.Nil(t, resp.Error)
+		channel, resp := client.CreateChannel(&model.Channel{TeamId: team.Id, Name: "channel", DisplayName: "Channel", Type: model.CHANNEL_OPEN})
+		require.Nil(t, resp.Error)
+
+		got, resp := client.GetChannel(channel.Id, "")
+		require.Nil(t, resp.Error)
+		require.Equal(t, "channel", got.Name)
+
+		patched, resp := client.PatchChannel(channel.Id, &model.ChannelPatch{DisplayName: model.NewString("Patched")})
+		require.Nil(t, resp.Error)
+		require.Equal(t, "Patched", patched.DisplayName)
+
+		private, resp := client.UpdateChannelPrivacy(channel.Id, model.CHANNEL_PRIVATE)
+		require.Nil(t, resp.Error)
+		require.Equal(t, model.CHANNEL_PRIVATE, private.Type)
+		privateChannels, resp := client.GetPrivateChannelsForTeam(team.Id, 0, 10, "")
+		require.Nil(t, resp.Error)
+		require.Len(t, privateChannels, 1)
+
+		user, _ := client.CreateUser(&model.User{Username: "john.doe", Email: "john@example.com", Password: "Password1"})
+		_, resp = client.AddTeamMember(team.Id, user.Id)
+		require.Nil(t, resp.Error)
+		_, resp = client.AddChannelMember(channel.Id, user.Id)
+		require.Nil(t, resp.Error)
+		member, resp := client.GetChannelMember(channel.Id, user.Id, "")
+		require.Nil(t, resp.Error)
+		require.Equal(t, user.Id, member.UserId)
+		userChannels, resp := client.GetChannelsForTeamForUser(team.Id, user.Id, false, "")
+		require.Nil(t, resp.Error)
+		require.Len(t, userChannels, 2)
+
+		// the members must be in the destination team to move the
+		// channel, unless the move is forced
+		_, resp = client.MoveChannel(channel.Id, otherTeam.Id, false)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		moved, resp := client.MoveChannel(channel.Id, otherTeam.Id, true)
+		require.Nil(t, resp.Error)
+		require.Equal(t, otherTeam.Id, moved.TeamId)
+		_, resp = client.GetChannelMember(channel.Id, user.Id, "")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		_, resp = client.RemoveUserFromChannel(channel.Id, s.AdminUser.Id)
+		require.Nil(t, resp.Error)
+		_, resp = client.GetChannelMember(channel.Id, s.AdminUser.Id, "")
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		_, resp = client.DeleteChannel(channel.Id)
+		require.Nil(t, resp.Error)
+		deletedChannels, resp := client.GetDeletedChannelsForTeam(otherTeam.Id, 0, 10, "")
+		require.Nil(t, resp.Error)
+		require.Len(t, deletedChannels, 1)
+
+		restored, resp := client.RestoreChannel(channel.Id)
+		require.Nil(t, resp.Error)
+		require.Zero(t, restored.DeleteAt)
+		deletedChannels, resp = client.GetDeletedChannelsForTeam(otherTeam.Id, 0, 10, "")
+		require.Nil(t, resp.Error)
+		require.Empty(t, deletedChannels)
+	})
+
+	t.Run("should patch the configuration and the roles", func(t *testing.T) {
+		s, client := startServer(t)
+		defer s.Close()
+
+		patch := &model.Config{}
+		patch.TeamSettings.SiteName = model.NewString("Fake")
+		config, resp := client.PatchConfig(patch)
+		require.Nil(t, resp.Error)
+		require.Equal(t, "Fake", *config.TeamSettings.SiteName)
+		require.Equal(t, ":8065", *config.ServiceSettings.ListenAddress)
+
+		role, resp := client.GetRoleByName(model.SYSTEM_USER_ROLE_ID)
+		require.Nil(t, resp.Error)
+
+		permissions := append(role.Permissions, model.PERMISSION_CREATE_TEAM.Id)
+		patchedRole, resp := client.PatchRole(role.Id, &model.RolePatch{Permissions: &permissions})
+		require.Nil(t, resp.Error)
+		require.Contains(t, patchedRole.Permissions, model.PERMISSION_CREATE_TEAM.Id)
+
+		roles, resp := client.GetRolesByNames([]string{model.SYSTEM_ADMIN_ROLE_ID, "unknown"})
+		require.Nil(t, resp.Error)
+		require.Len(t, roles, 1)
+
+		gotRole, resp := client.GetRole(role.Id)
+		require.Nil(t, resp.Error)
+		require.Contains(t, gotRole.Permissions, model.PERMISSION_CREATE_TEAM.Id)
+
+		config, resp = client.GetConfig()
+		require.Nil(t, resp.Error)
+		config.TeamSettings.SiteName = model.NewString("Updated")
+		config, resp = client.UpdateConfig(config)
+		require.Nil(t, resp.Error)
+		require.Equal(t, "Updated", *config.TeamSettings.SiteName)
+
+		reloaded, resp := client.ReloadConfig()
+		require.Nil(t, resp.Error)
+		require.True(t, reloaded)
+		config, resp = client.GetConfig()
+		require.Nil(t, resp.Error)
+		require.Equal(t, "Updated", *config.TeamSettings.SiteName)
+	})
+
+	t.Run("should manage jobs and uploads", func(t *testing.T) {
+		s, client := startServer(t)
+		defer s.Close()
+
+		job, resp := client.CreateJob(&model.Job{Type: model.JOB_TYPE_MESSAGE_EXPORT})
+		require.Nil(t, resp.Error)
+		require.Equal(t, model.JOB_STATUS_PENDING, job.Status)
+
+		_, resp = client.CancelJob(job.Id)
+		require.Nil(t, resp.Error)
+		jobs, resp := client.GetJobsByType(model.JOB_TYPE_MESSAGE_EXPORT, 0, 10)
+		require.Nil(t, resp.Error)
+		require.Len(t, jobs, 1)
+		require.Equal(t, model.JOB_STATUS_CANCELED, jobs[0].Status)
+
+		other, resp := client.CreateJob(&model.Job{Type: model.JOB_TYPE_DATA_RETENTION})
+		require.Nil(t, resp.Error)
+		jobs, resp = client.GetJobs(0, 10)
+		require.Nil(t, resp.Error)
+		require.Len(t, jobs, 2)
+		require.Equal(t, other.Id, jobs[0].Id)
+		gotJob, resp := client.GetJob(job.Id)
+		require.Nil(t, resp.Error)
+		require.Equal(t, model.JOB_STATUS_CANCELED, gotJob.Status)
+
+		us, resp := client.CreateUpload(&model.UploadSession{Filename: "import.zip", FileSize: 4, Type: model.UploadTypeImport, UserId: model.ME})
+		require.Nil(t, resp.Error)
+
+		uploads, resp := client.GetUploadsForUser(model.ME)
+		require.Nil(t, resp.Error)
+		require.Len(t, uploads, 1)
+
+		gotUpload, resp := client.GetUpload(us.Id)
+		require.Nil(t, resp.Error)
+		require.Equal(t, s.AdminUser.Id, gotUpload.UserId)
+
+		info, resp := client.UploadData(us.Id, bytes.NewReader([]byte("data")))
+		require.Nil(t, resp.Error)
+		require.Equal(t, int64(4), info.Size)
+
+		imports, resp := client.ListImports()
+		require.Nil(t, resp.Error)
+		require.Equal(t, []string{us.Id + "_import.zip"}, imports)
+	})
+
+	t.Run("should serve unauthenticated requests through the unix socket", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "fakeserver")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		s := NewServer()
+		socketPath := filepath.Join(dir, "mattermost_local.socket")
+		require.NoError(t, s.StartUnix(socketPath))
+		defer s.Close()
+
+		fi, err := os.Stat(socketPath)
+		require.NoError(t, err)
+		require.Equal(t, os.ModeSocket|0600, fi.Mode())
+
+		client := model.NewAPIv4SocketClient(socketPath)
+		user, resp := client.GetUser(s.AdminUser.Id, "")
+		require.Nil(t, resp.Error)
+		require.Equal(t, AdminUsername, user.Username)
+	})
+}
@@ -0,0 +1,342 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package fakeserver
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+// store keeps the entities of the server. The slices keep the
+// entities in creation order, so the lists and pages are stable
+type store struct {
+	users          []*model.User
+	sessions       map[string]*model.Session
+	tokens         []*model.UserAccessToken
+	teams          []*model.Team
+	teamMembers    []*model.TeamMember
+	channels       []*model.Channel
+	channelMembers []*model.ChannelMember
+	posts          []*model.Post
+	config         *model.Config
+	roles          []*model.Role
+	jobs           []*model.Job
+	uploads        []*model.UploadSession
+	imports        []string
+}
+
+func newStore() *store {
+	config := &model.Config{}
+	config.SetDefaults()
+
+	st := &store{
+		sessions: map[string]*model.Session{},
+		config:   config,
+	}
+
+	for _, role := range model.MakeDefaultRoles() {
+		role.Id = model.NewId()
+		role.CreateAt = model.GetMillis()
+		role.UpdateAt = role.CreateAt
+		st.roles = append(st.roles, role)
+	}
+
+	return st
+}
+
+func (st *store) createSession(userID string) string {
+	session := &model.Session{
+		Id:       model.NewId(),
+		Token:    model.NewId(),
+		UserId:   userID,
+		CreateAt: model.GetMillis(),
+	}
+	session.ExpiresAt = session.CreateAt + int64(*st.config.ServiceSettings.SessionLengthWebInDays)*24*60*60*1000
+	st.sessions[session.Token] = session
+	return session.Token
+}
+
+// userSessions returns the sessions of a user without their tokens,
+// from the oldest to the newest
+func (st *store) userSessions(userID string) []*model.Session {
+	sessions := []*model.Session{}
+	for _, session := range st.sessions {
+		if session.UserId == userID {
+			sanitized := *session
+			sanitized.Sanitize()
+			sessions = append(sessions, &sanitized)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreateAt < sessions[j].CreateAt
+	})
+	return sessions
+}
+
+// sessionUser returns the user of a session or a personal access
+// token
+func (st *store) sessionUser(token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+	if session, ok := st.sessions[token]; ok {
+		return session.UserId, !session.IsExpired()
+	}
+	for _, t := range st.tokens {
+		if t.Token == token && t.IsActive {
+			return t.UserId, true
+		}
+	}
+	return "", false
+}
+
+func (st *store) createUser(user *model.User) (*model.User, *model.AppError) {
+	user.Id = ""
+	user.PreSave()
+	if user.Roles == "" {
+		user.Roles = model.SYSTEM_USER_ROLE_ID
+	}
+	if err := user.IsValid(); err != nil {
+		return nil, err
+	}
+	if st.userByUsername(user.Username) != nil {
+		return nil, model.NewAppError("createUser", "app.user.save.username_exists.app_error", nil, "", http.StatusBadRequest)
+	}
+	if st.userByEmail(user.Email) != nil {
+		return nil, model.NewAppError("createUser", "app.user.save.email_exists.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	st.users = append(st.users, user)
+	return user, nil
+}
+
+func (st *store) user(id string) *model.User {
+	for _, user := range st.users {
+		if user.Id == id {
+			return user
+		}
+	}
+	return nil
+}
+
+func (st *store) userByUsername(username string) *model.User {
+	for _, user := range st.users {
+		if user.Username == strings.ToLower(username) {
+			return user
+		}
+	}
+	return nil
+}
+
+func (st *store) userByEmail(email string) *model.User {
+	for _, user := range st.users {
+		if user.Email == strings.ToLower(email) {
+			return user
+		}
+	}
+	return nil
+}
+
+func (st *store) team(id string) *model.Team {
+	for _, team := range st.teams {
+		if team.Id == id {
+			return team
+		}
+	}
+	return nil
+}
+
+func (st *store) teamByName(name string) *model.Team {
+	for _, team := range st.teams {
+		if team.Name == name {
+			return team
+		}
+	}
+	return nil
+}
+
+func (st *store) teamMember(teamID, userID string) *model.TeamMember {
+	for _, member := range st.teamMembers {
+		if member.TeamId == teamID && member.UserId == userID && member.DeleteAt == 0 {
+			return member
+		}
+	}
+	return nil
+}
+
+func (st *store) addTeamMember(teamID, userID string) *model.TeamMember {
+	if member := st.teamMember(teamID, userID); member != nil {
+		return member
+	}
+
+	member := &model.TeamMember{
+		TeamId:      teamID,
+		UserId:      userID,
+		Roles:       model.TEAM_USER_ROLE_ID,
+		SchemeUser:  true,
+		SchemeGuest: false,
+	}
+	st.teamMembers = append(st.teamMembers, member)
+	return member
+}
+
+func (st *store) removeTeamMember(teamID, userID string) bool {
+	for i, member := range st.teamMembers {
+		if member.TeamId == teamID && member.UserId == userID {
+			st.teamMembers = append(st.teamMembers[:i], st.teamMembers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (st *store) channel(id string) *model.Channel {
+	for _, channel := range st.channels {
+		if channel.Id == id {
+			return channel
+		}
+	}
+	return nil
+}
+
+func (st *store) channelByName(teamID, name string) *model.Channel {
+	for _, channel := range st.channels {
+		if channel.TeamId == teamID && channel.Name == name {
+			return channel
+		}
+	}
+	return nil
+}
+
+func (st *store) createChannel(channel *model.Channel) (*model.Channel, *model.AppError) {
+	channel.Id = ""
+	channel.PreSave()
+	if err := channel.IsValid(); err != nil {
+		return nil, err
+	}
+	if st.team(channel.TeamId) == nil {
+		return nil, model.NewAppError("createChannel", "app.team.get.find.app_error", nil, "", http.StatusNotFound)
+	}
+	if st.channelByName(channel.TeamId, channel.Name) != nil {
+		return nil, model.NewAppError("createChannel", "store.sql_channel.save_channel.exists.app_error", nil, "", http.StatusBadRequest)
+	}
+
+	st.channels = append(st.channels, channel)
+	return channel, nil
+}
+
+func (st *store) channelMember(channelID, userID string) *model.ChannelMember {
+	for _, member := range st.channelMembers {
+		if member.ChannelId == channelID && member.UserId == userID {
+			return member
+		}
+	}
+	return nil
+}
+
+func (st *store) addChannelMember(channelID, userID string) *model.ChannelMember {
+	if member := st.channelMember(channelID, userID); member != nil {
+		return member
+	}
+
+	member := &model.ChannelMember{
+		ChannelId:    channelID,
+		UserId:       userID,
+		Roles:        model.CHANNEL_USER_ROLE_ID,
+		SchemeUser:   true,
+		NotifyProps:  model.GetDefaultChannelNotifyProps(),
+		LastUpdateAt: model.GetMillis(),
+	}
+	st.channelMembers = append(st.channelMembers, member)
+	return member
+}
+
+func (st *store) removeChannelMember(channelID, userID string) bool {
+	for i, member := range st.channelMembers {
+		if member.ChannelId == channelID && member.UserId == userID {
+			st.channelMembers = append(st.channelMembers[:i], st.channelMembers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (st *store) role(id string) *model.Role {
+	for _, role := range st.roles {
+		if role.Id == id {
+			return role
+		}
+	}
+	return nil
+}
+
+func (st *store) roleByName(name string) *model.Role {
+	for _, role := range st.roles {
+		if role.Name == name {
+			return role
+		}
+	}
+	return nil
+}
+
+func (st *store) job(id string) *model.Job {
+	for _, job := range st.jobs {
+		if job.Id == id {
+			return job
+		}
+	}
+	return nil
+}
+
+func (st *store) upload(id string) *model.UploadSession {
+	for _, us := range st.uploads {
+		if us.Id == id {
+			return us
+		}
+	}
+	return nil
+}
+
+// paginate returns the bounds of a page for a list of n elements
+func paginate(n, page, perPage int) (int, int) {
+	start := page * perPage
+	if start > n {
+		start = n
+	}
+	end := start + perPage
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+// deleteChannel removes a channel with its members and posts
+func (st *store) deleteChannel(id string) {
+	channels := []*model.Channel{}
+	for _, channel := range st.channels {
+		if channel.Id != id {
+			channels = append(channels, channel)
+		}
+	}
+	st.channels = channels
+
+	members := []*model.ChannelMember{}
+	for _, member := range st.channelMembers {
+		if member.ChannelId != id {
+			members = append(members, member)
+		}
+	}
+	st.channelMembers = members
+
+	posts := []*model.Post{}
+	for _, post := range st.posts {
+		if post.ChannelId != id {
+			posts = append(posts, post)
+		}
+	}
+	st.posts = posts
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package fakeserver
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+// registerRoutes registers the handlers of every endpoint. The routes
+// of each group are registered with the fixed segments first, as the
+// first route that matches a request serves it
+func (s *Server) registerRoutes() {
+	s.handlePublic(http.MethodGet, "/system/ping", s.ping)
+	s.registerUserRoutes()
+	s.registerUploadRoutes()
+	s.registerChannelRoutes()
+	s.registerTeamRoutes()
+	s.registerPostRoutes()
+	s.registerConfigRoutes()
+	s.registerRoleRoutes()
+	s.registerJobRoutes()
+}
+
+func (s *Server) ping(c *context) {
+	status := map[string]string{model.STATUS: model.STATUS_OK}
+	if c.queryBool("get_server_status") {
+		status["database_status"] = model.STATUS_OK
+		status["filestore_status"] = model.STATUS_OK
+	}
+	c.json(http.StatusOK, status)
+}
@@ -0,0 +1,302 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package fakeserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+func (s *Server) registerTeamRoutes() {
+	s.handle(http.MethodPost, "/teams", s.createTeam)
+	s.handle(http.MethodGet, "/teams", s.getTeams)
+	s.handle(http.MethodPost, "/teams/search", s.searchTeams)
+	s.handle(http.MethodGet, "/teams/name/{team_name}", s.getTeamByName)
+	s.handle(http.MethodGet, "/teams/{team_id}", s.getTeam)
+	s.handle(http.MethodPut, "/teams/{team_id}", s.updateTeam)
+	s.handle(http.MethodDelete, "/teams/{team_id}", s.deleteTeam)
+	s.handle(http.MethodPut, "/teams/{team_id}/patch", s.patchTeam)
+	s.handle(http.MethodPut, "/teams/{team_id}/privacy", s.updateTeamPrivacy)
+	s.handle(http.MethodPost, "/teams/{team_id}/restore", s.restoreTeam)
+	s.handle(http.MethodPost, "/teams/{team_id}/members", s.addTeamMember)
+	s.handle(http.MethodGet, "/teams/{team_id}/members/{user_id}", s.getTeamMember)
+	s.handle(http.MethodDelete, "/teams/{team_id}/members/{user_id}", s.removeTeamMember)
+}
+
+// teamOrNotFound returns the team of the route, writing a not found
+// error if it doesn't exist
+func (s *Server) teamOrNotFound(c *context) *model.Team {
+	team := s.store.team(c.param("team_id"))
+	if team == nil {
+		c.notFound("team")
+	}
+	return team
+}
+
+func (s *Server) createTeam(c *context) {
+	var team model.Team
+	if !c.decode(&team) {
+		return
+	}
+
+	team.Id = ""
+	team.PreSave()
+	if err := team.IsValid(); err != nil {
+		c.appErr(err)
+		return
+	}
+	if s.store.teamByName(team.Name) != nil {
+		c.err(http.StatusBadRequest, "app.team.save.existing.app_error", "A team with that name already exists.")
+		return
+	}
+	s.store.teams = append(s.store.teams, &team)
+
+	// as the server does, the team is created with its default
+	// channels and the creator as a member of them
+	for _, name := range []string{model.DEFAULT_CHANNEL, "off-topic"} {
+		channel, err := s.store.createChannel(&model.Channel{
+			TeamId:      team.Id,
+			Name:        name,
+			DisplayName: strings.Title(strings.Replace(name, "-", " ", -1)),
+			Type:        model.CHANNEL_OPEN,
+			CreatorId:   c.userID,
+		})
+		if err == nil && c.userID != "" {
+			s.store.addChannelMember(channel.Id, c.userID)
+		}
+	}
+	if c.userID != "" {
+		member := s.store.addTeamMember(team.Id, c.userID)
+		member.SchemeAdmin = true
+	}
+
+	c.json(http.StatusCreated, &team)
+}
+
+func (s *Server) getTeams(c *context) {
+	teams := []*model.Team{}
+	for _, team := range s.store.teams {
+		if team.DeleteAt == 0 {
+			teams = append(teams, team)
+		}
+	}
+
+	page, perPage := c.page()
+	start, end := paginate(len(teams), page, perPage)
+	c.json(http.StatusOK, teams[start:end])
+}
+
+func (s *Server) searchTeams(c *context) {
+	var search model.TeamSearch
+	if !c.decode(&search) {
+		return
+	}
+
+	term := strings.ToLower(search.Term)
+	teams := []*model.Team{}
+	for _, team := range s.store.teams {
+		if strings.Contains(team.Name, term) || strings.Contains(strings.ToLower(team.DisplayName), term) {
+			teams = append(teams, team)
+		}
+	}
+	c.json(http.StatusOK, teams)
+}
+
+func (s *Server) getTeam(c *context) {
+	if team := s.teamOrNotFound(c); team != nil {
+		c.json(http.StatusOK, team)
+	}
+}
+
+func (s *Server) getTeamByName(c *context) {
+	team := s.store.teamByName(c.param("team_name"))
+	if team == nil {
+		c.notFound("team")
+		return
+	}
+	c.json(http.StatusOK, team)
+}
+
+func (s *Server) updateTeam(c *context) {
+	team := s.teamOrNotFound(c)
+	if team == nil {
+		return
+	}
+
+	var update model.Team
+	if !c.decode(&update) {
+		return
+	}
+
+	// the name and the state of the team can't be changed through
+	// this endpoint
+	update.Id = team.Id
+	update.Name = team.Name
+	update.CreateAt = team.CreateAt
+	update.DeleteAt = team.DeleteAt
+	update.UpdateAt = model.GetMillis()
+	if err := update.IsValid(); err != nil {
+		c.appErr(err)
+		return
+	}
+
+	*team = update
+	c.json(http.StatusOK, team)
+}
+
+func (s *Server) patchTeam(c *context) {
+	team := s.teamOrNotFound(c)
+	if team == nil {
+		return
+	}
+
+	var patch model.TeamPatch
+	if !c.decode(&patch) {
+		return
+	}
+
+	patched := *team
+	patched.Patch(&patch)
+	if err := patched.IsValid(); err != nil {
+		c.appErr(err)
+		return
+	}
+
+	patched.UpdateAt = model.GetMillis()
+	*team = patched
+	c.json(http.StatusOK, team)
+}
+
+func (s *Server) updateTeamPrivacy(c *context) {
+	team := s.teamOrNotFound(c)
+	if team == nil {
+		return
+	}
+
+	props := map[string]string{}
+	if !c.decode(&props) {
+		return
+	}
+
+	switch props["privacy"] {
+	case model.TEAM_OPEN:
+		team.Type = model.TEAM_OPEN
+		team.AllowOpenInvite = true
+	case model.TEAM_INVITE:
+		team.Type = model.TEAM_INVITE
+		team.AllowOpenInvite = false
+	default:
+		c.err(http.StatusBadRequest, "api.team.update_team_privacy.invalid_privacy.app_error", "Invalid privacy: "+props["privacy"])
+		return
+	}
+
+	team.UpdateAt = model.GetMillis()
+	c.json(http.StatusOK, team)
+}
+
+func (s *Server) deleteTeam(c *context) {
+	team := s.teamOrNotFound(c)
+	if team == nil {
+		return
+	}
+
+	if !c.queryBool("permanent") {
+		team.DeleteAt = model.GetMillis()
+		c.ok()
+		return
+	}
+
+	teams := []*model.Team{}
+	for _, t := range s.store.teams {
+		if t.Id != team.Id {
+			teams = append(teams, t)
+		}
+	}
+	s.store.teams = teams
+
+	members := []*model.TeamMember{}
+	for _, member := range s.store.teamMembers {
+		if member.TeamId != team.Id {
+			members = append(members, member)
+		}
+	}
+	s.store.teamMembers = members
+
+	for _, channel := range append([]*model.Channel{}, s.store.channels...) {
+		if channel.TeamId == team.Id {
+			s.store.deleteChannel(channel.Id)
+		}
+	}
+
+	c.ok()
+}
+
+func (s *Server) restoreTeam(c *context) {
+	team := s.teamOrNotFound(c)
+	if team == nil {
+		return
+	}
+
+	team.DeleteAt = 0
+	team.UpdateAt = model.GetMillis()
+	c.json(http.StatusOK, team)
+}
+
+func (s *Server) addTeamMember(c *context) {
+	team := s.teamOrNotFound(c)
+	if team == nil {
+		return
+	}
+
+	var member model.TeamMember
+	if !c.decode(&member) {
+		return
+	}
+	if s.store.user(member.UserId) == nil {
+		c.notFound("user")
+		return
+	}
+
+	added := s.store.addTeamMember(team.Id, member.UserId)
+	// the users join the default channel of the team
+	if channel := s.store.channelByName(team.Id, model.DEFAULT_CHANNEL); channel != nil {
+		s.store.addChannelMember(channel.Id, member.UserId)
+	}
+	c.json(http.StatusCreated, added)
+}
+
+func (s *Server) getTeamMember(c *context) {
+	team := s.teamOrNotFound(c)
+	if team == nil {
+		return
+	}
+
+	member := s.store.teamMember(team.Id, c.userParam())
+	if member == nil {
+		c.notFound("team_member")
+		return
+	}
+	c.json(http.StatusOK, member)
+}
+
+func (s *Server) removeTeamMember(c *context) {
+	team := s.teamOrNotFound(c)
+	if team == nil {
+		return
+	}
+
+	userID := c.userParam()
+	if !s.store.removeTeamMember(team.Id, userID) {
+		c.notFound("team_member")
+		return
+	}
+	for _, channel := range s.store.channels {
+		if channel.TeamId == team.Id {
+			s.store.removeChannelMember(channel.Id, userID)
+		}
+	}
+	c.ok()
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package fakeserver
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+func (s *Server) registerUploadRoutes() {
+	s.handle(http.MethodPost, "/uploads", s.createUpload)
+	s.handle(http.MethodGet, "/uploads/{upload_id}", s.getUpload)
+	s.handle(http.MethodPost, "/uploads/{upload_id}", s.uploadData)
+	s.handle(http.MethodGet, "/users/{user_id}/uploads", s.getUploadsForUser)
+	s.handle(http.MethodGet, "/imports", s.listImports)
+}
+
+func (s *Server) createUpload(c *context) {
+	var us model.UploadSession
+	if !c.decode(&us) {
+		return
+	}
+
+	us.Id = ""
+	us.CreateAt = 0
+	us.FileOffset = 0
+	us.PreSave()
+	us.Path = filepath.Join("data", "uploads", us.Id)
+	if c.local {
+		us.UserId = model.UploadNoUserID
+	} else {
+		us.UserId = c.userID
+	}
+	if err := us.IsValid(); err != nil {
+		c.appErr(err)
+		return
+	}
+
+	s.store.uploads = append(s.store.uploads, &us)
+	c.json(http.StatusCreated, &us)
+}
+
+func (s *Server) getUpload(c *context) {
+	us := s.store.upload(c.param("upload_id"))
+	if us == nil {
+		c.notFound("upload")
+		return
+	}
+	c.json(http.StatusOK, us)
+}
+
+func (s *Server) getUploadsForUser(c *context) {
+	userID := c.userParam()
+
+	uploads := []*model.UploadSession{}
+	for _, us := range s.store.uploads {
+		if us.UserId == userID {
+			uploads = append(uploads, us)
+		}
+	}
+	c.json(http.StatusOK, uploads)
+}
+
+// uploadData appends the body of the request to an upload. The data is
+// discarded, and once the upload completes the file is listed as an
+// import if it was uploaded as one
+func (s *Server) uploadData(c *context) {
+	us := s.store.upload(c.param("upload_id"))
+	if us == nil {
+		c.notFound("upload")
+		return
+	}
+
+	n, err := io.Copy(ioutil.Discard, io.LimitReader(c.r.Body, us.FileSize-us.FileOffset))
+	if err != nil {
+		c.err(http.StatusBadRequest, "api.upload.upload_data.read_body.app_error", err.Error())
+		return
+	}
+	us.FileOffset += n
+
+	if us.FileOffset < us.FileSize {
+		c.w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	info := &model.FileInfo{
+		Id:        model.NewId(),
+		CreatorId: us.UserId,
+		ChannelId: us.ChannelId,
+		CreateAt:  model.GetMillis(),
+		Name:      us.Filename,
+		Extension: strings.TrimPrefix(filepath.Ext(us.Filename), "."),
+		Size:      us.FileSize,
+	}
+	if us.Type == model.UploadTypeImport {
+		info.Name = us.Id + "_" + us.Filename
+		s.store.imports = append(s.store.imports, info.Name)
+	}
+
+	uploads := []*model.UploadSession{}
+	for _, other := range s.store.uploads {
+		if other.Id != us.Id {
+			uploads = append(uploads, other)
+		}
+	}
+	s.store.uploads = uploads
+
+	c.json(http.StatusCreated, info)
+}
+
+func (s *Server) listImports(c *context) {
+	c.json(http.StatusOK, append([]string{}, s.store.imports...))
+}
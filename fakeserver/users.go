@@ -0,0 +1,390 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package fakeserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v5/model"
+)
+
+func (s *Server) registerUserRoutes() {
+	s.handlePublic(http.MethodPost, "/users/login", s.login)
+	s.handle(http.MethodPost, "/users/logout", s.logout)
+	s.handle(http.MethodPost, "/users", s.createUser)
+	s.handle(http.MethodGet, "/users", s.getUsers)
+	s.handle(http.MethodPost, "/users/ids", s.getUsersByIds)
+	s.handle(http.MethodPost, "/users/tokens/revoke", s.revokeUserAccessToken)
+	s.handle(http.MethodGet, "/users/username/{username}", s.getUserByUsername)
+	s.handle(http.MethodGet, "/users/email/{email}", s.getUserByEmail)
+	s.handle(http.MethodGet, "/users/{user_id}", s.getUser)
+	s.handle(http.MethodPut, "/users/{user_id}", s.updateUser)
+	s.handle(http.MethodDelete, "/users/{user_id}", s.deleteUser)
+	s.handle(http.MethodPut, "/users/{user_id}/roles", s.updateUserRoles)
+	s.handle(http.MethodPut, "/users/{user_id}/active", s.updateUserActive)
+	s.handle(http.MethodPut, "/users/{user_id}/password", s.updateUserPassword)
+	s.handle(http.MethodPost, "/users/{user_id}/email/verify/member", s.verifyUserEmail)
+	s.handle(http.MethodPost, "/users/{user_id}/promote", s.promoteGuestToUser)
+	s.handle(http.MethodPost, "/users/{user_id}/demote", s.demoteUserToGuest)
+	s.handle(http.MethodPost, "/users/{user_id}/tokens", s.createUserAccessToken)
+	s.handle(http.MethodGet, "/users/{user_id}/tokens", s.getUserAccessTokens)
+	s.handle(http.MethodGet, "/users/{user_id}/sessions", s.getUserSessions)
+}
+
+// sanitizeUser returns a copy of the user without its password
+func sanitizeUser(user *model.User) *model.User {
+	sanitized := *user
+	sanitized.Password = ""
+	return &sanitized
+}
+
+func sanitizeUsers(users []*model.User) []*model.User {
+	sanitized := make([]*model.User, len(users))
+	for i, user := range users {
+		sanitized[i] = sanitizeUser(user)
+	}
+	return sanitized
+}
+
+func (s *Server) login(c *context) {
+	props := map[string]string{}
+	if !c.decode(&props) {
+		return
+	}
+
+	user := s.store.userByUsername(props["login_id"])
+	if user == nil {
+		user = s.store.userByEmail(props["login_id"])
+	}
+	if user == nil || user.DeleteAt != 0 || !model.ComparePassword(user.Password, props["password"]) {
+		c.err(http.StatusUnauthorized, "api.user.login.invalid_credentials_email_username", "Enter a valid email or username and/or password.")
+		return
+	}
+
+	c.w.Header().Set(model.HEADER_TOKEN, s.store.createSession(user.Id))
+	c.json(http.StatusOK, sanitizeUser(user))
+}
+
+func (s *Server) logout(c *context) {
+	delete(s.store.sessions, bearerToken(c.r))
+	c.ok()
+}
+
+func (s *Server) getUserSessions(c *context) {
+	c.json(http.StatusOK, s.store.userSessions(c.userParam()))
+}
+
+func (s *Server) createUser(c *context) {
+	var user model.User
+	if !c.decode(&user) {
+		return
+	}
+
+	created, err := s.store.createUser(&user)
+	if err != nil {
+		c.appErr(err)
+		return
+	}
+	c.json(http.StatusCreated, sanitizeUser(created))
+}
+
+func (s *Server) getUsers(c *context) {
+	teamID := c.r.URL.Query().Get("in_team")
+
+	users := []*model.User{}
+	for _, user := range s.store.users {
+		if teamID != "" && s.store.teamMember(teamID, user.Id) == nil {
+			continue
+		}
+		users = append(users, user)
+	}
+
+	page, perPage := c.page()
+	start, end := paginate(len(users), page, perPage)
+	c.json(http.StatusOK, sanitizeUsers(users[start:end]))
+}
+
+func (s *Server) getUsersByIds(c *context) {
+	var ids []string
+	if !c.decode(&ids) {
+		return
+	}
+
+	users := []*model.User{}
+	for _, id := range ids {
+		if user := s.store.user(id); user != nil {
+			users = append(users, user)
+		}
+	}
+	c.json(http.StatusOK, sanitizeUsers(users))
+}
+
+// userOrNotFound returns the user of the route, writing a not found
+// error if it doesn't exist
+func (s *Server) userOrNotFound(c *context) *model.User {
+	user := s.store.user(c.userParam())
+	if user == nil {
+		c.notFound("user")
+	}
+	return user
+}
+
+func (s *Server) getUser(c *context) {
+	if user := s.userOrNotFound(c); user != nil {
+		c.json(http.StatusOK, sanitizeUser(user))
+	}
+}
+
+func (s *Server) getUserByUsername(c *context) {
+	user := s.store.userByUsername(c.param("username"))
+	if user == nil {
+		c.notFound("user")
+		return
+	}
+	c.json(http.StatusOK, sanitizeUser(user))
+}
+
+func (s *Server) getUserByEmail(c *context) {
+	user := s.store.userByEmail(c.param("email"))
+	if user == nil {
+		c.notFound("user")
+		return
+	}
+	c.json(http.StatusOK, sanitizeUser(user))
+}
+
+func (s *Server) updateUser(c *context) {
+	user := s.userOrNotFound(c)
+	if user == nil {
+		return
+	}
+
+	var update model.User
+	if !c.decode(&update) {
+		return
+	}
+
+	// the fields managed through their own endpoints are kept
+	update.Id = user.Id
+	update.Password = user.Password
+	update.Roles = user.Roles
+	update.CreateAt = user.CreateAt
+	update.DeleteAt = user.DeleteAt
+	update.Username = model.NormalizeUsername(update.Username)
+	update.Email = model.NormalizeEmail(update.Email)
+	if err := update.IsValid(); err != nil {
+		c.appErr(err)
+		return
+	}
+	if other := s.store.userByUsername(update.Username); other != nil && other.Id != user.Id {
+		c.err(http.StatusBadRequest, "app.user.save.username_exists.app_error", "An account with that username already exists.")
+		return
+	}
+	if other := s.store.userByEmail(update.Email); other != nil && other.Id != user.Id {
+		c.err(http.StatusBadRequest, "app.user.save.email_exists.app_error", "An account with that email already exists.")
+		return
+	}
+
+	update.UpdateAt = model.GetMillis()
+	*user = update
+	c.json(http.StatusOK, sanitizeUser(user))
+}
+
+func (s *Server) deleteUser(c *context) {
+	user := s.userOrNotFound(c)
+	if user == nil {
+		return
+	}
+
+	if c.queryBool("permanent") {
+		for i, u := range s.store.users {
+			if u.Id == user.Id {
+				s.store.users = append(s.store.users[:i], s.store.users[i+1:]...)
+				break
+			}
+		}
+	} else {
+		user.DeleteAt = model.GetMillis()
+	}
+	c.ok()
+}
+
+func (s *Server) updateUserRoles(c *context) {
+	user := s.userOrNotFound(c)
+	if user == nil {
+		return
+	}
+
+	props := map[string]string{}
+	if !c.decode(&props) {
+		return
+	}
+	for _, name := range strings.Fields(props["roles"]) {
+		if s.store.roleByName(name) == nil {
+			c.err(http.StatusBadRequest, "api.user.update_user_roles.bad_role.app_error", "Invalid role: "+name)
+			return
+		}
+	}
+
+	user.Roles = strings.Join(strings.Fields(props["roles"]), " ")
+	user.UpdateAt = model.GetMillis()
+	c.ok()
+}
+
+func (s *Server) updateUserActive(c *context) {
+	user := s.userOrNotFound(c)
+	if user == nil {
+		return
+	}
+
+	props := map[string]interface{}{}
+	if !c.decode(&props) {
+		return
+	}
+	active, ok := props["active"].(bool)
+	if !ok {
+		c.err(http.StatusBadRequest, "api.context.invalid_body_param.app_error", "Invalid or missing active in request body.")
+		return
+	}
+
+	if active {
+		user.DeleteAt = 0
+	} else if user.DeleteAt == 0 {
+		user.DeleteAt = model.GetMillis()
+	}
+	c.ok()
+}
+
+func (s *Server) updateUserPassword(c *context) {
+	user := s.userOrNotFound(c)
+	if user == nil {
+		return
+	}
+
+	props := map[string]string{}
+	if !c.decode(&props) {
+		return
+	}
+
+	switch {
+	case props["already_hashed"] == "true":
+		user.Password = props["new_password"]
+	case props["current_password"] != "" && !model.ComparePassword(user.Password, props["current_password"]):
+		c.err(http.StatusBadRequest, "api.user.check_user_password.invalid.app_error", "Login failed because of invalid password.")
+		return
+	case props["new_password"] == "":
+		c.err(http.StatusBadRequest, "api.context.invalid_body_param.app_error", "Invalid or missing new_password in request body.")
+		return
+	default:
+		user.Password = model.HashPassword(props["new_password"])
+	}
+
+	user.LastPasswordUpdate = model.GetMillis()
+	c.ok()
+}
+
+func (s *Server) verifyUserEmail(c *context) {
+	user := s.userOrNotFound(c)
+	if user == nil {
+		return
+	}
+
+	user.EmailVerified = true
+	c.json(http.StatusOK, sanitizeUser(user))
+}
+
+func (s *Server) promoteGuestToUser(c *context) {
+	s.replaceUserRole(c, model.SYSTEM_GUEST_ROLE_ID, model.SYSTEM_USER_ROLE_ID)
+}
+
+func (s *Server) demoteUserToGuest(c *context) {
+	s.replaceUserRole(c, model.SYSTEM_USER_ROLE_ID, model.SYSTEM_GUEST_ROLE_ID)
+}
+
+func (s *Server) replaceUserRole(c *context, from, to string) {
+	user := s.userOrNotFound(c)
+	if user == nil {
+		return
+	}
+
+	roles := strings.Fields(user.Roles)
+	replaced := false
+	for i, role := range roles {
+		if role == from {
+			roles[i] = to
+			replaced = true
+		}
+	}
+	if !replaced {
+		c.err(http.StatusBadRequest, "api.user.update_user_roles.bad_role.app_error", "The user doesn't have the "+from+" role.")
+		return
+	}
+
+	user.Roles = strings.Join(roles, " ")
+	c.ok()
+}
+
+func (s *Server) createUserAccessToken(c *context) {
+	user := s.userOrNotFound(c)
+	if user == nil {
+		return
+	}
+
+	props := map[string]string{}
+	if !c.decode(&props) {
+		return
+	}
+
+	token := &model.UserAccessToken{
+		Id:          model.NewId(),
+		Token:       model.NewId(),
+		UserId:      user.Id,
+		Description: props["description"],
+		IsActive:    true,
+	}
+	if err := token.IsValid(); err != nil {
+		c.appErr(err)
+		return
+	}
+
+	s.store.tokens = append(s.store.tokens, token)
+	c.json(http.StatusOK, token)
+}
+
+func (s *Server) getUserAccessTokens(c *context) {
+	user := s.userOrNotFound(c)
+	if user == nil {
+		return
+	}
+
+	tokens := []*model.UserAccessToken{}
+	for _, token := range s.store.tokens {
+		if token.UserId == user.Id {
+			sanitized := *token
+			sanitized.Token = ""
+			tokens = append(tokens, &sanitized)
+		}
+	}
+
+	page, perPage := c.page()
+	start, end := paginate(len(tokens), page, perPage)
+	c.json(http.StatusOK, tokens[start:end])
+}
+
+func (s *Server) revokeUserAccessToken(c *context) {
+	props := map[string]string{}
+	if !c.decode(&props) {
+		return
+	}
+
+	for i, token := range s.store.tokens {
+		if token.Id == props["token_id"] {
+			s.store.tokens = append(s.store.tokens[:i], s.store.tokens[i+1:]...)
+			c.ok()
+			return
+		}
+	}
+	c.notFound("user_access_token")
+}
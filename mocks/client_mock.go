@@ -263,6 +263,21 @@ func (mr *MockClientMockRecorder) CreatePost(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockClient)(nil).CreatePost), arg0)
 }
 
+// CreateScheme mocks base method
+func (m *MockClient) CreateScheme(arg0 *model.Scheme) (*model.Scheme, *model.Response) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheme", arg0)
+	ret0, _ := ret[0].(*model.Scheme)
+	ret1, _ := ret[1].(*model.Response)
+	return ret0, ret1
+}
+
+// CreateScheme indicates an expected call of CreateScheme
+func (mr *MockClientMockRecorder) CreateScheme(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheme", reflect.TypeOf((*MockClient)(nil).CreateScheme), arg0)
+}
+
 // CreateTeam mocks base method
 func (m *MockClient) CreateTeam(arg0 *model.Team) (*model.Team, *model.Response) {
 	m.ctrl.T.Helper()
@@ -398,6 +413,21 @@ func (mr *MockClientMockRecorder) DeleteOutgoingWebhook(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOutgoingWebhook", reflect.TypeOf((*MockClient)(nil).DeleteOutgoingWebhook), arg0)
 }
 
+// DeleteScheme mocks base method
+func (m *MockClient) DeleteScheme(arg0 string) (bool, *model.Response) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheme", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(*model.Response)
+	return ret0, ret1
+}
+
+// DeleteScheme indicates an expected call of DeleteScheme
+func (mr *MockClientMockRecorder) DeleteScheme(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheme", reflect.TypeOf((*MockClient)(nil).DeleteScheme), arg0)
+}
+
 // DemoteUserToGuest mocks base method
 func (m *MockClient) DemoteUserToGuest(arg0 string) (bool, *model.Response) {
 	m.ctrl.T.Helper()
@@ -608,6 +638,21 @@ func (mr *MockClientMockRecorder) GetChannelByNameIncludeDeleted(arg0, arg1, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelByNameIncludeDeleted", reflect.TypeOf((*MockClient)(nil).GetChannelByNameIncludeDeleted), arg0, arg1, arg2)
 }
 
+// GetChannelMember mocks base method
+func (m *MockClient) GetChannelMember(arg0, arg1, arg2 string) (*model.ChannelMember, *model.Response) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.ChannelMember)
+	ret1, _ := ret[1].(*model.Response)
+	return ret0, ret1
+}
+
+// GetChannelMember indicates an expected call of GetChannelMember
+func (mr *MockClientMockRecorder) GetChannelMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelMember", reflect.TypeOf((*MockClient)(nil).GetChannelMember), arg0, arg1, arg2)
+}
+
 // GetChannelMembers mocks base method
 func (m *MockClient) GetChannelMembers(arg0 string, arg1, arg2 int, arg3 string) (*model.ChannelMembers, *model.Response) {
 	m.ctrl.T.Helper()
@@ -623,6 +668,21 @@ func (mr *MockClientMockRecorder) GetChannelMembers(arg0, arg1, arg2, arg3 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelMembers", reflect.TypeOf((*MockClient)(nil).GetChannelMembers), arg0, arg1, arg2, arg3)
 }
 
+// GetChannelsForScheme mocks base method
+func (m *MockClient) GetChannelsForScheme(arg0 string, arg1, arg2 int) (model.ChannelList, *model.Response) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelsForScheme", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.ChannelList)
+	ret1, _ := ret[1].(*model.Response)
+	return ret0, ret1
+}
+
+// GetChannelsForScheme indicates an expected call of GetChannelsForScheme
+func (mr *MockClientMockRecorder) GetChannelsForScheme(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelsForScheme", reflect.TypeOf((*MockClient)(nil).GetChannelsForScheme), arg0, arg1, arg2)
+}
+
 // GetChannelsForTeamForUser mocks base method
 func (m *MockClient) GetChannelsForTeamForUser(arg0, arg1 string, arg2 bool, arg3 string) ([]*model.Channel, *model.Response) {
 	m.ctrl.T.Helper()
@@ -1044,6 +1104,51 @@ func (mr *MockClientMockRecorder) GetRoleByName(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleByName", reflect.TypeOf((*MockClient)(nil).GetRoleByName), arg0)
 }
 
+// GetRolesByNames mocks base method
+func (m *MockClient) GetRolesByNames(arg0 []string) ([]*model.Role, *model.Response) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRolesByNames", arg0)
+	ret0, _ := ret[0].([]*model.Role)
+	ret1, _ := ret[1].(*model.Response)
+	return ret0, ret1
+}
+
+// GetRolesByNames indicates an expected call of GetRolesByNames
+func (mr *MockClientMockRecorder) GetRolesByNames(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRolesByNames", reflect.TypeOf((*MockClient)(nil).GetRolesByNames), arg0)
+}
+
+// GetScheme mocks base method
+func (m *MockClient) GetScheme(arg0 string) (*model.Scheme, *model.Response) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheme", arg0)
+	ret0, _ := ret[0].(*model.Scheme)
+	ret1, _ := ret[1].(*model.Response)
+	return ret0, ret1
+}
+
+// GetScheme indicates an expected call of GetScheme
+func (mr *MockClientMockRecorder) GetScheme(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheme", reflect.TypeOf((*MockClient)(nil).GetScheme), arg0)
+}
+
+// GetSchemes mocks base method
+func (m *MockClient) GetSchemes(arg0 string, arg1, arg2 int) ([]*model.Scheme, *model.Response) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchemes", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.Scheme)
+	ret1, _ := ret[1].(*model.Response)
+	return ret0, ret1
+}
+
+// GetSchemes indicates an expected call of GetSchemes
+func (mr *MockClientMockRecorder) GetSchemes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchemes", reflect.TypeOf((*MockClient)(nil).GetSchemes), arg0, arg1, arg2)
+}
+
 // GetServerBusy mocks base method
 func (m *MockClient) GetServerBusy() (*model.ServerBusyState, *model.Response) {
 	m.ctrl.T.Helper()
@@ -1089,6 +1194,36 @@ func (mr *MockClientMockRecorder) GetTeamByName(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamByName", reflect.TypeOf((*MockClient)(nil).GetTeamByName), arg0, arg1)
 }
 
+// GetTeamMember mocks base method
+func (m *MockClient) GetTeamMember(arg0, arg1, arg2 string) (*model.TeamMember, *model.Response) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamMember", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.TeamMember)
+	ret1, _ := ret[1].(*model.Response)
+	return ret0, ret1
+}
+
+// GetTeamMember indicates an expected call of GetTeamMember
+func (mr *MockClientMockRecorder) GetTeamMember(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamMember", reflect.TypeOf((*MockClient)(nil).GetTeamMember), arg0, arg1, arg2)
+}
+
+// GetTeamsForScheme mocks base method
+func (m *MockClient) GetTeamsForScheme(arg0 string, arg1, arg2 int) ([]*model.Team, *model.Response) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamsForScheme", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*model.Team)
+	ret1, _ := ret[1].(*model.Response)
+	return ret0, ret1
+}
+
+// GetTeamsForScheme indicates an expected call of GetTeamsForScheme
+func (mr *MockClientMockRecorder) GetTeamsForScheme(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamsForScheme", reflect.TypeOf((*MockClient)(nil).GetTeamsForScheme), arg0, arg1, arg2)
+}
+
 // GetUpload mocks base method
 func (m *MockClient) GetUpload(arg0 string) (*model.UploadSession, *model.Response) {
 	m.ctrl.T.Helper()
@@ -1464,6 +1599,21 @@ func (mr *MockClientMockRecorder) PatchRole(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchRole", reflect.TypeOf((*MockClient)(nil).PatchRole), arg0, arg1)
 }
 
+// PatchScheme mocks base method
+func (m *MockClient) PatchScheme(arg0 string, arg1 *model.SchemePatch) (*model.Scheme, *model.Response) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchScheme", arg0, arg1)
+	ret0, _ := ret[0].(*model.Scheme)
+	ret1, _ := ret[1].(*model.Response)
+	return ret0, ret1
+}
+
+// PatchScheme indicates an expected call of PatchScheme
+func (mr *MockClientMockRecorder) PatchScheme(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchScheme", reflect.TypeOf((*MockClient)(nil).PatchScheme), arg0, arg1)
+}
+
 // PatchTeam mocks base method
 func (m *MockClient) PatchTeam(arg0 string, arg1 *model.TeamPatch) (*model.Team, *model.Response) {
 	m.ctrl.T.Helper()
@@ -1794,6 +1944,21 @@ func (mr *MockClientMockRecorder) UpdateChannelPrivacy(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChannelPrivacy", reflect.TypeOf((*MockClient)(nil).UpdateChannelPrivacy), arg0, arg1)
 }
 
+// UpdateChannelScheme mocks base method
+func (m *MockClient) UpdateChannelScheme(arg0, arg1 string) (bool, *model.Response) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChannelScheme", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(*model.Response)
+	return ret0, ret1
+}
+
+// UpdateChannelScheme indicates an expected call of UpdateChannelScheme
+func (mr *MockClientMockRecorder) UpdateChannelScheme(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChannelScheme", reflect.TypeOf((*MockClient)(nil).UpdateChannelScheme), arg0, arg1)
+}
+
 // UpdateCommand mocks base method
 func (m *MockClient) UpdateCommand(arg0 *model.Command) (*model.Command, *model.Response) {
 	m.ctrl.T.Helper()
@@ -1884,6 +2049,21 @@ func (mr *MockClientMockRecorder) UpdateTeamPrivacy(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTeamPrivacy", reflect.TypeOf((*MockClient)(nil).UpdateTeamPrivacy), arg0, arg1)
 }
 
+// UpdateTeamScheme mocks base method
+func (m *MockClient) UpdateTeamScheme(arg0, arg1 string) (bool, *model.Response) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTeamScheme", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(*model.Response)
+	return ret0, ret1
+}
+
+// UpdateTeamScheme indicates an expected call of UpdateTeamScheme
+func (mr *MockClientMockRecorder) UpdateTeamScheme(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTeamScheme", reflect.TypeOf((*MockClient)(nil).UpdateTeamScheme), arg0, arg1)
+}
+
 // UpdateUser mocks base method
 func (m *MockClient) UpdateUser(arg0 *model.User) (*model.User, *model.Response) {
 	m.ctrl.T.Helper()
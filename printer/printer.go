@@ -9,22 +9,93 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync"
 	"text/template"
 )
 
 const (
-	FormatPlain = "plain"
-	FormatJSON  = "json"
+	FormatPlain  = "plain"
+	FormatJSON   = "json"
+	FormatTable  = "table"
+	FormatCSV    = "csv"
+	FormatTSV    = "tsv"
+	FormatYAML   = "yaml"
+	FormatNDJSON = "ndjson"
 )
 
 type Printer struct { //nolint
 	writer  io.Writer
 	eWriter io.Writer
 
-	Format     string
-	Single     bool
-	Lines      []interface{}
-	ErrorLines []interface{}
+	// mu protects the accumulated lines and the writers, as commands
+	// can print from several goroutines
+	mu sync.Mutex
+
+	Format       string
+	Single       bool
+	Instance     string
+	Columns      []string
+	Query        Query
+	Template     *template.Template
+	Lines        []interface{}
+	ErrorLines   []interface{}
+	WarningLines []interface{}
+}
+
+// ErrorObject is the representation of the errors and warnings printed
+// to the stderr when using the json or ndjson formats
+type ErrorObject struct {
+	Code     string `json:"code"`
+	Entity   string `json:"entity,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Message  string `json:"message"`
+	Status   int    `json:"status,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+const (
+	// ErrorCodeGeneric is the code of the errors printed without
+	// more information about their cause
+	ErrorCodeGeneric = "error"
+	// ErrorCodeWarning is the code of the warnings
+	ErrorCodeWarning = "warning"
+)
+
+// instanceElement is an element printed while the instance is set.
+// Its JSON representation is the one of the element with the instance
+// as its first key, or an object with the instance and the element as
+// its value if the element is not an object
+type instanceElement struct {
+	Instance string
+	Value    interface{}
+}
+
+func (e *instanceElement) MarshalJSON() ([]byte, error) {
+	instance, err := json.Marshal(e.Instance)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(e.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`{"instance":`)
+	b.Write(instance)
+	switch {
+	case bytes.Equal(value, []byte("{}")):
+	case len(value) > 0 && value[0] == '{':
+		b.WriteString(",")
+		b.Write(value[1 : len(value)-1])
+	default:
+		b.WriteString(`,"` + valueColumn + `":`)
+		b.Write(value)
+	}
+	b.WriteString("}")
+	return b.Bytes(), nil
 }
 
 var printer Printer
@@ -39,6 +110,11 @@ func SetFormat(t string) {
 	printer.Format = t
 }
 
+// GetFormat returns the format of the printer
+func GetFormat() string {
+	return printer.Format
+}
+
 // SetSingle sets the single flag on the printer. If this flag is set, the
 // printer will check the size of stored elements before printing, and
 // if there is only one, it will be printed on its own instead of
@@ -47,21 +123,138 @@ func SetSingle(single bool) {
 	printer.Single = single
 }
 
+// SetInstance sets the name of the instance the elements printed from
+// now on belong to, when a command runs against several of them. The
+// plain lines and the messages are prefixed with the name, and the
+// rest of formats add it to each element as the instance key. An
+// empty name disables it
+func SetInstance(name string) {
+	printer.mu.Lock()
+	defer printer.mu.Unlock()
+
+	printer.Instance = name
+}
+
+// SetColumns sets the fields that the table, csv, tsv and yaml
+// formats include for each element. Each of the columns can contain
+// several comma separated names and can be a dotted path to a nested
+// field
+func SetColumns(columns []string) {
+	printer.Columns = []string{}
+	for _, column := range columns {
+		for _, name := range strings.Split(column, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				printer.Columns = append(printer.Columns, name)
+			}
+		}
+	}
+}
+
+// SetQuery sets a JSONPath expression that the printer applies to
+// each element before printing it. Only the values that match the
+// expression are printed. An empty expression disables the query
+func SetQuery(expr string) error {
+	printer.Query = nil
+	if expr == "" {
+		return nil
+	}
+
+	q, err := ParseQuery(expr)
+	if err != nil {
+		return err
+	}
+	printer.Query = q
+	return nil
+}
+
+// SetTemplate sets a template that the printer uses to render each
+// element, or each of the values selected by the query, before
+// printing it. An empty template disables it
+func SetTemplate(templateString string) error {
+	printer.Template = nil
+	if templateString == "" {
+		return nil
+	}
+
+	t, err := template.New("").Parse(templateString)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	printer.Template = t
+	return nil
+}
+
 // PrintT prints an element. Depending on the format, the element can be
 // formatted and printed as a structure or used to populate the
-// template
+// template. If the printer has a query or a template set, they are
+// applied to the element first, and the elements they can't be applied
+// to are reported as warnings, as the command didn't fail
 func PrintT(templateString string, v interface{}) {
+	printer.mu.Lock()
+	defer printer.mu.Unlock()
+
+	if printer.Query == nil && printer.Template == nil {
+		printValue(templateString, v)
+		return
+	}
+
+	values := []interface{}{v}
+	if printer.Query != nil {
+		var err error
+		if values, err = printer.Query.Apply(v); err != nil {
+			printWarning(fmt.Sprintf("Can't apply the query to the element: %s", err))
+			return
+		}
+	}
+
+	for _, value := range values {
+		if printer.Template != nil {
+			var tpl bytes.Buffer
+			if err := printer.Template.Execute(&tpl, value); err != nil {
+				printWarning(fmt.Sprintf("Can't print the element using the provided template: %s", err))
+				continue
+			}
+			value = tpl.String()
+		}
+		printValue("", value)
+	}
+}
+
+// printValue prints an element in the current format. For the plain
+// format, an empty template means that the element is printed as
+// is. The plain and ndjson formats write the element right away,
+// and ndjson doesn't keep it in the printer lines so long listings
+// can be streamed without accumulating them in memory
+func printValue(templateString string, v interface{}) {
+	if printer.Instance != "" && printer.Format != FormatPlain {
+		v = &instanceElement{Instance: printer.Instance, Value: v}
+	}
+
 	switch printer.Format {
 	case FormatPlain:
+		if templateString == "" {
+			line := cell(v)
+			printer.Lines = append(printer.Lines, line)
+			fmt.Fprintln(printer.writer, prefixInstance(line))
+			return
+		}
+
 		t := template.Must(template.New("").Parse(templateString))
 		var tpl bytes.Buffer
 		if err := t.Execute(&tpl, v); err != nil {
-			PrintError("Can't print the message using the provided template: " + templateString)
+			printError("Can't print the message using the provided template: " + templateString)
 		}
 		tplString := tpl.String()
 		printer.Lines = append(printer.Lines, tplString)
-		fmt.Fprintln(printer.writer, tplString)
-	case FormatJSON:
+		fmt.Fprintln(printer.writer, prefixInstance(tplString))
+	case FormatNDJSON:
+		b, err := json.Marshal(v)
+		if err != nil {
+			printError(fmt.Sprintf("Can't print the element: %s", err))
+			return
+		}
+		fmt.Fprintln(printer.writer, string(b))
+	case FormatJSON, FormatTable, FormatCSV, FormatTSV, FormatYAML:
 		printer.Lines = append(printer.Lines, v)
 	}
 }
@@ -75,7 +268,11 @@ func Print(v interface{}) {
 
 // Flush writes the elements accumulated in the printer
 func Flush() {
-	if printer.Format == FormatJSON {
+	printer.mu.Lock()
+	defer printer.mu.Unlock()
+
+	switch printer.Format {
+	case FormatJSON:
 		var b []byte
 		switch {
 		case printer.Single && len(printer.Lines) == 0:
@@ -88,6 +285,18 @@ func Flush() {
 
 		fmt.Fprintln(printer.writer, string(b))
 		printer.Lines = []interface{}{}
+	case FormatTable:
+		flushTable(printer.writer, printer.Lines)
+		printer.Lines = []interface{}{}
+	case FormatCSV:
+		flushSeparated(printer.writer, printer.Lines, ',')
+		printer.Lines = []interface{}{}
+	case FormatTSV:
+		flushSeparated(printer.writer, printer.Lines, '\t')
+		printer.Lines = []interface{}{}
+	case FormatYAML:
+		flushYAML(printer.writer, printer.Lines, printer.Single)
+		printer.Lines = []interface{}{}
 	}
 }
 
@@ -95,6 +304,7 @@ func Flush() {
 func Clean() {
 	printer.Lines = []interface{}{}
 	printer.ErrorLines = []interface{}{}
+	printer.WarningLines = []interface{}{}
 }
 
 // GetLines returns the printer's accumulated lines
@@ -107,8 +317,70 @@ func GetErrorLines() []interface{} {
 	return printer.ErrorLines
 }
 
+// GetWarningLines returns the printer's accumulated warning lines
+func GetWarningLines() []interface{} {
+	return printer.WarningLines
+}
+
 // PrintError prints to the stderr.
 func PrintError(msg string) {
+	printer.mu.Lock()
+	defer printer.mu.Unlock()
+
+	printError(msg)
+}
+
+// PrintErrorObject prints an error to the stderr. In the json and
+// ndjson formats the error object is printed, and in the rest of
+// formats the message
+func PrintErrorObject(msg string, e *ErrorObject) {
+	printer.mu.Lock()
+	defer printer.mu.Unlock()
+
 	printer.ErrorLines = append(printer.ErrorLines, msg)
-	fmt.Fprintln(printer.eWriter, msg)
+	writeError(msg, e)
+}
+
+// prefixInstance prefixes each of the lines of a message with the name
+// of the instance, if it is set
+func prefixInstance(msg string) string {
+	if printer.Instance == "" {
+		return msg
+	}
+
+	lines := strings.Split(msg, "\n")
+	for i := range lines {
+		lines[i] = printer.Instance + ": " + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PrintWarning prints a message to the stderr that, unlike the ones
+// printed with PrintError, doesn't mean that the command failed
+func PrintWarning(msg string) {
+	printer.mu.Lock()
+	defer printer.mu.Unlock()
+
+	printWarning(msg)
+}
+
+func printWarning(msg string) {
+	printer.WarningLines = append(printer.WarningLines, msg)
+	writeError(msg, &ErrorObject{Code: ErrorCodeWarning, Message: msg})
+}
+
+func printError(msg string) {
+	printer.ErrorLines = append(printer.ErrorLines, msg)
+	writeError(msg, &ErrorObject{Code: ErrorCodeGeneric, Message: msg})
+}
+
+func writeError(msg string, e *ErrorObject) {
+	if printer.Format == FormatJSON || printer.Format == FormatNDJSON {
+		e.Instance = printer.Instance
+		if b, err := json.Marshal(e); err == nil {
+			fmt.Fprintln(printer.eWriter, string(b))
+			return
+		}
+	}
+	fmt.Fprintln(printer.eWriter, prefixInstance(msg))
 }
@@ -79,3 +79,384 @@ func TestFlush(t *testing.T) {
 		assert.Empty(t, GetLines(), 0)
 	})
 }
+
+func TestFlushTabular(t *testing.T) {
+	type user struct {
+		ID       string            `json:"id"`
+		Username string            `json:"username"`
+		Email    string            `json:"email"`
+		Props    map[string]string `json:"props"`
+	}
+
+	users := []*user{
+		{ID: "1", Username: "john.doe", Email: "john@example.com", Props: map[string]string{"theme": "dark"}},
+		{ID: "2", Username: "jane.doe", Email: "jane@example.com"},
+	}
+
+	defer func() {
+		printer.Columns = nil
+		printer.Single = false
+	}()
+
+	t.Run("should print a table with all the fields", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		printer.Format = FormatTable
+		SetColumns(nil)
+		Clean()
+
+		for _, u := range users {
+			PrintT("", u)
+		}
+		Flush()
+
+		expected := "ID   USERNAME   EMAIL              PROPS\n" +
+			"1    john.doe   john@example.com   {\"theme\":\"dark\"}\n" +
+			"2    jane.doe   jane@example.com   \n"
+		assert.Equal(t, expected, out.String())
+		assert.Empty(t, GetLines())
+	})
+
+	t.Run("should print the selected columns as csv", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		printer.Format = FormatCSV
+		SetColumns([]string{"Username,email", "props.theme"})
+		Clean()
+
+		for _, u := range users {
+			PrintT("", u)
+		}
+		Flush()
+
+		assert.Equal(t, "Username,email,props.theme\njohn.doe,john@example.com,dark\njane.doe,jane@example.com,\n", out.String())
+	})
+
+	t.Run("should print the selected columns as tsv", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		printer.Format = FormatTSV
+		SetColumns([]string{"id", "username"})
+		Clean()
+
+		for _, u := range users {
+			PrintT("", u)
+		}
+		Flush()
+
+		assert.Equal(t, "id\tusername\n1\tjohn.doe\n2\tjane.doe\n", out.String())
+	})
+
+	t.Run("should print strings in a value column", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		printer.Format = FormatCSV
+		SetColumns(nil)
+		Clean()
+
+		Print("test string")
+		Flush()
+
+		assert.Equal(t, "value\ntest string\n", out.String())
+	})
+
+	t.Run("should print a single element as yaml", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		printer.Format = FormatYAML
+		printer.Single = true
+		SetColumns(nil)
+		Clean()
+
+		PrintT("", users[1])
+		Flush()
+
+		assert.Equal(t, "id: \"2\"\nusername: jane.doe\nemail: jane@example.com\nprops: null\n", out.String())
+	})
+
+	t.Run("should print the selected columns as yaml", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		printer.Format = FormatYAML
+		printer.Single = false
+		SetColumns([]string{"username"})
+		Clean()
+
+		for _, u := range users {
+			PrintT("", u)
+		}
+		Flush()
+
+		assert.Equal(t, "- username: john.doe\n- username: jane.doe\n", out.String())
+	})
+}
+
+func TestParseQuery(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expr     string
+		expected Query
+	}{
+		{"root", "$", Query{}},
+		{"field", "$.id", Query{{field: "id"}}},
+		{"field without root", "id", Query{{field: "id"}}},
+		{"nested fields", "$.notify_props.email", Query{{field: "notify_props"}, {field: "email"}}},
+		{"quoted field", "$['notify_props']['email']", Query{{field: "notify_props"}, {field: "email"}}},
+		{"wildcards", "$[*].*", Query{{wildcard: true}, {wildcard: true}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := ParseQuery(tc.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, q)
+		})
+	}
+
+	t.Run("index", func(t *testing.T) {
+		q, err := ParseQuery("$.roles[-1]")
+		assert.NoError(t, err)
+		assert.Len(t, q, 2)
+		assert.Equal(t, -1, *q[1].index)
+	})
+
+	for _, expr := range []string{"$.", "$[0", "$[abc]", "$..id", "$!"} {
+		t.Run("invalid "+expr, func(t *testing.T) {
+			_, err := ParseQuery(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPrintTWithQuery(t *testing.T) {
+	type user struct {
+		ID       string   `json:"id"`
+		Username string   `json:"username"`
+		Roles    []string `json:"roles"`
+	}
+
+	users := []*user{
+		{ID: "1", Username: "john.doe", Roles: []string{"system_user", "system_admin"}},
+		{ID: "2", Username: "jane.doe", Roles: []string{"system_user"}},
+	}
+
+	defer func() {
+		printer.Query = nil
+		printer.Template = nil
+	}()
+
+	t.Run("should print the values that match the query", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		printer.Format = FormatPlain
+		assert.NoError(t, SetQuery("$.roles[*]"))
+		assert.NoError(t, SetTemplate(""))
+		Clean()
+
+		for _, u := range users {
+			PrintT("{{.Username}}", u)
+		}
+		Flush()
+
+		assert.Equal(t, "system_user\nsystem_admin\nsystem_user\n", out.String())
+	})
+
+	t.Run("should print the query results as json", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		printer.Format = FormatJSON
+		assert.NoError(t, SetQuery("$.id"))
+		assert.NoError(t, SetTemplate(""))
+		Clean()
+
+		for _, u := range users {
+			PrintT("{{.Username}}", u)
+		}
+		Flush()
+
+		assert.Equal(t, "[\n  \"1\",\n  \"2\"\n]\n", out.String())
+	})
+
+	t.Run("should render each element with the template", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		printer.Format = FormatPlain
+		assert.NoError(t, SetQuery(""))
+		assert.NoError(t, SetTemplate("{{.ID}}={{.Username}}"))
+		Clean()
+
+		for _, u := range users {
+			PrintT("{{.Username}}", u)
+		}
+		Flush()
+
+		assert.Equal(t, "1=john.doe\n2=jane.doe\n", out.String())
+	})
+
+	t.Run("should render the query results with the template", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		printer.Format = FormatPlain
+		assert.NoError(t, SetQuery("$.roles"))
+		assert.NoError(t, SetTemplate("{{len .}}"))
+		Clean()
+
+		for _, u := range users {
+			PrintT("{{.Username}}", u)
+		}
+		Flush()
+
+		assert.Equal(t, "2\n1\n", out.String())
+	})
+
+	t.Run("should report the elements the template can't render as warnings", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		printer.eWriter = &bytes.Buffer{}
+		printer.Format = FormatPlain
+		assert.NoError(t, SetQuery(""))
+		assert.NoError(t, SetTemplate("{{.Email}}"))
+		Clean()
+
+		PrintT("{{.Username}}", users[0])
+		Flush()
+
+		assert.Empty(t, out.String())
+		assert.Empty(t, GetErrorLines())
+		assert.Len(t, GetWarningLines(), 1)
+		assert.Contains(t, GetWarningLines()[0], "Can't print the element using the provided template")
+	})
+
+	t.Run("should fail with an invalid template", func(t *testing.T) {
+		assert.Error(t, SetTemplate("{{.ID"))
+	})
+}
+
+func TestPrintTNDJSON(t *testing.T) {
+	printer.Format = FormatNDJSON
+
+	t.Run("should write each element in its own line", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		Clean()
+
+		ts := struct {
+			ID int `json:"id"`
+		}{ID: 123}
+
+		PrintT("{{.ID}}", ts)
+		assert.Equal(t, "{\"id\":123}\n", out.String())
+
+		Print("test string")
+		assert.Equal(t, "{\"id\":123}\n\"test string\"\n", out.String())
+		assert.Empty(t, GetLines())
+
+		Flush()
+		assert.Equal(t, "{\"id\":123}\n\"test string\"\n", out.String())
+	})
+}
+
+func TestPrintErrors(t *testing.T) {
+	t.Run("should print the messages in the plain format", func(t *testing.T) {
+		printer.Format = FormatPlain
+		errOut := &bytes.Buffer{}
+		printer.eWriter = errOut
+		Clean()
+
+		PrintError("error message")
+		PrintWarning("warning message")
+		assert.Equal(t, "error message\nwarning message\n", errOut.String())
+		assert.Equal(t, []interface{}{"error message"}, GetErrorLines())
+		assert.Equal(t, []interface{}{"warning message"}, GetWarningLines())
+	})
+
+	t.Run("should print error objects in the json format", func(t *testing.T) {
+		printer.Format = FormatJSON
+		errOut := &bytes.Buffer{}
+		printer.eWriter = errOut
+		Clean()
+
+		PrintWarning("warning message")
+		PrintErrorObject("user not found", &ErrorObject{Code: "not_found", Entity: "user", ID: "userID", Message: "user not found"})
+		assert.Equal(t, "{\"code\":\"warning\",\"message\":\"warning message\"}\n"+
+			"{\"code\":\"not_found\",\"entity\":\"user\",\"id\":\"userID\",\"message\":\"user not found\"}\n", errOut.String())
+		assert.Equal(t, []interface{}{"user not found"}, GetErrorLines())
+	})
+}
+
+func TestPrintInstance(t *testing.T) {
+	type team struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	defer func() {
+		SetInstance("")
+		printer.Single = false
+	}()
+
+	t.Run("should prefix the plain lines and messages with the instance", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+		printer.writer = out
+		printer.eWriter = errOut
+		printer.Format = FormatPlain
+		Clean()
+
+		SetInstance("prod-eu")
+		PrintT("team {{.Name}}\nid {{.ID}}", &team{ID: "1", Name: "sales"})
+		PrintError("error message")
+		SetInstance("prod-us")
+		Print("test string")
+		SetInstance("")
+		Print("no instance")
+
+		assert.Equal(t, "prod-eu: team sales\nprod-eu: id 1\nprod-us: test string\nno instance\n", out.String())
+		assert.Equal(t, "prod-eu: error message\n", errOut.String())
+	})
+
+	t.Run("should add the instance to the elements in the json format", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		errOut := &bytes.Buffer{}
+		printer.writer = out
+		printer.eWriter = errOut
+		printer.Format = FormatJSON
+		printer.Single = true
+		Clean()
+
+		SetInstance("prod-eu")
+		PrintT("", &team{ID: "1", Name: "sales"})
+		PrintWarning("warning message")
+		SetInstance("prod-us")
+		Print("test string")
+		SetInstance("")
+		Flush()
+
+		expected := "[\n" +
+			"  {\n    \"instance\": \"prod-eu\",\n    \"id\": \"1\",\n    \"name\": \"sales\"\n  },\n" +
+			"  {\n    \"instance\": \"prod-us\",\n    \"value\": \"test string\"\n  }\n" +
+			"]\n"
+		assert.Equal(t, expected, out.String())
+		assert.Equal(t, "{\"code\":\"warning\",\"message\":\"warning message\",\"instance\":\"prod-eu\"}\n", errOut.String())
+	})
+
+	t.Run("should add the instance column to the tables", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		printer.writer = out
+		printer.Format = FormatTable
+		SetColumns(nil)
+		Clean()
+
+		SetInstance("prod-eu")
+		PrintT("", &team{ID: "1", Name: "sales"})
+		SetInstance("prod-us")
+		PrintT("", &team{ID: "2", Name: "support"})
+		SetInstance("")
+		Flush()
+
+		expected := "INSTANCE   ID   NAME\n" +
+			"prod-eu    1    sales\n" +
+			"prod-us    2    support\n"
+		assert.Equal(t, expected, out.String())
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package printer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// queryStep is a single step of a query. It selects either a field of
+// an object, an element of an array or, if wildcard is set, all the
+// children of the current value
+type queryStep struct {
+	field    string
+	index    *int
+	wildcard bool
+}
+
+// Query is a parsed JSONPath expression. Only a subset of the syntax
+// is supported: the root "$", fields as ".name" or "['name']",
+// indexes as "[0]" or "[-1]" and wildcards as "[*]" or ".*"
+type Query []queryStep
+
+// ParseQuery parses a JSONPath expression. The leading "$" is optional
+func ParseQuery(expr string) (Query, error) {
+	q := Query{}
+	rest := strings.TrimSpace(expr)
+	if strings.HasPrefix(rest, "$") {
+		rest = rest[1:]
+	} else if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+
+			switch name {
+			case "":
+				return nil, fmt.Errorf("invalid query %q: empty field name", expr)
+			case "*":
+				q = append(q, queryStep{wildcard: true})
+			default:
+				q = append(q, queryStep{field: name})
+			}
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("invalid query %q: unclosed bracket", expr)
+			}
+			selector := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			switch {
+			case selector == "*":
+				q = append(q, queryStep{wildcard: true})
+			case len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0]:
+				q = append(q, queryStep{field: selector[1 : len(selector)-1]})
+			default:
+				index, err := strconv.Atoi(selector)
+				if err != nil {
+					return nil, fmt.Errorf("invalid query %q: %q is not a valid index", expr, selector)
+				}
+				q = append(q, queryStep{index: &index})
+			}
+		default:
+			return nil, fmt.Errorf("invalid query %q: unexpected character %q", expr, rest[0])
+		}
+	}
+
+	return q, nil
+}
+
+// Apply runs the query against the JSON representation of an element
+// and returns the values that match it
+func (q Query) Apply(v interface{}) ([]interface{}, error) {
+	value, err := toJSONValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []interface{}{value}
+	for _, step := range q {
+		next := []interface{}{}
+		for _, current := range values {
+			next = append(next, step.apply(current)...)
+		}
+		values = next
+	}
+
+	return values, nil
+}
+
+func (s queryStep) apply(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if s.wildcard {
+			children := []interface{}{}
+			for _, key := range sortedKeys(v) {
+				children = append(children, v[key])
+			}
+			return children
+		}
+		if s.field == "" {
+			return nil
+		}
+		if child, ok := v[s.field]; ok {
+			return []interface{}{child}
+		}
+		for key, child := range v {
+			if strings.EqualFold(key, s.field) {
+				return []interface{}{child}
+			}
+		}
+	case []interface{}:
+		if s.wildcard {
+			return v
+		}
+		if s.index == nil {
+			return nil
+		}
+		index := *s.index
+		if index < 0 {
+			index += len(v)
+		}
+		if index >= 0 && index < len(v) {
+			return []interface{}{v[index]}
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,354 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package printer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v2"
+)
+
+// valueColumn is the column used for elements that are not objects,
+// like the plain strings sent through Print
+const valueColumn = "value"
+
+// defaultColumns contains the columns shown for known entity types
+// when the user doesn't select them explicitly. The keys are the
+// type names as returned by reflect, so the printer doesn't need to
+// depend on the model package
+var defaultColumns = map[string][]string{
+	"model.User":            {"id", "username", "email", "roles"},
+	"model.Team":            {"id", "name", "display_name", "type"},
+	"model.Channel":         {"id", "team_id", "name", "display_name", "type"},
+	"model.Bot":             {"user_id", "username", "display_name", "owner_id"},
+	"model.Job":             {"id", "type", "status", "create_at"},
+	"model.Command":         {"id", "team_id", "trigger", "display_name"},
+	"model.IncomingWebhook": {"id", "channel_id", "display_name"},
+	"model.OutgoingWebhook": {"id", "team_id", "display_name", "trigger_words"},
+	"model.Role":            {"id", "name", "display_name", "scheme_managed"},
+	"model.Scheme":          {"id", "name", "display_name", "scope"},
+	"model.UserAccessToken": {"id", "user_id", "description", "is_active"},
+}
+
+// row is an element converted to its JSON representation, keeping
+// the order of its top level keys
+type row struct {
+	keys   []string
+	values map[string]interface{}
+	scalar bool
+}
+
+// newRow converts an element into a row using its JSON
+// representation, so the column names are the same ones used by the
+// json format
+func newRow(v interface{}) (*row, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := decodeJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := value.(map[string]interface{})
+	if !ok {
+		return &row{keys: []string{valueColumn}, values: map[string]interface{}{valueColumn: value}, scalar: true}, nil
+	}
+
+	// the keys are read again from the encoded element as the
+	// decoded map doesn't keep their order
+	keys := []string{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	depth := 0
+	expectKey := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case json.Delim:
+			if t == '{' || t == '[' {
+				depth++
+			} else {
+				depth--
+			}
+			expectKey = depth == 1
+		default:
+			if depth != 1 {
+				continue
+			}
+			if expectKey {
+				keys = append(keys, t.(string))
+			}
+			expectKey = !expectKey
+		}
+	}
+
+	return &row{keys: keys, values: values}, nil
+}
+
+// toJSONValue converts an element into the generic value that
+// results from decoding its JSON representation
+func toJSONValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(b)
+}
+
+// decodeJSON decodes a JSON document keeping its numbers as
+// json.Number, so big integers like timestamps are not converted to
+// floats
+func decodeJSON(b []byte) (interface{}, error) {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// lookup returns the value of a column, which can be a dotted path to
+// a nested field. If there is no exact match for a key, it is
+// compared without taking the case into account
+func (r *row) lookup(column string) interface{} {
+	var current interface{} = r.values
+	for _, key := range strings.Split(column, ".") {
+		values, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		value, ok := values[key]
+		if !ok {
+			for k, v := range values {
+				if strings.EqualFold(k, key) {
+					value = v
+					break
+				}
+			}
+		}
+		current = value
+	}
+
+	return current
+}
+
+// cell formats a value to be shown as a table, csv or tsv cell, or
+// as a plain line. Strings are shown without quotes and objects as
+// compact JSON
+func cell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprintf("%t", v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// typeName returns the name of the type of an element, ignoring
+// pointers
+func typeName(v interface{}) string {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
+
+// columnsFor returns the columns to print for a set of lines. The
+// columns set in the printer take precedence, then the default ones
+// for the type of the first line, and as a last resort all the keys
+// present in the rows in the order they appear
+func columnsFor(lines []interface{}, rows []*row) []string {
+	if len(printer.Columns) > 0 {
+		return printer.Columns
+	}
+
+	if len(lines) > 0 {
+		if element, ok := lines[0].(*instanceElement); ok {
+			if columns, ok := defaultColumns[typeName(element.Value)]; ok {
+				return append([]string{"instance"}, columns...)
+			}
+		} else if columns, ok := defaultColumns[typeName(lines[0])]; ok {
+			return columns
+		}
+	}
+
+	columns := []string{}
+	seen := map[string]bool{}
+	for _, r := range rows {
+		for _, key := range r.keys {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+	}
+	return columns
+}
+
+// toRows converts the lines accumulated in the printer into rows,
+// skipping the ones that can't be represented
+func toRows(lines []interface{}) ([]interface{}, []*row) {
+	kept := []interface{}{}
+	rows := []*row{}
+	for _, line := range lines {
+		if line == nil {
+			continue
+		}
+
+		r, err := newRow(line)
+		if err != nil {
+			printError(fmt.Sprintf("Can't print the element: %s", err))
+			continue
+		}
+		kept = append(kept, line)
+		rows = append(rows, r)
+	}
+	return kept, rows
+}
+
+// flushTable writes the lines as a table with aligned columns and a
+// header with the column names
+func flushTable(w io.Writer, lines []interface{}) {
+	lines, rows := toRows(lines)
+	if len(rows) == 0 {
+		return
+	}
+	columns := columnsFor(lines, rows)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, r := range rows {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			// tabs and new lines would break the alignment
+			cells[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(cell(r.lookup(column)))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	tw.Flush()
+}
+
+// flushSeparated writes the lines as comma or tab separated values,
+// with a first record containing the column names
+func flushSeparated(w io.Writer, lines []interface{}, separator rune) {
+	lines, rows := toRows(lines)
+	if len(rows) == 0 {
+		return
+	}
+	columns := columnsFor(lines, rows)
+
+	cw := csv.NewWriter(w)
+	cw.Comma = separator
+	_ = cw.Write(columns)
+	for _, r := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = cell(r.lookup(column))
+		}
+		_ = cw.Write(record)
+	}
+	cw.Flush()
+}
+
+// flushYAML writes the lines as a YAML document. If the user selected
+// columns, only those fields are included for each element
+func flushYAML(w io.Writer, lines []interface{}, single bool) {
+	lines, rows := toRows(lines)
+
+	values := make([]interface{}, len(rows))
+	for i, r := range rows {
+		if r.scalar {
+			values[i] = yamlValue(r.values[valueColumn])
+			continue
+		}
+
+		columns := printer.Columns
+		if len(columns) == 0 {
+			columns = r.keys
+		}
+
+		item := yaml.MapSlice{}
+		for _, column := range columns {
+			item = append(item, yaml.MapItem{Key: column, Value: yamlValue(r.lookup(column))})
+		}
+		values[i] = item
+	}
+
+	var out interface{} = values
+	switch {
+	case single && len(lines) == 0:
+		return
+	case single && len(lines) == 1:
+		out = values[0]
+	}
+
+	b, err := yaml.Marshal(out)
+	if err != nil {
+		printError(fmt.Sprintf("Can't print the elements: %s", err))
+		return
+	}
+	fmt.Fprint(w, string(b))
+}
+
+// yamlValue converts the numbers decoded from the JSON representation
+// of an element so they are not written as strings or in exponent
+// notation
+func yamlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = yamlValue(item)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, item := range v {
+			s[i] = yamlValue(item)
+		}
+		return s
+	default:
+		return v
+	}
+}